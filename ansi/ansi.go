@@ -0,0 +1,187 @@
+// Package ansi interprets ANSI.SYS and ECMA-48 escape sequences into a virtual screen.
+//
+// The screen is a grid of cells, each holding a character and its display attributes.
+// Interpreting the cursor movements means text art renders the same on every terminal,
+// instead of relying on the terminal to obey the original escape sequences.
+package ansi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Columns is the default number of characters per line of the MS-DOS ANSI.SYS screen.
+const Columns = 80
+
+const (
+	esc = 0x1b // esc is the escape control code.
+	csi = '['  // csi is the control sequence introducer that follows an escape.
+)
+
+// ColorMode is the color space of a color value.
+type ColorMode uint8
+
+const (
+	Default ColorMode = iota // Default is the terminal default foreground or background.
+	Indexed                  // Indexed is a 4-bit or 8-bit palette index.
+	RGB                      // RGB is a 24-bit true color.
+)
+
+// Color is a foreground or background color.
+type Color struct {
+	Mode  ColorMode // Mode is the color space of the color.
+	Index uint8     // Index is the palette index, used by the indexed mode.
+	R     uint8     // R is the red value, used by the RGB mode.
+	G     uint8     // G is the green value, used by the RGB mode.
+	B     uint8     // B is the blue value, used by the RGB mode.
+}
+
+// Index returns an indexed palette color.
+func Index(i uint8) Color {
+	return Color{Mode: Indexed, Index: i}
+}
+
+// TrueColor returns a 24-bit RGB color.
+func TrueColor(r, g, b uint8) Color {
+	return Color{Mode: RGB, R: r, G: g, B: b}
+}
+
+// Flags are the character display attributes.
+type Flags uint16
+
+const (
+	Bold      Flags = 1 << iota // Bold or increased intensity.
+	Faint                       // Faint or decreased intensity.
+	Italic                      // Italic.
+	Underline                   // Underline.
+	Blink                       // Blink.
+	Inverse                     // Inverse or reverse video.
+	Conceal                     // Conceal or hidden text.
+	Strike                      // Strike or crossed-out text.
+)
+
+// Attr are the display attributes of a cell.
+type Attr struct {
+	FG    Color // FG is the foreground color.
+	BG    Color // BG is the background color.
+	Flags Flags // Flags are the display attributes.
+}
+
+// Has reports whether the attribute has the flag.
+func (a Attr) Has(f Flags) bool {
+	return a.Flags&f != 0
+}
+
+// Colors returns the foreground and background colors displayed using the MS-DOS conventions.
+// The terminal default colors become the light gray foreground and the black background.
+// Bold brightens a standard foreground color and inverse swaps the two colors.
+// When ice is true, blink brightens a standard background color, this is known as iCE colors.
+func (a Attr) Colors(ice bool) (Color, Color) {
+	const lightGray, black = 7, 0
+	fg, bg := a.FG, a.BG
+	if fg.Mode == Default {
+		fg = Index(lightGray)
+	}
+	if bg.Mode == Default {
+		bg = Index(black)
+	}
+	if a.Has(Bold) && fg.Mode == Indexed && fg.Index < classic {
+		fg.Index += classic
+	}
+	if ice && a.Has(Blink) && bg.Mode == Indexed && bg.Index < classic {
+		bg.Index += classic
+	}
+	if a.Has(Inverse) {
+		return bg, fg
+	}
+	return fg, bg
+}
+
+// SGR returns the select graphic rendition escape sequence for the attribute.
+// The sequence always resets the previous attributes.
+func (a Attr) SGR() string {
+	return string(rune(esc)) + "[" + strings.Join(a.Params(), ";") + "m"
+}
+
+// Params returns the select graphic rendition parameters for the attribute.
+func (a Attr) Params() []string {
+	p := []string{"0"}
+	flags := []struct {
+		f Flags
+		v string
+	}{
+		{Bold, "1"}, {Faint, "2"}, {Italic, "3"}, {Underline, "4"},
+		{Blink, "5"}, {Inverse, "7"}, {Conceal, "8"}, {Strike, "9"},
+	}
+	for _, x := range flags {
+		if a.Has(x.f) {
+			p = append(p, x.v)
+		}
+	}
+	if s := a.FG.params(false); s != "" {
+		p = append(p, s)
+	}
+	if s := a.BG.params(true); s != "" {
+		p = append(p, s)
+	}
+	return p
+}
+
+// params returns the select graphic rendition parameters for the color.
+func (c Color) params(bg bool) string {
+	switch c.Mode {
+	case Indexed:
+		base, bright, ext := fgBlack, fgBrightBlack, "38"
+		if bg {
+			base, bright, ext = bgBlack, bgBrightBlack, "48"
+		}
+		switch {
+		case c.Index < classic:
+			return strconv.Itoa(base + int(c.Index))
+		case c.Index < classic*2:
+			return strconv.Itoa(bright + int(c.Index) - classic)
+		}
+		return ext + ";5;" + strconv.Itoa(int(c.Index))
+	case RGB:
+		ext := "38"
+		if bg {
+			ext = "48"
+		}
+		return ext + ";2;" + strconv.Itoa(int(c.R)) + ";" +
+			strconv.Itoa(int(c.G)) + ";" + strconv.Itoa(int(c.B))
+	case Default:
+	}
+	return ""
+}
+
+// Cell is a single character position on the screen.
+type Cell struct {
+	Rune rune // Rune is the character, a zero value is an unused cell.
+	Attr Attr // Attr are the display attributes.
+}
+
+// Blank reports whether the cell displays nothing but the default background.
+func (c Cell) Blank() bool {
+	if c.Rune != 0 && c.Rune != ' ' {
+		return false
+	}
+	return c.Attr.BG.Mode == Default && !c.Attr.Has(Inverse)
+}
+
+// Contains reports whether the runes contain an ECMA-48 control sequence.
+func Contains(r ...rune) bool {
+	for i := 0; i+1 < len(r); i++ {
+		if r[i] == esc && r[i+1] == csi {
+			return true
+		}
+	}
+	return false
+}
+
+// Render interprets the escape sequences in the runes and returns the flattened screen.
+// The width is the number of columns of the screen, the Columns value is used when it is less than 1.
+func Render(width int, r ...rune) []rune {
+	s := New(width)
+	s.Runes(r...)
+	return []rune(s.String())
+}
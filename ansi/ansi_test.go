@@ -0,0 +1,268 @@
+package ansi_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/nalgeon/be"
+)
+
+const esc = "\x1b["
+
+func ExampleRender() {
+	s := esc + "6CWorld" + esc + "11DHello"
+	fmt.Println(string(ansi.Render(0, []rune(s)...)))
+	// Output: Hello World
+}
+
+func ExampleSGR() {
+	a := ansi.SGR(ansi.Attr{}, 1, 31, 44)
+	fmt.Printf("%q\n", a.SGR())
+	// Output: "\x1b[0;1;31;44m"
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+	be.True(t, !ansi.Contains())
+	be.True(t, !ansi.Contains([]rune("hello world")...))
+	be.True(t, !ansi.Contains([]rune("\x1b")...))
+	be.True(t, ansi.Contains([]rune("hello"+esc+"0m")...))
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		width int
+		s     string
+		want  string
+	}{
+		{"empty", 0, "", ""},
+		{"text", 0, "hello\r\nworld", "hello\nworld"},
+		{"cup", 0, esc + "2;3Hx", "\n  x"},
+		{"hvp", 0, esc + "2;3fx", "\n  x"},
+		{"cuf", 0, "a" + esc + "3Cb", "a   b"},
+		{"cub", 0, "abcd" + esc + "2DX", "abXd"},
+		{"cuu", 0, "a\r\nb" + esc + "AX", "aX\nb"},
+		{"cud", 0, "a" + esc + "2BX", "a\n\n X"},
+		{"save restore", 0, esc + "sabc" + esc + "uX", "Xbc"},
+		{"erase line", 0, "abcdef" + esc + "3D" + esc + "K", "abc"},
+		{"erase start", 0, "abcdef" + esc + "3D" + esc + "1K", "    ef"},
+		{"insert chars", 4, "abcd" + esc + "2D" + esc + "2@X", "aX b"},
+		{"delete chars", 0, "abcdef" + esc + "5D" + esc + "2P", "adef"},
+		{"erase display", 0, "abc\r\ndef" + esc + "2Jx", "x"},
+		{"wrap", 4, "abcdef", "abcd\nef"},
+		{"full row", 4, "abcd\r\nef", "abcd\nef"},
+		{"no wrap", 4, esc + "?7labcdef", "abcd"},
+		{"40 columns", 0, esc + "=1h" + "abc", "abc"},
+		{"invalid", 0, esc + "\x01x", "\x01x"},
+		{"private", 0, esc + "?25lx", "x"},
+		{"sgr", 0, esc + "1;31mx" + esc + "0my", esc + "0;1;31mx" + esc + "0my"},
+		{"pablodraw", 0, esc + "1;255;0;0tx", esc + "0;38;2;255;0;0mx" + esc + "0m"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := string(ansi.Render(tt.width, []rune(tt.s)...))
+			be.Equal(t, got, tt.want)
+		})
+	}
+}
+
+func TestSGR(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		p    []int
+		want ansi.Attr
+	}{
+		{"reset", nil, ansi.Attr{}},
+		{"missing", []int{-1}, ansi.Attr{}},
+		{"bold", []int{1}, ansi.Attr{Flags: ansi.Bold}},
+		{"normal", []int{1, 22}, ansi.Attr{}},
+		{"blink", []int{5}, ansi.Attr{Flags: ansi.Blink}},
+		{"colors", []int{32, 47}, ansi.Attr{FG: ansi.Index(2), BG: ansi.Index(7)}},
+		{"bright", []int{92, 107}, ansi.Attr{FG: ansi.Index(10), BG: ansi.Index(15)}},
+		{"xterm", []int{38, 5, 180}, ansi.Attr{FG: ansi.Index(180)}},
+		{"rgb", []int{48, 2, 1, 2, 3, 1}, ansi.Attr{BG: ansi.TrueColor(1, 2, 3), Flags: ansi.Bold}},
+		{"clamp", []int{38, 2, 999, -1, 3}, ansi.Attr{FG: ansi.TrueColor(255, 0, 3)}},
+		{"incomplete", []int{38, 5}, ansi.Attr{}},
+		{"default", []int{31, 41, 39, 49}, ansi.Attr{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, ansi.SGR(ansi.Attr{}, tt.p...), tt.want)
+		})
+	}
+}
+
+func TestScreen_Write(t *testing.T) {
+	t.Parallel()
+	s := ansi.New(0)
+	be.Equal(t, s.Width, ansi.Columns)
+	// the euro sign is split across two writes
+	b := []byte("a€")
+	n, err := s.Write(b[:2])
+	be.Err(t, err, nil)
+	be.Equal(t, n, 2)
+	_, err = s.Write(b[2:])
+	be.Err(t, err, nil)
+	be.Equal(t, s.String(), "a€")
+	x, y := s.Cursor()
+	be.Equal(t, x, 2)
+	be.Equal(t, y, 0)
+	be.Equal(t, s.Height(), 1)
+}
+
+func TestScreen_Limits(t *testing.T) {
+	t.Parallel()
+	s := ansi.New(0)
+	_, err := s.Write([]byte("abc\x1b[1D\x1b[900000000@d"))
+	be.Err(t, err, nil)
+	be.Equal(t, s.String(), "abd")
+	s = ansi.New(0)
+	_, err = s.Write([]byte("abc\x1b[900000000L\x1b[900000000I!"))
+	be.Err(t, err, nil)
+	be.True(t, s.Height() <= 1<<16)
+	x, _ := s.Cursor()
+	be.Equal(t, x, ansi.Columns)
+	const huge = "9223372036854775807"
+	for _, final := range "ABCDEF" {
+		s = ansi.New(0)
+		_, err = s.Write([]byte("\x1b[B\x1b[" + huge + string(final) + "x"))
+		be.Err(t, err, nil)
+		x, y := s.Cursor()
+		be.True(t, x >= 0 && x <= ansi.Columns)
+		be.True(t, y >= 0 && y < 1<<16)
+	}
+}
+
+func TestParseParams(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(ansi.ParseParams("")), 0)
+	p := ansi.ParseParams("1;;x;4")
+	be.Equal(t, p, ansi.Params{1, -1, -1, 4})
+	be.Equal(t, p.Get(0, 9), 1)
+	be.Equal(t, p.Get(1, 9), 9)
+	be.Equal(t, p.Get(5, 9), 9)
+}
+
+func ExampleColors() {
+	c := ansi.Colors{Depth: ansi.Depth16}
+	fmt.Printf("%q", string(c.Runes([]rune(esc+"38;2;250;80;80mHi"+esc+"0m")...)))
+	// Output: "\x1b[0;91mHi\x1b[0m"
+}
+
+func TestColors_Attr(t *testing.T) {
+	t.Parallel()
+	c64, _ := ansi.Named("c64")
+	tests := []struct {
+		name string
+		c    ansi.Colors
+		a    ansi.Attr
+		want ansi.Attr
+	}{
+		{"keep", ansi.Colors{}, ansi.Attr{FG: ansi.TrueColor(1, 2, 3)}, ansi.Attr{FG: ansi.TrueColor(1, 2, 3)}},
+		{"mono", ansi.Colors{Depth: ansi.DepthMono},
+			ansi.Attr{FG: ansi.Index(1), BG: ansi.Index(4), Flags: ansi.Bold}, ansi.Attr{Flags: ansi.Bold}},
+		{"256 cube", ansi.Colors{Depth: ansi.Depth256},
+			ansi.Attr{FG: ansi.TrueColor(255, 135, 0)}, ansi.Attr{FG: ansi.Index(208)}},
+		{"256 gray", ansi.Colors{Depth: ansi.Depth256},
+			ansi.Attr{BG: ansi.TrueColor(0x30, 0x30, 0x31)}, ansi.Attr{BG: ansi.Index(236)}},
+		{"256 keeps index", ansi.Colors{Depth: ansi.Depth256},
+			ansi.Attr{FG: ansi.Index(3)}, ansi.Attr{FG: ansi.Index(3)}},
+		{"16 true color", ansi.Colors{Depth: ansi.Depth16},
+			ansi.Attr{FG: ansi.TrueColor(0, 0, 0xa0)}, ansi.Attr{FG: ansi.Index(4)}},
+		{"16 xterm", ansi.Colors{Depth: ansi.Depth16},
+			ansi.Attr{BG: ansi.Index(231)}, ansi.Attr{BG: ansi.Index(15)}},
+		{"palette", ansi.Colors{Palette: &c64},
+			ansi.Attr{FG: ansi.Index(1), BG: ansi.Index(6)},
+			ansi.Attr{FG: ansi.TrueColor(0x68, 0x37, 0x2b), BG: ansi.TrueColor(0x70, 0xa4, 0xb2)}},
+		{"palette bold", ansi.Colors{Palette: &c64},
+			ansi.Attr{FG: ansi.Index(3), Flags: ansi.Bold},
+			ansi.Attr{FG: ansi.TrueColor(0xb8, 0xc7, 0x6f), Flags: ansi.Bold}},
+		{"palette 16", ansi.Colors{Depth: ansi.Depth16, Palette: &c64},
+			ansi.Attr{FG: ansi.Index(2)}, ansi.Attr{FG: ansi.Index(2)}},
+		{"ice", ansi.Colors{ICE: true},
+			ansi.Attr{BG: ansi.Index(4), Flags: ansi.Blink | ansi.Bold},
+			ansi.Attr{BG: ansi.Index(12), Flags: ansi.Bold}},
+		{"ice default", ansi.Colors{ICE: true},
+			ansi.Attr{Flags: ansi.Blink}, ansi.Attr{BG: ansi.Index(8)}},
+		{"ice true color", ansi.Colors{ICE: true, Palette: &c64},
+			ansi.Attr{BG: ansi.Index(0), Flags: ansi.Blink}, ansi.Attr{BG: ansi.TrueColor(0x44, 0x44, 0x44)}},
+		{"no ice", ansi.Colors{Depth: ansi.Depth16},
+			ansi.Attr{BG: ansi.Index(4), Flags: ansi.Blink}, ansi.Attr{BG: ansi.Index(4), Flags: ansi.Blink}},
+	}
+	for _, tt := range tests {
+		be.Equal(t, tt.c.Attr(tt.a), tt.want)
+	}
+}
+
+func TestColors_Runes(t *testing.T) {
+	t.Parallel()
+	vga := ansi.VGA()
+	tests := []struct {
+		name string
+		c    ansi.Colors
+		s    string
+		want string
+	}{
+		{"text", ansi.Colors{Depth: ansi.Depth16}, "hello", "hello"},
+		{"unchanged", ansi.Colors{Depth: ansi.Depth16},
+			esc + "1;31mhi" + esc + "2J", esc + "1;31mhi" + esc + "2J"},
+		{"relative", ansi.Colors{Depth: ansi.Depth256},
+			esc + "38;2;0;0;0m" + esc + "1mhi", esc + "0;38;5;16m" + esc + "0;1;38;5;16mhi"},
+		{"pablodraw", ansi.Colors{Depth: ansi.Depth16}, esc + "1;170;0;0thi", esc + "0;31mhi"},
+		{"palette", ansi.Colors{Palette: &vga}, esc + "44m ", esc + "0;48;2;0;0;170m "},
+		{"private", ansi.Colors{Depth: ansi.DepthMono}, esc + "?25l", esc + "?25l"},
+		{"ice", ansi.Colors{ICE: true}, esc + "5;44mhi" + esc + "0m", esc + "0;104mhi" + esc + "0m"},
+		{"incomplete", ansi.Colors{Depth: ansi.DepthMono}, "a" + esc + "31", "a" + esc + "31"},
+	}
+	for _, tt := range tests {
+		be.Equal(t, string(tt.c.Runes([]rune(tt.s)...)), tt.want)
+	}
+}
+
+func TestColors_NewWriter(t *testing.T) {
+	t.Parallel()
+	c := ansi.Colors{Depth: ansi.Depth256}
+	const s = esc + "38;2;0;0;0m╔═╗" + esc + "1mhi\r\n" + esc + "5;44mbye" + esc + "0m" + esc + "31"
+	for _, size := range []int{1, 2, 7, len(s)} {
+		var sb strings.Builder
+		w := c.NewWriter(&sb)
+		for p := []byte(s); len(p) > 0; p = p[min(size, len(p)):] {
+			n, err := w.Write(p[:min(size, len(p))])
+			be.Err(t, err, nil)
+			be.Equal(t, n, min(size, len(p)))
+		}
+		be.Err(t, w.Flush(), nil)
+		be.Equal(t, sb.String(), string(c.Runes([]rune(s)...)))
+	}
+	// a long true color sequence split between the writes
+	var sb strings.Builder
+	w := ansi.Colors{Depth: ansi.Depth16}.NewWriter(&sb)
+	const long = "hi " + esc + "0;1;5;38;2;255;255;255;48;2;255;255;255mbye"
+	i := strings.Index(long, "mbye")
+	for _, p := range []string{long[:i], long[i:]} {
+		_, err := w.Write([]byte(p))
+		be.Err(t, err, nil)
+	}
+	be.Err(t, w.Flush(), nil)
+	be.Equal(t, sb.String(), "hi "+esc+"0;1;5;97;107mbye")
+}
+
+func TestNamed(t *testing.T) {
+	t.Parallel()
+	for _, name := range ansi.Palettes() {
+		_, err := ansi.Named(name)
+		be.Err(t, err, nil)
+	}
+	p, err := ansi.Named(" Amiga")
+	be.Err(t, err, nil)
+	be.Equal(t, p, ansi.Workbench())
+	_, err = ansi.Named("zx")
+	be.Err(t, err, ansi.ErrPalette)
+}
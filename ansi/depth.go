@@ -0,0 +1,250 @@
+package ansi
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Depth is the number of colors displayed by a terminal.
+type Depth int
+
+const (
+	DepthTrue Depth = 0   // DepthTrue is a terminal with 24-bit true colors, where the colors are kept.
+	DepthMono Depth = 2   // DepthMono is a monochrome terminal, where the colors are removed.
+	Depth16   Depth = 16  // Depth16 is a terminal with the 16 standard and bright colors.
+	Depth256  Depth = 256 // Depth256 is a terminal with the 256 xterm colors.
+)
+
+// Colors is the output stage that converts the colors of the text for the terminal.
+// The zero value keeps the colors as is.
+type Colors struct {
+	Depth   Depth    // Depth is the number of colors of the terminal.
+	Palette *Palette // Palette replaces the 16 standard and bright colors with its true colors, when not nil.
+	ICE     bool     // ICE replaces the blink attribute with a bright background color, known as iCE colors.
+}
+
+// Attr returns the attribute with its colors converted for the terminal.
+// With iCE colors, blink first brightens the standard or default black background.
+// The standard colors are then replaced using the palette, where bold brightens
+// the foreground, and then the colors are reduced to the depth of the terminal.
+func (c Colors) Attr(a Attr) Attr {
+	if c.ICE && a.Has(Blink) {
+		if a.BG.Mode == Default {
+			a.BG = Index(0)
+		}
+		if a.BG.Mode == Indexed && a.BG.Index < classic {
+			a.BG.Index += classic
+		}
+		a.Flags &^= Blink
+	}
+	if c.Palette != nil {
+		fg := a.FG
+		if a.Has(Bold) && fg.Mode == Indexed && fg.Index < classic {
+			fg.Index += classic
+		}
+		a.FG, a.BG = c.rgb(fg), c.rgb(a.BG)
+	}
+	switch c.Depth {
+	case DepthMono:
+		a.FG, a.BG = Color{}, Color{}
+	case Depth16:
+		a.FG, a.BG = c.standard(a.FG), c.standard(a.BG)
+	case Depth256:
+		a.FG, a.BG = xterm(a.FG), xterm(a.BG)
+	case DepthTrue:
+	}
+	return a
+}
+
+// Runes returns the runes with the colors of the select graphic rendition (SGR) sequences
+// converted for the terminal. The PabloDraw 24-bit color sequences are replaced with SGR sequences.
+// The sequences that do not need a conversion are kept as is.
+func (c Colors) Runes(r ...rune) []rune {
+	cur := Attr{}
+	return c.runes(&cur, r...)
+}
+
+// runes converts the colors of the runes using and updating the cur attributes.
+func (c Colors) runes(cur *Attr, r ...rune) []rune {
+	if c == (Colors{}) || !Contains(r...) {
+		return r
+	}
+	out := make([]rune, 0, len(r))
+	for i := 0; i < len(r); i++ {
+		if r[i] != esc || i+1 == len(r) || r[i+1] != csi {
+			out = append(out, r[i])
+			continue
+		}
+		j := i + 2
+		for j < len(r) && r[j] >= 0x20 && r[j] <= 0x3f {
+			j++
+		}
+		if j == len(r) {
+			return append(out, r[i:]...)
+		}
+		params, final := string(r[i+2:j]), r[j]
+		seq := r[i : j+1]
+		i = j
+		if strings.ContainsAny(params, "?=<>") {
+			out = append(out, seq...)
+			continue
+		}
+		switch final {
+		case 'm':
+			*cur = SGR(*cur, ParseParams(params)...)
+		case 't':
+			*cur = PabloDraw(*cur, ParseParams(params)...)
+		default:
+			out = append(out, seq...)
+			continue
+		}
+		if x := c.Attr(*cur); x != *cur {
+			out = append(out, []rune(x.SGR())...)
+			continue
+		}
+		out = append(out, seq...)
+	}
+	return out
+}
+
+// Writer converts the colors of a text written to it in chunks for the terminal.
+// The attributes are kept between the writes, while an escape sequence or a rune
+// split between the writes is converted once it is complete.
+type Writer struct {
+	colors Colors
+	w      io.Writer
+	cur    Attr
+	carry  []byte // carry is an incomplete sequence or rune at the end of the last write.
+}
+
+// NewWriter returns a writer that converts the colors of the text and writes it to w.
+func (c Colors) NewWriter(w io.Writer) *Writer {
+	return &Writer{colors: c, w: w}
+}
+
+func (x *Writer) Write(p []byte) (int, error) {
+	b := p
+	if len(x.carry) > 0 {
+		b = append(x.carry, p...)
+	}
+	i := incomplete(b)
+	x.carry = append([]byte(nil), b[i:]...)
+	if err := x.write(b[:i]...); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Flush writes any incomplete sequence or rune at the end of the text as is.
+func (x *Writer) Flush() error {
+	b := x.carry
+	x.carry = nil
+	return x.write(b...)
+}
+
+func (x *Writer) write(b ...byte) error {
+	if len(b) == 0 {
+		return nil
+	}
+	r := x.colors.runes(&x.cur, bytes.Runes(b)...)
+	if _, err := io.WriteString(x.w, string(r)); err != nil {
+		return fmt.Errorf("ansi writer: %w", err)
+	}
+	return nil
+}
+
+// incomplete returns the index of an incomplete escape sequence or rune at the end of b,
+// or the length of b when it ends with a complete sequence and rune.
+func incomplete(b []byte) int {
+	const maxSeq = 256 // maxSeq is the length of the longest sequence that is kept together.
+	start := max(0, len(b)-maxSeq)
+	if i := bytes.LastIndexByte(b[start:], esc); i >= 0 {
+		i += start
+		j := i + 1
+		if j < len(b) && b[j] == csi {
+			j++
+			for j < len(b) && b[j] >= 0x20 && b[j] <= 0x3f {
+				j++
+			}
+		}
+		if j == len(b) {
+			return i
+		}
+	}
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if utf8.RuneStart(b[i]) {
+			if !utf8.FullRune(b[i:]) {
+				return i
+			}
+			break
+		}
+	}
+	return len(b)
+}
+
+// rgb returns the true color of a standard or bright color using the palette.
+func (c Colors) rgb(x Color) Color {
+	if x.Mode != Indexed || int(x.Index) >= len(c.Palette) {
+		return x
+	}
+	v := c.Palette[x.Index]
+	return TrueColor(v.R, v.G, v.B)
+}
+
+// standard returns the nearest of the 16 standard and bright colors,
+// compared using the palette or otherwise the VGA palette.
+func (c Colors) standard(x Color) Color {
+	p := VGA()
+	if c.Palette != nil {
+		p = *c.Palette
+	}
+	v, ok := p.RGBA(x)
+	if !ok || (x.Mode == Indexed && int(x.Index) < len(p)) {
+		return x
+	}
+	n, best := 0, -1
+	for i, y := range p {
+		if d := distance(v, y); best < 0 || d < best {
+			n, best = i, d
+		}
+	}
+	return Index(uint8(n))
+}
+
+// xterm returns the nearest of the xterm color cube and grayscale colors for a true color.
+func xterm(x Color) Color {
+	const cube, gray, grays = 16, 232, 24
+	if x.Mode != RGB {
+		return x
+	}
+	v := color.RGBA{x.R, x.G, x.B, 0xff}
+	// level returns the nearest of the 0, 95, 135, 175, 215 and 255 color cube levels
+	level := func(c uint8) int {
+		const low, first, step = 48, 115, 40
+		switch {
+		case c < low:
+			return 0
+		case c < first:
+			return 1
+		}
+		return (int(c) - first + 2*step) / step
+	}
+	i := cube + 36*level(v.R) + 6*level(v.G) + level(v.B) //nolint:mnd
+	n, best := i, distance(v, Xterm(uint8(i)))
+	for g := gray; g < gray+grays; g++ {
+		if d := distance(v, Xterm(uint8(g))); d < best {
+			n, best = g, d
+		}
+	}
+	return Index(uint8(n))
+}
+
+// distance returns the squared distance between the two colors.
+func distance(a, b color.RGBA) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
@@ -0,0 +1,163 @@
+package ansi
+
+import (
+	"errors"
+	"fmt"
+	"image/color"
+	"strings"
+)
+
+var ErrPalette = errors.New("palette is not known")
+
+// Palette are the 16 standard and bright colors used by the indexed color mode.
+type Palette [classic * 2]color.RGBA
+
+// VGA returns the IBM VGA text mode palette used by MS-DOS and most ANSI art.
+func VGA() Palette {
+	return Palette{
+		{0x00, 0x00, 0x00, 0xff}, // black
+		{0xaa, 0x00, 0x00, 0xff}, // red
+		{0x00, 0xaa, 0x00, 0xff}, // green
+		{0xaa, 0x55, 0x00, 0xff}, // brown
+		{0x00, 0x00, 0xaa, 0xff}, // blue
+		{0xaa, 0x00, 0xaa, 0xff}, // magenta
+		{0x00, 0xaa, 0xaa, 0xff}, // cyan
+		{0xaa, 0xaa, 0xaa, 0xff}, // light gray
+		{0x55, 0x55, 0x55, 0xff}, // dark gray
+		{0xff, 0x55, 0x55, 0xff}, // light red
+		{0x55, 0xff, 0x55, 0xff}, // light green
+		{0xff, 0xff, 0x55, 0xff}, // yellow
+		{0x55, 0x55, 0xff, 0xff}, // light blue
+		{0xff, 0x55, 0xff, 0xff}, // light magenta
+		{0x55, 0xff, 0xff, 0xff}, // light cyan
+		{0xff, 0xff, 0xff, 0xff}, // white
+	}
+}
+
+// RGBA returns the color value using the palette for the 16 standard and bright colors.
+// The 256 color xterm values are used for the other indexed colors.
+// It reports false when the color is the terminal default.
+func (p Palette) RGBA(c Color) (color.RGBA, bool) {
+	const opaque = 0xff
+	switch c.Mode {
+	case Indexed:
+		if int(c.Index) < len(p) {
+			return p[c.Index], true
+		}
+		return Xterm(c.Index), true
+	case RGB:
+		return color.RGBA{c.R, c.G, c.B, opaque}, true
+	case Default:
+	}
+	return color.RGBA{}, false
+}
+
+// Xterm returns the color value of the 256 color xterm palette index.
+// The first 16 colors use the VGA palette.
+func Xterm(i uint8) color.RGBA {
+	const (
+		opaque = 0xff
+		cube   = 16  // cube is the first index of the 6x6x6 color cube.
+		gray   = 232 // gray is the first index of the grayscale ramp.
+		steps  = 6   // steps is the number of color levels on each side of the cube.
+	)
+	switch {
+	case i < cube:
+		return VGA()[i]
+	case i < gray:
+		n := i - cube
+		level := func(v uint8) uint8 {
+			const first, step = 55, 40
+			if v == 0 {
+				return 0
+			}
+			return first + v*step
+		}
+		return color.RGBA{level(n / (steps * steps)), level(n / steps % steps), level(n % steps), opaque}
+	}
+	const first, step = 8, 10
+	v := first + (i-gray)*step
+	return color.RGBA{v, v, v, opaque}
+}
+
+// CGA returns the IBM CGA palette as shown by a RGBI monitor without the brown circuit
+// of the IBM 5153 display, so the standard yellow is a dark yellow.
+func CGA() Palette {
+	p := VGA()
+	p[3] = color.RGBA{0xaa, 0xaa, 0x00, 0xff}
+	return p
+}
+
+// EGA returns the default IBM EGA palette, which the VGA text mode copies.
+func EGA() Palette {
+	return VGA()
+}
+
+// Workbench returns the 12-bit colors of the Commodore Amiga Workbench 1.3 screen.
+// The blue, white, black and orange pens of the Workbench replace the blue,
+// white, black and yellow colors, the others use the 12-bit color levels of the Amiga.
+func Workbench() Palette {
+	return Palette{
+		{0x00, 0x00, 0x22, 0xff}, // black pen
+		{0xaa, 0x00, 0x00, 0xff}, // red
+		{0x00, 0xaa, 0x00, 0xff}, // green
+		{0xcc, 0x66, 0x00, 0xff}, // brown
+		{0x00, 0x55, 0xaa, 0xff}, // blue pen
+		{0xaa, 0x00, 0xaa, 0xff}, // magenta
+		{0x00, 0xaa, 0xaa, 0xff}, // cyan
+		{0xbb, 0xbb, 0xbb, 0xff}, // light gray
+		{0x66, 0x66, 0x66, 0xff}, // dark gray
+		{0xff, 0x44, 0x44, 0xff}, // light red
+		{0x44, 0xff, 0x44, 0xff}, // light green
+		{0xff, 0x88, 0x00, 0xff}, // orange pen
+		{0x66, 0x88, 0xff, 0xff}, // light blue
+		{0xff, 0x44, 0xff, 0xff}, // light magenta
+		{0x44, 0xff, 0xff, 0xff}, // light cyan
+		{0xff, 0xff, 0xff, 0xff}, // white pen
+	}
+}
+
+// C64 returns the Commodore 64 palette measured by Pepto.
+// The C64 has no light magenta and light cyan, so the purple and cyan are reused.
+func C64() Palette {
+	return Palette{
+		{0x00, 0x00, 0x00, 0xff}, // black
+		{0x68, 0x37, 0x2b, 0xff}, // red
+		{0x58, 0x8d, 0x43, 0xff}, // green
+		{0x6f, 0x4f, 0x25, 0xff}, // orange
+		{0x35, 0x28, 0x79, 0xff}, // blue
+		{0x6f, 0x3d, 0x86, 0xff}, // purple
+		{0x70, 0xa4, 0xb2, 0xff}, // cyan
+		{0x95, 0x95, 0x95, 0xff}, // light grey
+		{0x44, 0x44, 0x44, 0xff}, // dark grey
+		{0x9a, 0x67, 0x59, 0xff}, // light red
+		{0x9a, 0xd2, 0x84, 0xff}, // light green
+		{0xb8, 0xc7, 0x6f, 0xff}, // yellow
+		{0x6c, 0x5e, 0xb5, 0xff}, // light blue
+		{0x6f, 0x3d, 0x86, 0xff}, // purple
+		{0x70, 0xa4, 0xb2, 0xff}, // cyan
+		{0xff, 0xff, 0xff, 0xff}, // white
+	}
+}
+
+// Palettes returns the names of the palettes used by Named.
+func Palettes() []string {
+	return []string{"vga", "cga", "ega", "amiga", "c64"}
+}
+
+// Named returns the palette of the name, which is one of the Palettes.
+func Named(name string) (Palette, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "vga":
+		return VGA(), nil
+	case "cga":
+		return CGA(), nil
+	case "ega":
+		return EGA(), nil
+	case "amiga", "workbench":
+		return Workbench(), nil
+	case "c64":
+		return C64(), nil
+	}
+	return Palette{}, fmt.Errorf("%w: %q", ErrPalette, name)
+}
@@ -0,0 +1,453 @@
+package ansi
+
+import (
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Screen is a virtual ANSI.SYS display of character cells.
+// The number of rows grows as text is written below the last row.
+type Screen struct {
+	Width int  // Width is the number of columns per row.
+	Wrap  bool // Wrap moves any text written beyond the last column onto the next row.
+
+	rows   [][]Cell
+	x, y   int  // x and y are the cursor column and row.
+	sx, sy int  // sx and sy are the saved cursor column and row.
+	attr   Attr // attr are the current display attributes.
+	state  int  // state of the escape sequence parser.
+	seq    []rune
+	utf    []byte // utf holds an incomplete UTF-8 encoded rune between writes.
+}
+
+// Parser states.
+const (
+	ground = iota
+	escape
+	control
+)
+
+const (
+	tabStop = 8       // tabStop is the number of columns between horizontal tab stops.
+	maxRows = 1 << 16 // maxRows limits the screen height to protect against runaway cursor movements.
+)
+
+// New returns a blank screen with the number of columns.
+// The Columns value is used when the width is less than 1.
+func New(width int) *Screen {
+	if width < 1 {
+		width = Columns
+	}
+	return &Screen{Width: width, Wrap: true}
+}
+
+// Write interprets the UTF-8 encoded text and escape sequences.
+// It always consumes all of the bytes.
+func (s *Screen) Write(p []byte) (int, error) {
+	b := p
+	if len(s.utf) > 0 {
+		b = append(s.utf, p...)
+		s.utf = nil
+	}
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r == utf8.RuneError && !utf8.FullRune(b) {
+			s.utf = append([]byte{}, b...)
+			break
+		}
+		s.put(r)
+		b = b[size:]
+	}
+	return len(p), nil
+}
+
+// Runes interprets the runes and escape sequences.
+func (s *Screen) Runes(r ...rune) {
+	for _, x := range r {
+		s.put(x)
+	}
+}
+
+// Cursor returns the zero-based column and row of the cursor.
+func (s *Screen) Cursor() (int, int) {
+	return s.x, s.y
+}
+
+// Attr returns the current display attributes.
+func (s *Screen) Attr() Attr {
+	return s.attr
+}
+
+// SetAttr replaces the current display attributes.
+func (s *Screen) SetAttr(a Attr) {
+	s.attr = a
+}
+
+// Rows returns the cells of the screen.
+// Rows are not padded, so a row can contain fewer cells than the width.
+func (s *Screen) Rows() [][]Cell {
+	return s.rows
+}
+
+// Height returns the number of rows in use.
+func (s *Screen) Height() int {
+	return len(s.rows)
+}
+
+// String returns the screen as text using SGR escape sequences for the display attributes.
+// Trailing blank cells on each row are removed.
+func (s *Screen) String() string {
+	sb := &strings.Builder{}
+	_, _ = s.WriteTo(sb)
+	return sb.String()
+}
+
+// WriteTo writes the screen as text using SGR escape sequences for the display attributes.
+func (s *Screen) WriteTo(w io.Writer) (int64, error) {
+	if w == nil {
+		w = io.Discard
+	}
+	var n int64
+	for y, row := range s.rows {
+		sb := strings.Builder{}
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		Line(&sb, row...)
+		i, err := io.WriteString(w, sb.String())
+		n += int64(i)
+		if err != nil {
+			return n, err //nolint:wrapcheck
+		}
+	}
+	return n, nil
+}
+
+// Line writes the cells as text using SGR escape sequences for the display attributes.
+// Trailing blank cells are removed and any attributes are reset at the end of the line.
+func Line(sb *strings.Builder, cells ...Cell) {
+	last := len(cells) - 1
+	for last >= 0 && cells[last].Blank() {
+		last--
+	}
+	attr := Attr{}
+	for _, c := range cells[:last+1] {
+		if c.Attr != attr {
+			sb.WriteString(c.Attr.SGR())
+			attr = c.Attr
+		}
+		r := c.Rune
+		if r == 0 {
+			r = ' '
+		}
+		sb.WriteRune(r)
+	}
+	if attr != (Attr{}) {
+		sb.WriteString(Attr{}.SGR())
+	}
+}
+
+// put interprets a single rune.
+func (s *Screen) put(r rune) {
+	switch s.state {
+	case escape:
+		if r == csi {
+			s.state = control
+			s.seq = s.seq[:0]
+			return
+		}
+		s.state = ground
+	case control:
+		s.sequence(r)
+		return
+	}
+	switch r {
+	case esc:
+		s.state = escape
+	case '\r':
+		s.x = 0
+	case '\n':
+		s.newline()
+	case '\t':
+		s.x = min((s.x/tabStop+1)*tabStop, s.Width-1)
+	default:
+		s.Print(r)
+	}
+}
+
+// Print writes the rune at the cursor using the current attributes and advances the cursor.
+// Wrapping is deferred until the next rune is printed, so a line break that follows
+// a full row does not create an empty row.
+func (s *Screen) Print(r rune) {
+	if s.x >= s.Width {
+		if !s.Wrap {
+			return
+		}
+		s.newline()
+	}
+	s.set(s.x, s.y, Cell{Rune: r, Attr: s.attr})
+	s.x++
+}
+
+// newline moves the cursor to the start of the next row.
+func (s *Screen) newline() {
+	s.x = 0
+	s.y = min(s.y+1, maxRows-1)
+}
+
+// sequence collects the parameters of a control sequence until the final byte.
+func (s *Screen) sequence(r rune) {
+	const (
+		paramLow  = 0x30
+		paramHigh = 0x3f
+		interLow  = 0x20
+		interHigh = 0x2f
+		final     = 0x40
+		finalHigh = 0x7e
+		maxLen    = 64
+	)
+	switch {
+	case r >= paramLow && r <= paramHigh, r >= interLow && r <= interHigh:
+		s.seq = append(s.seq, r)
+		if len(s.seq) > maxLen {
+			s.state = ground
+		}
+	case r >= final && r <= finalHigh:
+		s.state = ground
+		s.Control(string(s.seq), r)
+	default:
+		// an invalid sequence is discarded
+		s.state = ground
+		s.put(r)
+	}
+}
+
+// Control applies the control sequence using the parameters and the final character.
+// A parameters string that begins with a "?", "=" or ">" character is a private mode.
+func (s *Screen) Control(params string, final rune) {
+	private := ""
+	if params != "" && strings.ContainsRune("?=><", rune(params[0])) {
+		private, params = params[:1], params[1:]
+	}
+	p := ParseParams(params)
+	// the count is limited, as a runaway count overflows the relative cursor movements
+	n := min(p.Get(0, 1), maxRows)
+	switch final {
+	case 'h', 'l':
+		s.mode(private, final == 'h', p)
+		return
+	}
+	if private != "" {
+		return
+	}
+	switch final {
+	case 'A':
+		s.y = max(s.y-n, 0)
+	case 'B':
+		s.y += n
+	case 'C':
+		s.x = min(s.x+n, s.Width-1)
+	case 'D':
+		s.x = max(min(s.x, s.Width-1)-n, 0)
+	case 'E':
+		s.x, s.y = 0, s.y+n
+	case 'F':
+		s.x, s.y = 0, max(s.y-n, 0)
+	case 'G':
+		s.x = min(max(n-1, 0), s.Width-1)
+	case 'H', 'f':
+		s.y = max(p.Get(0, 1)-1, 0)
+		s.x = min(max(p.Get(1, 1)-1, 0), s.Width-1)
+	case 'I':
+		// the cursor stops at the last column, so more tabs than columns have no effect
+		for range min(n, s.Width) {
+			s.put('\t')
+		}
+	case 'J':
+		s.eraseDisplay(p.Get(0, 0))
+	case 'K':
+		s.eraseLine(p.Get(0, 0))
+	case '@':
+		s.insertChars(n)
+	case 'L':
+		s.insertLines(n)
+	case 'M':
+		s.deleteLines(n)
+	case 'P':
+		s.deleteChars(n)
+	case 'm':
+		s.attr = SGR(s.attr, p...)
+	case 's':
+		s.sx, s.sy = s.x, s.y
+	case 'u':
+		s.x, s.y = s.sx, s.sy
+	case 't':
+		s.attr = PabloDraw(s.attr, p...)
+	}
+	s.y = min(s.y, maxRows-1)
+}
+
+// mode handles the ANSI.SYS set and reset mode sequences.
+// Only the screen width and line wrapping modes affect the screen.
+func (s *Screen) mode(private string, set bool, p Params) {
+	const col40a, col40b, col80a, col80b, wrap = 0, 1, 2, 3, 7
+	if private == ">" || private == "<" {
+		return
+	}
+	for _, v := range p {
+		switch v {
+		case col40a, col40b:
+			if set && private == "=" {
+				s.Width = Columns / 2
+			}
+		case col80a, col80b:
+			if set && private == "=" {
+				s.Width = Columns
+			}
+		case wrap:
+			if private != "" {
+				s.Wrap = set
+			}
+		}
+	}
+}
+
+// row returns the row at y, creating any missing rows.
+func (s *Screen) row(y int) []Cell {
+	for len(s.rows) <= y {
+		s.rows = append(s.rows, nil)
+	}
+	return s.rows[y]
+}
+
+// set places the cell at the column and row.
+func (s *Screen) set(x, y int, c Cell) {
+	row := s.row(y)
+	if len(row) <= x {
+		row = append(row, make([]Cell, x-len(row)+1)...)
+	}
+	row[x] = c
+	s.rows[y] = row
+}
+
+// blank returns an erased cell that keeps the current background color.
+func (s *Screen) blank() Cell {
+	return Cell{Rune: ' ', Attr: Attr{BG: s.attr.BG}}
+}
+
+// eraseLine clears the cursor row, 0 from the cursor to the end,
+// 1 from the start to the cursor, 2 the entire row.
+func (s *Screen) eraseLine(n int) {
+	row := s.row(s.y)
+	from, to := s.x, s.Width
+	switch n {
+	case 1:
+		from, to = 0, s.x+1
+	case 2:
+		from = 0
+	}
+	blank := s.blank()
+	if blank.Blank() && to >= len(row) {
+		// erasing the end of a row with the default background shortens the row
+		s.rows[s.y] = row[:min(from, len(row))]
+		return
+	}
+	for x := from; x < to && x < s.Width; x++ {
+		s.set(x, s.y, blank)
+	}
+}
+
+// eraseDisplay clears the screen, 0 from the cursor to the end,
+// 1 from the start to the cursor, 2 the entire screen.
+// ANSI.SYS also moves the cursor to the top left when the entire screen is cleared.
+func (s *Screen) eraseDisplay(n int) {
+	switch n {
+	case 0:
+		s.eraseLine(0)
+		if s.y+1 < len(s.rows) {
+			s.rows = s.rows[:s.y+1]
+		}
+	case 1:
+		for y := 0; y < s.y && y < len(s.rows); y++ {
+			s.rows[y] = nil
+		}
+		s.eraseLine(1)
+	case 2:
+		s.rows = nil
+		s.x, s.y = 0, 0
+	}
+}
+
+// insertLines inserts n blank rows at the cursor row,
+// the rows that are pushed beyond the screen height are lost.
+func (s *Screen) insertLines(n int) {
+	s.row(s.y)
+	blank := make([][]Cell, min(n, maxRows-s.y))
+	s.rows = append(s.rows[:s.y], append(blank, s.rows[s.y:]...)...)
+	s.rows = s.rows[:min(len(s.rows), maxRows)]
+}
+
+// deleteLines removes n rows from the cursor row.
+func (s *Screen) deleteLines(n int) {
+	if s.y >= len(s.rows) {
+		return
+	}
+	end := min(s.y+n, len(s.rows))
+	s.rows = append(s.rows[:s.y], s.rows[end:]...)
+}
+
+// insertChars inserts n blank cells at the cursor,
+// the cells that are pushed beyond the last column are lost.
+func (s *Screen) insertChars(n int) {
+	row := s.row(s.y)
+	if s.x >= len(row) || s.x >= s.Width {
+		return
+	}
+	blank := make([]Cell, min(n, s.Width-s.x))
+	for i := range blank {
+		blank[i] = s.blank()
+	}
+	row = append(row[:s.x], append(blank, row[s.x:]...)...)
+	s.rows[s.y] = row[:min(len(row), s.Width)]
+}
+
+// deleteChars removes n cells from the cursor, the following cells move to the left.
+func (s *Screen) deleteChars(n int) {
+	row := s.row(s.y)
+	if s.x >= len(row) {
+		return
+	}
+	end := min(s.x+n, len(row))
+	s.rows[s.y] = append(row[:s.x], row[end:]...)
+}
+
+// Params are the numeric parameters of a control sequence.
+// Missing parameters use a -1 value.
+type Params []int
+
+// Get returns the parameter at index i or the fallback value when it is missing or zero.
+func (p Params) Get(i, fallback int) int {
+	if i >= len(p) || p[i] < 1 {
+		return fallback
+	}
+	return p[i]
+}
+
+// ParseParams splits the semicolon separated parameters of a control sequence.
+func ParseParams(s string) Params {
+	if s == "" {
+		return nil
+	}
+	fields := strings.Split(s, ";")
+	p := make(Params, 0, len(fields))
+	for _, f := range fields {
+		i, err := strconv.Atoi(f)
+		if err != nil {
+			i = -1
+		}
+		p = append(p, i)
+	}
+	return p
+}
@@ -0,0 +1,125 @@
+package ansi
+
+// Select graphic rendition parameter values.
+const (
+	fgBlack       = 30  // fgBlack is the first of the eight standard foreground colors.
+	fgWhite       = 37  // fgWhite is the last of the eight standard foreground colors.
+	fgExtended    = 38  // fgExtended introduces an 8-bit or 24-bit foreground color.
+	fgDefault     = 39  // fgDefault is the default foreground color.
+	bgBlack       = 40  // bgBlack is the first of the eight standard background colors.
+	bgWhite       = 47  // bgWhite is the last of the eight standard background colors.
+	bgExtended    = 48  // bgExtended introduces an 8-bit or 24-bit background color.
+	bgDefault     = 49  // bgDefault is the default background color.
+	fgBrightBlack = 90  // fgBrightBlack is the first of the aixterm bright foreground colors.
+	fgBrightWhite = 97  // fgBrightWhite is the last of the aixterm bright foreground colors.
+	bgBrightBlack = 100 // bgBrightBlack is the first of the aixterm bright background colors.
+	bgBrightWhite = 107 // bgBrightWhite is the last of the aixterm bright background colors.
+	classic       = 8   // classic is the number of standard colors.
+)
+
+// SGR applies the select graphic rendition parameters to the attribute and returns the result.
+// Both the 8-bit (38;5;n) and the 24-bit (38;2;r;g;b) extended colors are supported.
+func SGR(a Attr, p ...int) Attr {
+	if len(p) == 0 {
+		return Attr{}
+	}
+	for i := 0; i < len(p); i++ {
+		v := max(p[i], 0)
+		switch {
+		case v == 0:
+			a = Attr{}
+		case v >= fgBlack && v <= fgWhite:
+			a.FG = Index(uint8(v - fgBlack))
+		case v >= bgBlack && v <= bgWhite:
+			a.BG = Index(uint8(v - bgBlack))
+		case v >= fgBrightBlack && v <= fgBrightWhite:
+			a.FG = Index(uint8(v - fgBrightBlack + classic))
+		case v >= bgBrightBlack && v <= bgBrightWhite:
+			a.BG = Index(uint8(v - bgBrightBlack + classic))
+		case v == fgExtended, v == bgExtended:
+			c, n, ok := extended(p[i+1:]...)
+			i += n
+			if !ok {
+				continue
+			}
+			if v == fgExtended {
+				a.FG = c
+				continue
+			}
+			a.BG = c
+		case v == fgDefault:
+			a.FG = Color{}
+		case v == bgDefault:
+			a.BG = Color{}
+		default:
+			a.Flags = flags(a.Flags, v)
+		}
+	}
+	return a
+}
+
+// flags applies the select graphic rendition parameter to the display attribute flags.
+func flags(f Flags, v int) Flags {
+	set := map[int]Flags{
+		1: Bold, 2: Faint, 3: Italic, 4: Underline, 5: Blink, 6: Blink,
+		7: Inverse, 8: Conceal, 9: Strike,
+	}
+	reset := map[int]Flags{
+		21: Bold | Faint, 22: Bold | Faint, 23: Italic, 24: Underline,
+		25: Blink, 27: Inverse, 28: Conceal, 29: Strike,
+	}
+	if x, ok := set[v]; ok {
+		return f | x
+	}
+	if x, ok := reset[v]; ok {
+		return f &^ x
+	}
+	return f
+}
+
+// extended returns the 8-bit or 24-bit color that follows a 38 or 48 parameter,
+// the number of parameters that were used and whether the color is valid.
+func extended(p ...int) (Color, int, bool) {
+	const indexed, rgb = 5, 2
+	if len(p) == 0 {
+		return Color{}, 0, false
+	}
+	switch p[0] {
+	case indexed:
+		const n = 2
+		if len(p) < n {
+			return Color{}, len(p), false
+		}
+		return Index(byteVal(p[1])), n, true
+	case rgb:
+		const n = 4
+		if len(p) < n {
+			return Color{}, len(p), false
+		}
+		return TrueColor(byteVal(p[1]), byteVal(p[2]), byteVal(p[3])), n, true
+	}
+	return Color{}, 0, false
+}
+
+// PabloDraw applies the PabloDraw 24-bit color sequence to the attribute and returns the result.
+// The sequence is ESC[0;R;G;Bt for the background or ESC[1;R;G;Bt for the foreground.
+func PabloDraw(a Attr, p ...int) Attr {
+	const bg, fg, n = 0, 1, 4
+	if len(p) != n {
+		return a
+	}
+	c := TrueColor(byteVal(p[1]), byteVal(p[2]), byteVal(p[3]))
+	switch max(p[0], 0) {
+	case bg:
+		a.BG = c
+	case fg:
+		a.FG = c
+	}
+	return a
+}
+
+// byteVal clamps the value to the 0 to 255 range of a byte.
+func byteVal(v int) uint8 {
+	const maxUint8 = 255
+	return uint8(min(max(v, 0), maxUint8))
+}
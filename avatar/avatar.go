@@ -0,0 +1,371 @@
+// Package avatar translates the Avatar/0 and Avatar/0+ control codes into ANSI escape sequences.
+//
+// Avatar, the Advanced Video Attribute Terminal Assembler and Recreator, is the compact
+// alternative to ANSI used by FidoNet mailers and BBS software such as RemoteAccess.
+// The codes set the MS-DOS color attributes, move the cursor, repeat characters
+// and with Avatar/0+, edit areas of the screen.
+//
+// The translation is applied to the encoded bytes before they are decoded,
+// as the parameters of the codes are bytes that must not be mistaken for characters.
+// The escape sequences are those interpreted by the ANSI.SYS screen of the ansi package.
+package avatar
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"golang.org/x/text/transform"
+)
+
+const (
+	ff  = 0x0c // ff is the form feed control that clears the screen.
+	avt = 0x16 // avt is the synchronous idle control that introduces the commands.
+	rep = 0x19 // rep is the end of medium control that repeats a character.
+)
+
+// The commands that follow the avt control, the Avatar/0+ commands begin with insert.
+const (
+	attribute  = 0x01 // attribute sets the color attribute.
+	blink      = 0x02 // blink turns on the blink attribute.
+	up         = 0x03 // up moves the cursor up a row.
+	down       = 0x04 // down moves the cursor down a row.
+	left       = 0x05 // left moves the cursor left a column.
+	right      = 0x06 // right moves the cursor right a column.
+	clear      = 0x07 // clear erases the row from the cursor.
+	position   = 0x08 // position moves the cursor to the row and column.
+	insert     = 0x09 // insert turns on the insert mode.
+	scrollUp   = 0x0a // scrollUp scrolls an area of the screen up.
+	scrollDown = 0x0b // scrollDown scrolls an area of the screen down.
+	clearArea  = 0x0c // clearArea erases an area of the screen using an attribute.
+	fillArea   = 0x0d // fillArea fills an area of the screen with a character and an attribute.
+	remove     = 0x0e // remove deletes the character at the cursor.
+	pattern    = 0x19 // pattern repeats a sequence of characters.
+)
+
+const (
+	cyan       = 0x03 // cyan is the color attribute used after the screen is cleared.
+	blinkBit   = 0x80 // blinkBit is the blink bit of a color attribute, which is ignored.
+	incomplete = -1   // incomplete is the size of a sequence that needs more bytes.
+)
+
+// Contains reports whether b contains any Avatar commands.
+// The repeat and clear screen controls are common in other texts,
+// so they are not enough to identify the use of Avatar.
+func Contains(b ...byte) bool {
+	for i := 0; i < len(b); i++ {
+		if b[i] == avt && size(b[i:]) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of Avatar commands and repeat controls in b.
+// A zero value is returned when b does not contain any Avatar commands.
+func Count(b ...byte) int {
+	if !Contains(b...) {
+		return 0
+	}
+	n := 0
+	for i := 0; i < len(b); {
+		s := size(b[i:])
+		if s < 1 {
+			i++
+			continue
+		}
+		n++
+		i += s
+	}
+	return n
+}
+
+// Counter counts the Avatar commands and repeat controls of a text written to it in chunks,
+// where a command split between the writes is counted once.
+type Counter struct {
+	n     int
+	found bool   // found is true once an Avatar command is counted.
+	carry []byte // carry is an incomplete command at the end of the last write.
+}
+
+func (c *Counter) Write(p []byte) (int, error) {
+	b := p
+	if len(c.carry) > 0 {
+		b = append(c.carry, p...)
+	}
+	c.carry = nil
+	c.scan(b, false)
+	return len(p), nil
+}
+
+// Count returns the number of Avatar commands and repeat controls written.
+// A zero value is returned when the text does not contain any Avatar commands.
+func (c *Counter) Count() int {
+	b := c.carry
+	c.carry = nil
+	c.scan(b, true)
+	if !c.found {
+		return 0
+	}
+	return c.n
+}
+
+// scan counts the commands of b, any incomplete command at the end of b is carried
+// to the next write unless it is the end of the text.
+func (c *Counter) scan(b []byte, atEOF bool) {
+	for i := 0; i < len(b); {
+		s := size(b[i:])
+		if s == incomplete && !atEOF {
+			c.carry = append([]byte(nil), b[i:]...)
+			return
+		}
+		if s < 1 {
+			i++
+			continue
+		}
+		c.n++
+		c.found = c.found || b[i] == avt
+		i += s
+	}
+}
+
+// Translate returns b with the Avatar codes replaced by ANSI escape sequences.
+func Translate(b ...byte) []byte {
+	p, _, _ := transform.Bytes(NewTranslator(), b)
+	return p
+}
+
+// NewTranslator returns a transformer that replaces the Avatar codes with ANSI escape sequences.
+func NewTranslator() transform.Transformer {
+	return &translator{}
+}
+
+// translator holds the display attributes and the insert mode of the Avatar codes.
+type translator struct {
+	attr   ansi.Attr
+	insert bool
+	out    bytes.Buffer // out is the translated text that is not yet written.
+}
+
+func (t *translator) Reset() {
+	t.attr, t.insert = ansi.Attr{}, false
+	t.out.Reset()
+}
+
+func (t *translator) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for {
+		n, _ := t.out.Read(dst[nDst:])
+		nDst += n
+		if t.out.Len() > 0 {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		if nSrc == len(src) {
+			return nDst, nSrc, nil
+		}
+		n = t.next(src[nSrc:], atEOF)
+		if n == incomplete {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		nSrc += n
+	}
+}
+
+// next translates the text or the code at the start of b and returns the number of bytes used.
+func (t *translator) next(b []byte, atEOF bool) int {
+	switch b[0] {
+	case ff:
+		t.insert = false
+		t.attr = color(cyan)
+		t.out.WriteString(csi("2J") + t.attr.SGR())
+		return 1
+	case avt, rep:
+		s := size(b)
+		if s == incomplete && !atEOF {
+			return incomplete
+		}
+		if s > 0 {
+			t.code(b[:s]...)
+			return s
+		}
+		t.out.WriteByte(b[0])
+		return 1
+	}
+	i := 1
+	for i < len(b) && b[i] != ff && b[i] != avt && b[i] != rep {
+		i++
+	}
+	t.text(b[:i]...)
+	return i
+}
+
+// text writes the characters, where the insert mode pushes aside the existing characters.
+func (t *translator) text(b ...byte) {
+	const space = 0x20
+	if !t.insert {
+		t.out.Write(b)
+		return
+	}
+	for _, c := range b {
+		if c >= space {
+			t.out.WriteString(csi("@"))
+		}
+		t.out.WriteByte(c)
+	}
+}
+
+// code writes the escape sequences of the Avatar code.
+func (t *translator) code(b ...byte) {
+	if b[0] == rep {
+		t.text(bytes.Repeat(b[1:2], int(b[2]))...)
+		return
+	}
+	if b[1] == insert {
+		t.insert = true
+		return
+	}
+	t.insert = false
+	switch b[1] {
+	case attribute:
+		t.attr = color(b[2])
+		t.out.WriteString(t.attr.SGR())
+	case blink:
+		t.attr.Flags |= ansi.Blink
+		t.out.WriteString(t.attr.SGR())
+	case up:
+		t.out.WriteString(csi("A"))
+	case down:
+		t.out.WriteString(csi("B"))
+	case left:
+		t.out.WriteString(csi("D"))
+	case right:
+		t.out.WriteString(csi("C"))
+	case clear:
+		t.out.WriteString(csi("K"))
+	case position:
+		t.out.WriteString(cup(int(b[2]), int(b[3])))
+	case scrollUp, scrollDown:
+		t.scroll(b[1] == scrollUp, b[2:]...)
+	case clearArea:
+		t.fill(b[2], ' ', int(b[3]), int(b[4]))
+	case fillArea:
+		t.fill(b[2], b[3], int(b[4]), int(b[5]))
+	case remove:
+		t.out.WriteString(csi("P"))
+	case pattern:
+		n := int(b[2])
+		t.out.Write(bytes.Repeat(b[3:3+n], int(b[3+n])))
+	}
+}
+
+// scroll moves the rows of the area up or down by the number of lines in p,
+// followed by the top, left, bottom and right edges of the area.
+// The ANSI.SYS screen has no scrolling regions, so the entire rows
+// of the area are moved by deleting and inserting lines.
+func (t *translator) scroll(up bool, p ...byte) {
+	lines, top, bottom := int(p[0]), int(p[1]), int(p[3])
+	lines = min(lines, bottom-top+1)
+	if lines < 1 || top < 1 {
+		return
+	}
+	from, to := top, bottom-lines+1
+	if !up {
+		from, to = to, from
+	}
+	n := strconv.Itoa(lines)
+	t.out.WriteString(csi("s") + cup(from, 1) + csi(n+"M") + cup(to, 1) + csi(n+"L") + csi("u"))
+}
+
+// fill writes the character using the color attribute to an area of the screen,
+// the area starts at the cursor, which is kept.
+func (t *translator) fill(a, c byte, lines, columns int) {
+	t.attr = color(a)
+	t.out.WriteString(csi("s") + t.attr.SGR())
+	row := bytes.Repeat([]byte{c}, columns)
+	for i := range lines {
+		if i > 0 {
+			t.out.WriteString(csi("u") + csi(strconv.Itoa(i)+"B"))
+		}
+		t.out.Write(row)
+	}
+	t.out.WriteString(csi("u"))
+}
+
+// size returns the number of bytes of the Avatar code at the start of b,
+// or zero when b does not start with a code.
+func size(b []byte) int {
+	n := 0
+	switch b[0] {
+	case rep:
+		n = 3
+	case avt:
+		if len(b) < 2 {
+			return incomplete
+		}
+		p := params(b[1])
+		if p < 0 {
+			return 0
+		}
+		n = 2 + p
+		if b[1] == pattern {
+			if len(b) < 3 {
+				return incomplete
+			}
+			// the number of characters, the characters and the repeat count
+			n = 2 + 1 + int(b[2]) + 1
+		}
+	default:
+		return 0
+	}
+	if len(b) < n {
+		return incomplete
+	}
+	return n
+}
+
+// params returns the number of parameter bytes of the command, or -1 if it is not a command.
+func params(cmd byte) int {
+	switch cmd {
+	case blink, up, down, left, right, clear, insert, remove, pattern:
+		return 0
+	case attribute:
+		return 1
+	case position:
+		return 2
+	case clearArea:
+		return 3
+	case fillArea:
+		return 4
+	case scrollUp, scrollDown:
+		return 5
+	}
+	return -1
+}
+
+// color returns the display attributes of the MS-DOS color attribute,
+// where the bright foreground colors use bold.
+func color(a byte) ansi.Attr {
+	a &^= blinkBit
+	fg, bg := a&0x0f, a>>4
+	attr := ansi.Attr{FG: ansi.Index(index(fg)), BG: ansi.Index(index(bg))}
+	if fg > 0x07 {
+		attr.Flags |= ansi.Bold
+	}
+	return attr
+}
+
+// index returns the ANSI color index of the MS-DOS color,
+// as MS-DOS swaps the order of the blue and red bits.
+func index(c byte) uint8 {
+	return c&0b010 | c&1<<2 | c&4>>2
+}
+
+// csi returns the control sequence of the parameters and the final character.
+func csi(s string) string {
+	return "\x1b[" + s
+}
+
+// cup returns the cursor position control sequence of the one-based row and column.
+func cup(row, col int) string {
+	return csi(strconv.Itoa(row) + ";" + strconv.Itoa(col) + "H")
+}
@@ -0,0 +1,127 @@
+package avatar_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/avatar"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/transform"
+)
+
+const esc = "\x1b["
+
+func ExampleTranslate() {
+	b := avatar.Translate([]byte("\x16\x01\x1eHi\x19!\x03")...)
+	fmt.Printf("%q", b)
+	// Output: "\x1b[0;1;33;44mHi!!!"
+}
+
+func TestContains(t *testing.T) {
+	t.Parallel()
+	be.True(t, avatar.Contains([]byte("\x16\x01\x07hi")...))
+	be.True(t, avatar.Contains([]byte("\x16\x02")...))
+	be.True(t, !avatar.Contains([]byte("\x16\x01")...))
+	be.True(t, !avatar.Contains([]byte("\x16\x7fhi")...))
+	be.True(t, !avatar.Contains([]byte("\x19a\x03\x0c")...))
+	be.True(t, !avatar.Contains())
+}
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, avatar.Count([]byte("\x16\x01\x07hi\x19a\x03\x16\x08\x01\x01")...), 3)
+	be.Equal(t, avatar.Count([]byte("\x19a\x03")...), 0)
+	be.Equal(t, avatar.Count(), 0)
+}
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+	const s = "\x16\x01\x07hi\x19a\x03\x16\x08\x01\x01\x16\x19\x02ab\x03"
+	for _, size := range []int{1, 2, 3, len(s)} {
+		c := avatar.Counter{}
+		for p := []byte(s); len(p) > 0; p = p[min(size, len(p)):] {
+			_, err := c.Write(p[:min(size, len(p))])
+			be.Err(t, err, nil)
+		}
+		be.Equal(t, c.Count(), avatar.Count([]byte(s)...))
+	}
+	c := avatar.Counter{}
+	_, err := c.Write([]byte("\x19a\x03\x16"))
+	be.Err(t, err, nil)
+	be.Equal(t, c.Count(), 0)
+}
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"text", "hello", "hello"},
+		{"attribute", "\x16\x01\x07a", esc + "0;37;40ma"},
+		{"attribute blink bit", "\x16\x01\x8ca", esc + "0;1;31;40ma"},
+		{"blink", "\x16\x01\x02\x16\x02a", esc + "0;32;40m" + esc + "0;5;32;40ma"},
+		{"clear screen", "a\x0cb", "a" + esc + "2J" + esc + "0;36;40mb"},
+		{"repeat", "\x19-\x05", "-----"},
+		{"repeat none", "a\x19-\x00b", "ab"},
+		{"cursor", "\x16\x03\x16\x04\x16\x05\x16\x06", esc + "A" + esc + "B" + esc + "D" + esc + "C"},
+		{"clear line", "\x16\x07", esc + "K"},
+		{"position", "\x16\x08\x05\x0a", esc + "5;10H"},
+		{"insert", "\x16\x09ab\r\n\x16\x05c", esc + "@a" + esc + "@b\r\n" + esc + "Dc"},
+		{"delete", "\x16\x0e", esc + "P"},
+		{"pattern", "\x16\x19\x02ab\x03", "ababab"},
+		{"clear area", "\x16\x0c\x17\x02\x03",
+			esc + "s" + esc + "0;37;44m   " + esc + "u" + esc + "1B   " + esc + "u"},
+		{"fill area", "\x16\x0d\x07#\x01\x02", esc + "s" + esc + "0;37;40m##" + esc + "u"},
+		{"scroll up", "\x16\x0a\x01\x02\x01\x04\x50",
+			esc + "s" + esc + "2;1H" + esc + "1M" + esc + "4;1H" + esc + "1L" + esc + "u"},
+		{"scroll down", "\x16\x0b\x02\x01\x01\x03\x50",
+			esc + "s" + esc + "2;1H" + esc + "2M" + esc + "1;1H" + esc + "2L" + esc + "u"},
+		{"unknown", "\x16\x7fa", "\x16\x7fa"},
+		{"incomplete", "a\x16\x08\x01", "a\x16\x08\x01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, string(avatar.Translate([]byte(tt.s)...)), tt.want)
+		})
+	}
+}
+
+func TestTranslate_screen(t *testing.T) {
+	t.Parallel()
+	b := avatar.Translate([]byte("\x16\x01\x0fabc\x16\x08\x02\x03X\x16\x08\x01\x02\x16\x09Y")...)
+	s := ansi.New(0)
+	_, _ = s.Write(b)
+	be.Equal(t, len(s.Rows()), 2)
+	be.Equal(t, s.Rows()[0][1], ansi.Cell{Rune: 'Y', Attr: ansi.Attr{
+		FG: ansi.Index(7), BG: ansi.Index(0), Flags: ansi.Bold,
+	}})
+	be.Equal(t, s.Rows()[0][2].Rune, 'b')
+	be.Equal(t, s.Rows()[1][2].Rune, 'X')
+}
+
+func TestNewTranslator(t *testing.T) {
+	t.Parallel()
+	// the sequences and the long repeats are split between the reads
+	s := strings.Repeat("\x16\x01\x1e\x19=\xff\x16\x19\x03abc\xffend\r\n", 10)
+	want := avatar.Translate([]byte(s)...)
+	r := transform.NewReader(iotest.OneByteReader(strings.NewReader(s)), avatar.NewTranslator())
+	var sb bytes.Buffer
+	p := make([]byte, 7)
+	for {
+		n, err := r.Read(p)
+		sb.Write(p[:n])
+		if err != nil {
+			be.Err(t, err, io.EOF)
+			break
+		}
+	}
+	be.Equal(t, sb.Bytes(), want)
+}
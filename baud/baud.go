@@ -0,0 +1,57 @@
+// Package baud emulates the transfer speed of the modem connections used by BBS software,
+// so ANSI art and ANSImations are drawn at the pace they were seen online.
+//
+// A modem using 8 data bits, no parity and a stop bit sends 10 bits for each character,
+// so a 2400 baud connection displays 240 characters per second.
+package baud
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+var ErrRate = errors.New("baud rate is not supported")
+
+const (
+	bits = 10 // bits is the number of bits sent for each character, including the start and stop bits.
+	FPS  = 20 // FPS is the number of frames drawn each second.
+)
+
+// Delay is the duration of a frame.
+const Delay = time.Second / FPS
+
+// Rates returns the supported baud rates of the modems.
+func Rates() []int {
+	return []int{300, 1200, 2400, 4800, 9600, 14400, 19200, 28800, 33600, 57600, 115200}
+}
+
+// Valid returns an error when the rate is not one of the supported baud rates.
+func Valid(rate int) error {
+	if !slices.Contains(Rates(), rate) {
+		return fmt.Errorf("%w: %d", ErrRate, rate)
+	}
+	return nil
+}
+
+// CPS returns the number of characters per second sent at the baud rate.
+func CPS(rate int) int {
+	return max(rate/bits, 1)
+}
+
+// Frames splits the runes into the frames that are sent every Delay at the baud rate.
+// Each rune is counted as a character, including the runes of the escape sequences.
+func Frames(rate int, r ...rune) [][]rune {
+	if len(r) == 0 {
+		return nil
+	}
+	cps := CPS(rate)
+	frames := [][]rune{}
+	for i, start := 1, 0; start < len(r); i++ {
+		end := min(max(i*cps/FPS, start+1), len(r))
+		frames = append(frames, r[start:end])
+		start = end
+	}
+	return frames
+}
@@ -0,0 +1,58 @@
+package baud_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/baud"
+	"github.com/nalgeon/be"
+)
+
+func ExampleFrames() {
+	r := []rune(strings.Repeat("x", 100))
+	frames := baud.Frames(2400, r...)
+	fmt.Println(baud.CPS(2400), "characters per second")
+	fmt.Println(len(frames), "frames of", len(frames[0]), "characters")
+	// Output: 240 characters per second
+	// 9 frames of 12 characters
+}
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+	for _, rate := range baud.Rates() {
+		be.Err(t, baud.Valid(rate), nil)
+	}
+	be.Err(t, baud.Valid(0), baud.ErrRate)
+	be.Err(t, baud.Valid(2401), baud.ErrRate)
+}
+
+func TestFrames(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		rate  int
+		size  int
+		want  int
+		first int
+	}{
+		{"300 baud", 300, 10, 7, 1},
+		{"9600 baud", 9600, 1000, 21, 48},
+		{"14400 baud", 14400, 72, 1, 72},
+		{"empty", 2400, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := []rune(strings.Repeat("x", tt.size))
+			frames := baud.Frames(tt.rate, r...)
+			be.Equal(t, len(frames), tt.want)
+			if tt.want == 0 {
+				return
+			}
+			be.Equal(t, len(frames[0]), tt.first)
+			be.Equal(t, slices.Concat(frames...), r)
+		})
+	}
+}
@@ -0,0 +1,566 @@
+// Package bintext decodes the binary text art formats of the PC art scene.
+//
+// Unlike ANSI art, these formats store the screen as a grid of character and
+// attribute pairs, often with an embedded palette and bitmap font.
+// The XBin, Artworx ADF, iCE Draw IDF, BinaryText (BIN) and TundraDraw formats
+// are decoded into rows of cells that use the code page 437 characters.
+package bintext
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image/color"
+	"path/filepath"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/sauce"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	ErrFormat = errors.New("data is not a known binary text format")
+	ErrHeader = errors.New("binary text header is invalid")
+	ErrSize   = errors.New("binary text is too short")
+	ErrWidth  = errors.New("binary text width is invalid")
+)
+
+// Format is a binary text art file format.
+type Format int
+
+const (
+	Unknown Format = iota // Unknown is not a binary text format.
+	XBin                  // XBin is the eXtended BIN format with an optional palette and font.
+	ADF                   // ADF is the Artworx Data Format with a palette and font.
+	IDF                   // IDF is the iCE Draw Format with a palette and font.
+	BIN                   // BIN is the raw BinaryText format of character and attribute pairs.
+	Tundra                // Tundra is the TundraDraw format with 24-bit colors.
+)
+
+// String returns the description of the format.
+func (f Format) String() string {
+	switch f {
+	case XBin:
+		return "XBin text art"
+	case ADF:
+		return "Artworx ADF text art"
+	case IDF:
+		return "iCE Draw IDF text art"
+	case BIN:
+		return "BinaryText art"
+	case Tundra:
+		return "TundraDraw text art"
+	}
+	return ""
+}
+
+// File identifiers.
+const (
+	xbinID   = "XBIN\x1a"
+	idfID    = "\x041."
+	tundraID = "\x18TUNDRA24"
+)
+
+// SAUCE data types.
+const (
+	binaryText = 5
+	xbinType   = 6
+)
+
+const (
+	fontGlyphs = 256      // fontGlyphs is the number of glyphs in a font.
+	vgaHeight  = 16       // vgaHeight is the glyph height of the 8x16 VGA fonts.
+	vgaFont    = 4096     // vgaFont is the size of a 256 glyph 8x16 font.
+	rgbPalette = 16 * 3   // rgbPalette is the size of a palette of 16 colors, using 6-bit RGB values.
+	binWidth   = 160      // binWidth is the usual width of a BIN file without a SAUCE width.
+	adfWidth   = 80       // adfWidth is the width of an ADF file.
+	maxWidth   = 1 << 12  // maxWidth limits the width to protect against invalid headers.
+	maxCells   = 1 << 24  // maxCells limits the size of the image to protect against invalid headers.
+	pair       = 2        // pair is the size of a character and attribute pair.
+	eof        = byte(26) // eof is the MS-DOS end of file marker.
+)
+
+// Font is a bitmap font of 8 pixel wide glyphs,
+// where each glyph is a sequence of bytes with one byte per row.
+type Font struct {
+	Height int    // Height of a glyph in pixels.
+	Bitmap []byte // Bitmap is the raw font data of either 256 or 512 glyphs.
+}
+
+// Glyph returns the rows of the glyph at index i.
+// The most significant bit of each row is the leftmost pixel.
+func (f *Font) Glyph(i int) []byte {
+	n := i * f.Height
+	if f.Height < 1 || i < 0 || n+f.Height > len(f.Bitmap) {
+		return nil
+	}
+	return f.Bitmap[n : n+f.Height]
+}
+
+// Len returns the number of glyphs in the font.
+func (f *Font) Len() int {
+	if f.Height < 1 {
+		return 0
+	}
+	return len(f.Bitmap) / f.Height
+}
+
+// Image is a decoded binary text art file.
+type Image struct {
+	Format  Format        // Format is the file format.
+	Width   int           // Width is the number of columns.
+	Rows    [][]ansi.Cell // Rows are the cells of the image, each row is the width in length.
+	Palette ansi.Palette  // Palette are the 16 colors used by the indexed colors of the cells.
+	Font    *Font         // Font is the embedded bitmap font, or nil for the VGA font.
+	ICE     bool          // ICE uses the bright background colors instead of the blink attribute.
+}
+
+// Detect returns the binary text format of the named file and its bytes,
+// or Unknown if the bytes are not a known binary text format.
+// The ADF and BIN formats have no identifiers, so they rely on the filename extension
+// or the SAUCE data type.
+func Detect(name string, b ...byte) Format {
+	switch {
+	case bytes.HasPrefix(b, []byte(xbinID)):
+		return XBin
+	case bytes.HasPrefix(b, []byte(tundraID)):
+		return Tundra
+	case bytes.HasPrefix(b, []byte(idfID)):
+		return IDF
+	}
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".adf":
+		if len(b) > 1+3*64+vgaFont {
+			return ADF
+		}
+	case ".bin":
+		if data := trim(sauce.Trim(b)); len(data) >= pair && len(data)%pair == 0 {
+			return BIN
+		}
+	}
+	if sauce.Contains(b) {
+		switch uint8(sauce.Decode(b).Data.Type) {
+		case binaryText:
+			return BIN
+		case xbinType:
+			return XBin
+		}
+	}
+	return Unknown
+}
+
+// Decode returns the image of the named binary text art file.
+// The BIN width and the iCE colors use the SAUCE metadata when it is found.
+func Decode(name string, b ...byte) (*Image, error) {
+	f := Detect(name, b...)
+	if f == Unknown {
+		return nil, ErrFormat
+	}
+	width, ice := binWidth, false
+	if sauce.Contains(b) {
+		const nonBlink = 1
+		r := sauce.Decode(b)
+		if uint8(r.Data.Type) == binaryText && uint8(r.File.Type) > 0 {
+			// the BIN file type holds half of the character width
+			width = int(uint8(r.File.Type)) * pair
+		}
+		ice = uint8(r.Info.Flags.Decimal)&nonBlink == nonBlink
+		b = sauce.Trim(b)
+	}
+	switch f {
+	case XBin:
+		return DecodeXBin(b...)
+	case ADF:
+		return DecodeADF(b...)
+	case IDF:
+		return DecodeIDF(b...)
+	case BIN:
+		return DecodeBIN(width, ice, b...)
+	case Tundra:
+		return DecodeTundra(b...)
+	case Unknown:
+	}
+	return nil, ErrFormat
+}
+
+// DecodeXBin returns the image of the XBin data,
+// with the embedded palette and font when they are included.
+func DecodeXBin(b ...byte) (*Image, error) {
+	const (
+		header   = 11
+		palette  = 1 << 0
+		font     = 1 << 1
+		compress = 1 << 2
+		nonBlink = 1 << 3
+		chars512 = 1 << 4
+	)
+	if !bytes.HasPrefix(b, []byte(xbinID)) {
+		return nil, ErrHeader
+	}
+	if len(b) < header {
+		return nil, ErrSize
+	}
+	width := int(binary.LittleEndian.Uint16(b[5:]))
+	height := int(binary.LittleEndian.Uint16(b[7:]))
+	fontSize, flags := int(b[9]), b[10]
+	if err := valid(width, height); err != nil {
+		return nil, err
+	}
+	img := &Image{Format: XBin, Width: width, Palette: ansi.VGA(), ICE: flags&nonBlink != 0}
+	b = b[header:]
+	if flags&palette != 0 {
+		if len(b) < rgbPalette {
+			return nil, ErrSize
+		}
+		img.Palette = rgb(b[:rgbPalette])
+		b = b[rgbPalette:]
+	}
+	if flags&font != 0 {
+		glyphs := fontGlyphs
+		if flags&chars512 != 0 {
+			glyphs *= 2
+		}
+		n := glyphs * fontSize
+		if fontSize < 1 || len(b) < n {
+			return nil, ErrSize
+		}
+		img.Font = &Font{Height: fontSize, Bitmap: b[:n]}
+		b = b[n:]
+	}
+	data := b
+	if flags&compress != 0 {
+		data = unpack(width*height, b)
+	}
+	img.Rows = cells(width, height, img.ICE, flags&chars512 != 0, data)
+	return img, nil
+}
+
+// unpack returns the character and attribute pairs of the XBin compressed data.
+// Each run begins with a byte, where the upper two bits are the compression type
+// and the lower six bits are the length of the run minus one.
+func unpack(n int, b []byte) []byte {
+	const (
+		none = iota
+		char
+		attr
+		both
+	)
+	data := make([]byte, 0, n*pair)
+	for i := 0; i < len(b) && len(data) < n*pair; {
+		kind, count := b[i]>>6, int(b[i]&0x3f)+1
+		i++
+		switch kind {
+		case none:
+			end := min(i+count*pair, len(b))
+			data = append(data, b[i:end]...)
+			i = end
+		case char, attr:
+			if i >= len(b) {
+				return data
+			}
+			c := b[i]
+			i++
+			for range count {
+				if i >= len(b) {
+					return data
+				}
+				if kind == char {
+					data = append(data, c, b[i])
+				} else {
+					data = append(data, b[i], c)
+				}
+				i++
+			}
+		case both:
+			if i+1 >= len(b) {
+				return data
+			}
+			for range count {
+				data = append(data, b[i], b[i+1])
+			}
+			i += pair
+		}
+	}
+	return data
+}
+
+// DecodeADF returns the image of the Artworx ADF data, with its palette and font.
+// ADF always uses the iCE colors and a width of 80 columns.
+func DecodeADF(b ...byte) (*Image, error) {
+	const (
+		header  = 1
+		palette = 64 * 3
+	)
+	if len(b) < header+palette+vgaFont {
+		return nil, ErrSize
+	}
+	// the 16 colors use these indexes of the 64 color EGA palette
+	indexes := [16]int{0, 1, 2, 3, 4, 5, 20, 7, 56, 57, 58, 59, 60, 61, 62, 63}
+	ega := b[header : header+palette]
+	rgbs := make([]byte, 0, rgbPalette)
+	for _, i := range indexes {
+		rgbs = append(rgbs, ega[i*3:i*3+3]...)
+	}
+	img := &Image{Format: ADF, Width: adfWidth, Palette: rgb(rgbs), ICE: true}
+	font := b[header+palette : header+palette+vgaFont]
+	img.Font = &Font{Height: vgaHeight, Bitmap: font}
+	data := trim(b[header+palette+vgaFont:])
+	height := (len(data)/pair + adfWidth - 1) / adfWidth
+	img.Rows = cells(adfWidth, height, true, false, data)
+	return img, nil
+}
+
+// DecodeIDF returns the image of the iCE Draw IDF data, with its palette and font.
+// The image data uses a run-length compression and IDF always uses the iCE colors.
+func DecodeIDF(b ...byte) (*Image, error) {
+	const header = 12
+	if !bytes.HasPrefix(b, []byte(idfID)) {
+		return nil, ErrHeader
+	}
+	if len(b) < header+vgaFont+rgbPalette {
+		return nil, ErrSize
+	}
+	width := int(binary.LittleEndian.Uint16(b[8:])) + 1
+	if err := valid(width, 1); err != nil {
+		return nil, err
+	}
+	end := len(b) - vgaFont - rgbPalette
+	img := &Image{
+		Format:  IDF,
+		Width:   width,
+		Palette: rgb(b[end+vgaFont:]),
+		Font:    &Font{Height: vgaHeight, Bitmap: b[end : end+vgaFont]},
+		ICE:     true,
+	}
+	var data []byte
+	for i := header; i+1 < end && len(data) < maxCells*pair; {
+		// a 0x01, 0x00 pair is followed by the run length, and the repeated pair
+		const runLen = 6
+		if b[i] == 1 && b[i+1] == 0 && i+runLen <= end {
+			count := int(binary.LittleEndian.Uint16(b[i+2:]))
+			for range count {
+				data = append(data, b[i+4], b[i+5])
+			}
+			i += runLen
+			continue
+		}
+		data = append(data, b[i], b[i+1])
+		i += pair
+	}
+	height := (len(data)/pair + width - 1) / width
+	img.Rows = cells(width, height, true, false, data)
+	return img, nil
+}
+
+// DecodeBIN returns the image of the BinaryText data using the width in columns,
+// where ice uses the iCE colors instead of the blink attribute.
+func DecodeBIN(width int, ice bool, b ...byte) (*Image, error) {
+	if err := valid(width, 1); err != nil {
+		return nil, err
+	}
+	data := trim(b)
+	if len(data) < pair {
+		return nil, ErrSize
+	}
+	height := (len(data)/pair + width - 1) / width
+	img := &Image{Format: BIN, Width: width, Palette: ansi.VGA(), ICE: ice}
+	img.Rows = cells(width, height, ice, false, data)
+	return img, nil
+}
+
+// DecodeTundra returns the image of the TundraDraw data.
+// The colors of the cells use 24-bit RGB values and the width is 80 columns.
+func DecodeTundra(b ...byte) (*Image, error) {
+	const (
+		width    = 80
+		position = 1
+		fg       = 2
+		bg       = 4
+		both     = 6
+		rgbSize  = 4 // rgbSize is the size of a color value, an unused byte and the RGB bytes.
+		point    = 8 // point is the size of a position value, the row and column.
+	)
+	if !bytes.HasPrefix(b, []byte(tundraID)) {
+		return nil, ErrHeader
+	}
+	img := &Image{Format: Tundra, Width: width, Palette: ansi.VGA()}
+	attr := ansi.Attr{FG: ansi.Index(lightGray), BG: ansi.Index(black)}
+	x, y := 0, 0
+	put := func(c byte) {
+		for len(img.Rows) <= y {
+			img.Rows = append(img.Rows, blankRow(width))
+		}
+		img.Rows[y][x] = ansi.Cell{Rune: glyphs[c], Attr: attr}
+		x++
+		if x >= width {
+			x, y = 0, y+1
+		}
+	}
+	truecolor := func(p []byte) ansi.Color {
+		return ansi.TrueColor(p[1], p[2], p[3])
+	}
+	for i := len(tundraID); i < len(b) && y*width < maxCells; i++ {
+		cmd := b[i]
+		switch {
+		case cmd == position && i+point < len(b):
+			y = int(binary.BigEndian.Uint32(b[i+1:]))
+			x = int(binary.BigEndian.Uint32(b[i+5:]))
+			if x >= width || y*width >= maxCells {
+				return nil, fmt.Errorf("%w: position %d,%d", ErrHeader, x, y)
+			}
+			i += point
+		case cmd == fg && i+1+rgbSize < len(b):
+			attr.FG = truecolor(b[i+2:])
+			put(b[i+1])
+			i += 1 + rgbSize
+		case cmd == bg && i+1+rgbSize < len(b):
+			attr.BG = truecolor(b[i+2:])
+			put(b[i+1])
+			i += 1 + rgbSize
+		case cmd == both && i+1+rgbSize*2 < len(b):
+			attr.FG, attr.BG = truecolor(b[i+2:]), truecolor(b[i+2+rgbSize:])
+			put(b[i+1])
+			i += 1 + rgbSize*2
+		default:
+			put(cmd)
+		}
+	}
+	return img, nil
+}
+
+// valid returns an error when the width or height are not usable.
+func valid(width, height int) error {
+	if width < 1 || width > maxWidth || height < 0 || width*height > maxCells {
+		return fmt.Errorf("%w: %d columns and %d rows", ErrWidth, width, height)
+	}
+	return nil
+}
+
+// trim removes a trailing end of file marker that leaves an incomplete pair.
+func trim(b []byte) []byte {
+	if len(b)%pair == 1 && b[len(b)-1] == eof {
+		return b[:len(b)-1]
+	}
+	return b
+}
+
+// ansiIndex returns the ANSI color index of the DOS color index,
+// as DOS swaps the order of the blue and red bits.
+func ansiIndex(i byte) uint8 {
+	return i&0b1010 | i&1<<2 | i&4>>2
+}
+
+// rgb returns the palette of the 16 colors using 6-bit RGB values in the DOS order.
+func rgb(b []byte) ansi.Palette {
+	p := ansi.VGA()
+	for i := range p {
+		if i*3+2 >= len(b) {
+			break
+		}
+		scale := func(v byte) uint8 {
+			v &= 0x3f
+			return v<<2 | v>>4
+		}
+		p[ansiIndex(byte(i))] = color.RGBA{scale(b[i*3]), scale(b[i*3+1]), scale(b[i*3+2]), 0xff}
+	}
+	return p
+}
+
+// VGA color indexes used as the defaults.
+const (
+	black     = 0
+	lightGray = 7
+)
+
+// glyphs are the code page 437 characters, including the pictures of the control codes.
+var glyphs = func() [256]rune {
+	var t [256]rune
+	for i := range t {
+		t[i] = charmap.CodePage437.DecodeByte(byte(i))
+	}
+	copy(t[1:], []rune("☺☻♥♦♣♠•◘○◙♂♀♪♫☼►◄↕‼¶§▬↨↑↓→←∟↔▲▼"))
+	t[0x00], t[0x7f], t[0xff] = ' ', '⌂', ' '
+	return t
+}()
+
+// cells returns the rows of cells of the character and attribute pairs.
+// The attributes use the DOS color order, which are converted to the ANSI color indexes.
+// The low nibble of the attribute is the foreground color and the high nibble the background,
+// where the high bit is either the blink attribute or a bright background with the iCE colors.
+// With the 512 character fonts, bit 3 of the attribute selects the second half of the font.
+func cells(width, height int, ice, chars512 bool, data []byte) [][]ansi.Cell {
+	rows := make([][]ansi.Cell, height)
+	for y := range rows {
+		rows[y] = blankRow(width)
+	}
+	for i := 0; i+1 < len(data) && i/pair < width*height; i += pair {
+		c, a := data[i], data[i+1]
+		fg, bg := a&0x0f, a>>4
+		if chars512 {
+			fg &= 0x07
+		}
+		attr := ansi.Attr{FG: ansi.Index(ansiIndex(fg)), BG: ansi.Index(ansiIndex(bg))}
+		if !ice {
+			attr.BG = ansi.Index(ansiIndex(bg & 0x07))
+			if bg&0x08 != 0 {
+				attr.Flags |= ansi.Blink
+			}
+		}
+		n := i / pair
+		rows[n/width][n%width] = ansi.Cell{Rune: glyphs[c], Attr: attr}
+	}
+	return rows
+}
+
+// blankRow returns a row of spaces using the light gray on black colors.
+func blankRow(width int) []ansi.Cell {
+	row := make([]ansi.Cell, width)
+	for i := range row {
+		row[i] = ansi.Cell{Rune: ' ', Attr: ansi.Attr{FG: ansi.Index(lightGray), BG: ansi.Index(black)}}
+	}
+	return row
+}
+
+// Custom reports whether the image uses a palette that differs from the VGA palette.
+func (img *Image) Custom() bool {
+	return img.Palette != ansi.VGA()
+}
+
+// String returns the image as text using SGR escape sequences for the colors.
+// The VGA light gray and black use the terminal default colors, while the images
+// with a custom palette use its 24-bit RGB colors.
+func (img *Image) String() string {
+	sb := &strings.Builder{}
+	custom := img.Custom()
+	for y, row := range img.Rows {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		cells := make([]ansi.Cell, len(row))
+		for x, c := range row {
+			c.Attr.FG = img.color(c.Attr.FG, lightGray, custom)
+			c.Attr.BG = img.color(c.Attr.BG, black, custom)
+			cells[x] = c
+		}
+		ansi.Line(sb, cells...)
+	}
+	return sb.String()
+}
+
+// color returns the terminal color of the indexed color,
+// the fallback index uses the terminal default color.
+func (img *Image) color(c ansi.Color, fallback uint8, custom bool) ansi.Color {
+	if c.Mode != ansi.Indexed {
+		return c
+	}
+	if custom {
+		v, _ := img.Palette.RGBA(c)
+		return ansi.TrueColor(v.R, v.G, v.B)
+	}
+	if c.Index == fallback {
+		return ansi.Color{}
+	}
+	return c
+}
@@ -0,0 +1,205 @@
+package bintext_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/bintext"
+	"github.com/nalgeon/be"
+)
+
+// xbin returns an XBin file of the width and height, using the flags and the optional data.
+func xbin(width, height uint16, flags byte, data ...byte) []byte {
+	b := []byte("XBIN\x1a")
+	b = binary.LittleEndian.AppendUint16(b, width)
+	b = binary.LittleEndian.AppendUint16(b, height)
+	b = append(b, 16, flags)
+	return append(b, data...)
+}
+
+// idf returns an iCE Draw file of the width, with the data, a blank font and a palette.
+func idf(width uint16, data ...byte) []byte {
+	b := []byte("\x041.4")
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, width-1)
+	b = binary.LittleEndian.AppendUint16(b, 0)
+	b = append(b, data...)
+	b = append(b, make([]byte, 4096)...)
+	return append(b, rgbs()...)
+}
+
+// rgbs returns a 6-bit RGB palette where the first color is dark red.
+func rgbs() []byte {
+	p := make([]byte, 48)
+	p[0] = 0x20
+	return p
+}
+
+func ExampleDecodeBIN() {
+	img, _ := bintext.DecodeBIN(4, false, 'H', 0x0f, 'i', 0x1e, '!', 0x07, 0x01, 0x8c)
+	fmt.Println(img.Format, len(img.Rows), img.Rows[0][1].Rune)
+	fmt.Printf("%q", img.String())
+	// Output: BinaryText art 1 105
+	// "\x1b[0;97mH\x1b[0;93;44mi\x1b[0m!\x1b[0;5;91m☺\x1b[0m"
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		b    []byte
+		want bintext.Format
+	}{
+		{"art.xb", xbin(1, 1, 0), bintext.XBin},
+		{"", idf(80), bintext.IDF},
+		{"", []byte("\x18TUNDRA24A"), bintext.Tundra},
+		{"art.ADF", make([]byte, 4300), bintext.ADF},
+		{"art.adf", make([]byte, 10), bintext.Unknown},
+		{"art.bin", []byte("A\x07"), bintext.BIN},
+		{"art.bin", []byte("A\x07B"), bintext.Unknown},
+		{"art.bin", []byte("A\x07\x1a"), bintext.BIN},
+		{"art.txt", []byte("A\x07"), bintext.Unknown},
+		{"", nil, bintext.Unknown},
+	}
+	for _, tt := range tests {
+		be.Equal(t, bintext.Detect(tt.name, tt.b...), tt.want)
+	}
+	be.Equal(t, bintext.XBin.String(), "XBin text art")
+	be.Equal(t, bintext.Unknown.String(), "")
+}
+
+func TestDecodeXBin(t *testing.T) {
+	t.Parallel()
+	const palette, font, compress, nonBlink, chars512 = 1, 2, 4, 8, 16
+	t.Run("raw", func(t *testing.T) {
+		t.Parallel()
+		img, err := bintext.DecodeXBin(xbin(2, 2, 0, 'A', 0x07, 'B', 0x8f, 'C', 0x10)...)
+		be.Err(t, err, nil)
+		be.Equal(t, img.Width, 2)
+		be.Equal(t, len(img.Rows), 2)
+		be.Equal(t, img.Rows[0][1], ansi.Cell{Rune: 'B', Attr: ansi.Attr{
+			FG: ansi.Index(15), BG: ansi.Index(0), Flags: ansi.Blink,
+		}})
+		be.Equal(t, img.Rows[1][0].Attr.BG, ansi.Index(4))
+		be.Equal(t, img.Rows[1][1].Rune, ' ')
+		be.True(t, img.Font == nil)
+		be.True(t, !img.Custom())
+	})
+	t.Run("palette and font", func(t *testing.T) {
+		t.Parallel()
+		data := append(rgbs(), bytes.Repeat([]byte{0xff}, 256*16)...)
+		data = append(data, 'A', 0x80)
+		img, err := bintext.DecodeXBin(xbin(1, 1, palette|font|nonBlink, data...)...)
+		be.Err(t, err, nil)
+		be.Equal(t, img.Palette[0], color.RGBA{0x82, 0, 0, 0xff})
+		be.True(t, img.Custom())
+		be.True(t, img.ICE)
+		be.Equal(t, img.Font.Len(), 256)
+		be.Equal(t, img.Font.Glyph(1), bytes.Repeat([]byte{0xff}, 16))
+		be.Equal(t, img.Rows[0][0].Attr.BG, ansi.Index(8))
+		be.Equal(t, img.String(), "\x1b[0;38;2;130;0;0;48;2;0;0;0mA\x1b[0m")
+	})
+	t.Run("512 characters", func(t *testing.T) {
+		t.Parallel()
+		data := append(make([]byte, 512*16), 'A', 0x0f)
+		img, err := bintext.DecodeXBin(xbin(1, 1, font|chars512, data...)...)
+		be.Err(t, err, nil)
+		be.Equal(t, img.Font.Len(), 512)
+		be.Equal(t, img.Rows[0][0].Attr.FG, ansi.Index(7))
+	})
+	t.Run("compressed", func(t *testing.T) {
+		t.Parallel()
+		data := []byte{
+			0x01, 'A', 0x07, 'B', 0x07, // two uncompressed pairs
+			0x41, 'C', 0x01, 0x02, // the character with two attributes
+			0x81, 0x04, 'D', 'E', // the attribute with two characters
+			0xc1, 'F', 0x05, // the pair repeated twice
+		}
+		img, err := bintext.DecodeXBin(xbin(4, 2, compress, data...)...)
+		be.Err(t, err, nil)
+		var s []rune
+		for _, row := range img.Rows {
+			for _, c := range row {
+				s = append(s, c.Rune)
+			}
+		}
+		be.Equal(t, string(s), "ABCCDEFF")
+		be.Equal(t, img.Rows[0][3].Attr.FG, ansi.Index(2))
+		be.Equal(t, img.Rows[1][0].Attr.FG, ansi.Index(1))
+	})
+	t.Run("errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := bintext.DecodeXBin([]byte("XBIN")...)
+		be.Err(t, err, bintext.ErrHeader)
+		_, err = bintext.DecodeXBin(xbin(0, 1, 0)...)
+		be.Err(t, err, bintext.ErrWidth)
+		_, err = bintext.DecodeXBin(xbin(1, 1, palette)...)
+		be.Err(t, err, bintext.ErrSize)
+	})
+}
+
+func TestDecodeADF(t *testing.T) {
+	t.Parallel()
+	b := make([]byte, 1+192+4096)
+	b[0] = 1
+	// the brown color uses the EGA palette index 20
+	b[1+20*3], b[1+20*3+1] = 0x2a, 0x15
+	b = append(b, bytes.Repeat([]byte{'x', 0xf6}, 81)...)
+	img, err := bintext.DecodeADF(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Width, 80)
+	be.Equal(t, len(img.Rows), 2)
+	be.Equal(t, img.Palette[3], color.RGBA{0xaa, 0x55, 0, 0xff})
+	be.True(t, img.ICE)
+	be.Equal(t, img.Rows[1][0].Attr, ansi.Attr{FG: ansi.Index(3), BG: ansi.Index(15)})
+	be.Equal(t, img.Font.Len(), 256)
+	_, err = bintext.DecodeADF(1, 2, 3)
+	be.Err(t, err, bintext.ErrSize)
+}
+
+func TestDecodeIDF(t *testing.T) {
+	t.Parallel()
+	data := []byte{'A', 0x07, 0x01, 0x00, 0x03, 0x00, 'B', 0x4e}
+	img, err := bintext.DecodeIDF(idf(2, data...)...)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Width, 2)
+	be.Equal(t, len(img.Rows), 2)
+	be.Equal(t, img.Rows[1][1], ansi.Cell{Rune: 'B', Attr: ansi.Attr{FG: ansi.Index(11), BG: ansi.Index(1)}})
+	be.Equal(t, img.Palette[0], color.RGBA{0x82, 0, 0, 0xff})
+	_, err = bintext.DecodeIDF([]byte("\x041.4")...)
+	be.Err(t, err, bintext.ErrSize)
+}
+
+func TestDecodeTundra(t *testing.T) {
+	t.Parallel()
+	b := []byte("\x18TUNDRA24A")
+	b = append(b, 2, 'B', 0, 0xff, 0x80, 0)
+	b = append(b, 1, 0, 0, 0, 1, 0, 0, 0, 79)
+	b = append(b, 6, 'C', 0, 1, 2, 3, 0, 4, 5, 6, 'D')
+	img, err := bintext.DecodeTundra(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, len(img.Rows), 3)
+	be.Equal(t, img.Rows[0][1], ansi.Cell{Rune: 'B', Attr: ansi.Attr{
+		FG: ansi.TrueColor(0xff, 0x80, 0), BG: ansi.Index(0),
+	}})
+	be.Equal(t, img.Rows[1][79].Attr.BG, ansi.TrueColor(4, 5, 6))
+	be.Equal(t, img.Rows[2][0].Rune, 'D')
+	_, err = bintext.DecodeTundra([]byte("\x18TUNDRA24\x01\x00\x00\x00\x00\x00\x00\x01\x00")...)
+	be.Err(t, err, bintext.ErrHeader)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	img, err := bintext.Decode("art.bin", bytes.Repeat([]byte{0xdb, 0x04}, 161)...)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Width, 160)
+	be.Equal(t, len(img.Rows), 2)
+	be.Equal(t, img.Rows[0][0].Rune, '█')
+	_, err = bintext.Decode("readme.txt", []byte("hello")...)
+	be.Err(t, err, bintext.ErrFormat)
+}
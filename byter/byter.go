@@ -64,6 +64,88 @@ func TrimEOF(b []byte) []byte {
 	return b
 }
 
+// EOFReader returns a reader that stops at the first end-of-file marker read from r.
+// The markers are the same as those used by TrimEOF, however the stream is cut
+// at whichever marker occurs first.
+func EOFReader(r io.Reader) io.Reader {
+	return &eofReader{r: r}
+}
+
+// eofReader is the reader returned by EOFReader.
+type eofReader struct {
+	r    io.Reader
+	held []byte // held are the trailing bytes that may start a multi-byte marker.
+	n    int64  // n is the number of bytes returned.
+	done bool
+}
+
+func (e *eofReader) Read(p []byte) (int, error) {
+	if e.done {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+	n := copy(p, e.held)
+	e.held = e.held[n:]
+	m, err := e.r.Read(p[n:])
+	n += m
+	if cut := eofIndex(e.n, p[:n]); cut >= 0 {
+		e.done = true
+		e.n += int64(cut)
+		return cut, io.EOF
+	}
+	if err == nil && m > 0 {
+		// hold back the start of a marker that is split between reads
+		k := partialMarker(p[:n])
+		e.held = append(e.held, p[n-k:n]...)
+		n -= k
+	}
+	e.n += int64(n)
+	return n, err //nolint:wrapcheck
+}
+
+// eofIndex returns the index of the first end-of-file marker in b,
+// or -1 if there is none. The offset is the position of b in the stream,
+// a marker at the start of the stream is ignored.
+func eofIndex(offset int64, b []byte) int {
+	cut := -1
+	for _, marker := range eofMarkers() {
+		start := 0
+		if offset == 0 && bytes.HasPrefix(b, marker) {
+			start = len(marker)
+		}
+		i := bytes.Index(b[start:], marker)
+		if i < 0 {
+			continue
+		}
+		if i += start; cut < 0 || i < cut {
+			cut = i
+		}
+	}
+	return cut
+}
+
+// partialMarker returns the number of trailing bytes of b that could start an end-of-file marker.
+func partialMarker(b []byte) int {
+	for k := utf8.UTFMax - 1; k > 0; k-- {
+		if len(b) < k {
+			continue
+		}
+		for _, marker := range eofMarkers() {
+			if len(marker) > k && bytes.HasSuffix(b, marker[:k]) {
+				return k
+			}
+		}
+	}
+	return 0
+}
+
+// eofMarkers returns the end-of-file markers as bytes.
+func eofMarkers() [][]byte {
+	return [][]byte{{SUB}, []byte(string(rune(SymbolSUB))), []byte(string(rune(DosSUB)))}
+}
+
 // MakeBytes generates a 256 character or 8-bit container ready to hold legacy code point values.
 func MakeBytes() []byte {
 	const size = 256
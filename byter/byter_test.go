@@ -2,11 +2,15 @@ package byter_test
 
 import (
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/bengarrett/retrotxtgo/byter"
 	"github.com/bengarrett/retrotxtgo/internal/mock"
+	"github.com/nalgeon/be"
 	"golang.org/x/text/encoding/charmap"
 )
 
@@ -83,6 +87,33 @@ func TestTrimEOF(t *testing.T) {
 	})
 }
 
+func TestEOFReader(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"empty", "", ""},
+		{"none", "hello world", "hello world"},
+		{"sub", "hello\x1aworld\x1ahidden", "hello"},
+		{"start", "\x1ahello\x1aworld", "\x1ahello"},
+		{"symbol", "hello␚world", "hello"},
+		{"arrow", "hello world→", "hello world"},
+		{"first", "hello→world\x1a", "hello"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			// a one byte reader splits the multi-byte markers between reads
+			r := byter.EOFReader(iotest.OneByteReader(strings.NewReader(tt.s)))
+			b, err := io.ReadAll(r)
+			be.Err(t, err, nil)
+			be.Equal(t, string(b), tt.want)
+		})
+	}
+}
+
 func TestMakeBytes(t *testing.T) {
 	t.Parallel()
 	if l := len(byter.MakeBytes()); l != 256 {
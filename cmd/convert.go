@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/convert"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+const convertLong = `Convert text files and text art to UTF-8 and save them for use in other apps.
+
+The convert command reads the files in the same way as the view command,
+so the --input flag sets the character encoding of the legacy 8-bit texts.
+The filenames can also be glob patterns, such as *.txt or art/*.ans.
+
+Unlike the view command, any ANSI controls are kept in the saved text
+and are not drawn onto a virtual screen. Texts that use iCE colors,
+either set by the SAUCE non-blink flag or the --ice flag, have the blink
+attribute replaced by bright background colors.
+
+The converted texts are saved beside the original files using a
+.utf8.txt extension, but piped text is written to the standard output.
+The --output flag saves the texts using a different encoding, see the
+encode command for the handling of the unmappable characters.`
+
+func ConvertCommand() *cobra.Command {
+	s := "Convert text files to UTF-8 and save them"
+	expl := strings.Builder{}
+	example.Convert.String(&expl)
+	return &cobra.Command{
+		Use:     "convert " + example.Filenames,
+		Aliases: []string{"c", "utf8"},
+		GroupID: IDfile,
+		Short:   s,
+		Long:    convertLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return convert.Run(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func ConvertInit() *cobra.Command {
+	cc := ConvertCommand()
+	f := flag.View()
+	flag.Encode(&f.Input, cc)
+	flag.BBS(&f.BBS, cc)
+	flag.ICE(&f.ICE, cc)
+	s := &strings.Builder{}
+	term.Options(s, "line break of the converted text (default keeps the original)", false, true,
+		"lf", "crlf", "cr")
+	cc.Flags().StringVarP(&flag.Convert.LineBreak, "line-break", "l", "", s.String())
+	flag.Output(&flag.Convert.Output, "UTF-8", cc)
+	flag.Unmappable(&flag.Convert.Unmappable, cc)
+	cc.Flags().BoolVar(&flag.Convert.BOM, "bom", false,
+		"add a UTF-8 byte order mark to the converted text")
+	cc.Flags().BoolVar(&flag.Convert.StripBOM, "strip-bom", false,
+		"remove any UTF-8 byte order mark from the converted text")
+	cc.MarkFlagsMutuallyExclusive("bom", "strip-bom")
+	cc.Flags().StringVarP(&flag.Convert.OutputDir, "output-dir", "o", "",
+		"directory to save the texts (default is the directory of each file)")
+	cc.Flags().BoolVar(&flag.Convert.Overwrite, "overwrite", false,
+		"overwrite any existing texts instead of using a unique filename")
+	cc.Flags().SortFlags = false
+	return cc
+}
+
+func init() {
+	Cmd.AddCommand(ConvertInit())
+}
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/encode"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/spf13/cobra"
+)
+
+const encodeLong = `Encode UTF-8 text files into a legacy code page and save them.
+
+The encode command is the reverse of the convert command, it reads
+modern UTF-8 texts and saves them using a legacy 8-bit code page,
+such as Code Page 437 for BBS releases, ISO 8859-1, Shift JIS or
+an IBM EBCDIC code page. The filenames can also be glob patterns.
+
+Characters that do not exist in the code page are unmappable and
+are handled by the --unmappable flag. They can stop the encoding
+with an error, be replaced with a question mark, or be replaced with
+the nearest glyph, such as a straight " quote for the curly “ quote.
+
+The encoded texts are saved beside the original files using the name
+of the code page as the extension, but piped text is written to the
+standard output.`
+
+func EncodeCommand() *cobra.Command {
+	s := "Encode UTF-8 text files into a legacy code page"
+	expl := strings.Builder{}
+	example.Encode.String(&expl)
+	return &cobra.Command{
+		Use:     "encode " + example.Filenames,
+		Aliases: []string{"en"},
+		GroupID: IDfile,
+		Short:   s,
+		Long:    encodeLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return encode.Run(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func EncodeInit() *cobra.Command {
+	ec := EncodeCommand()
+	flag.Output(&flag.Convert.Output, encode.Default, ec)
+	flag.Unmappable(&flag.Convert.Unmappable, ec)
+	ec.Flags().StringVarP(&flag.Convert.OutputDir, "output-dir", "o", "",
+		"directory to save the texts (default is the directory of each file)")
+	ec.Flags().BoolVar(&flag.Convert.Overwrite, "overwrite", false,
+		"overwrite any existing texts instead of using a unique filename")
+	ec.Flags().SortFlags = false
+	return ec
+}
+
+func init() {
+	Cmd.AddCommand(EncodeInit())
+}
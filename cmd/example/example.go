@@ -29,6 +29,12 @@ const (
 	Info                    // Info is the example for the info command.
 	View                    // View is the example for the view command.
 	Dump                    // Dump is the example for the dump command.
+	Export                  // Export is the example for the export command.
+	Render                  // Render is the example for the render command.
+	Convert                 // Convert is the example for the convert command.
+	Encode                  // Encode is the example for the encode command.
+	Sauce                   // Sauce is the example for the sauce command.
+	Music                   // Music is the example for the music command.
 )
 
 // String writes the example usage help.
@@ -87,6 +93,18 @@ func (e Example) result() string {
 		return view()
 	case Dump:
 		return dump()
+	case Export:
+		return export()
+	case Render:
+		return render()
+	case Convert:
+		return convert()
+	case Encode:
+		return encode()
+	case Sauce:
+		return sauce()
+	case Music:
+		return music()
 	}
 	return ""
 }
@@ -95,6 +113,12 @@ func cmd() string {
 	s := &strings.Builder{}
 	fmt.Fprintf(s, "  %s info %s\t\t# Analyze file encoding and metadata\n", meta.Bin, Filenames)
 	fmt.Fprintf(s, "  %s view %s\t\t# Display legacy text files with proper encoding\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s export %s\t\t# Save text files and art as HTML documents\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s render %s\t\t# Save text files and art as PNG images\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s convert %s\t# Save text files as UTF-8 texts\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s encode %s\t\t# Save UTF-8 texts using a legacy code page\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s sauce set %s\t# Add or update the SAUCE metadata\n", meta.Bin, Filenames)
+	fmt.Fprintf(s, "  %s music %s\t\t# Save the ANSI music of art as MIDI files\n", meta.Bin, Filenames)
 	fmt.Fprintf(s, "  %s example\t\t\t# Browse and view built-in sample files\n\n", meta.Bin)
 
 	fmt.Fprintf(s, "  %s list\t\t\t\t# List supported legacy code pages\n", meta.Bin)
@@ -139,6 +163,12 @@ func view() string {
 	s := &strings.Builder{}
 	fmt.Fprintf(s, "  %s view file.txt -i latin1\n", meta.Bin)
 	fmt.Fprintf(s, "  %s view file1.txt file2.txt --input \"iso-8859-1\"\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view file.txt --input auto\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view file.txt --bbs pcboard\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view file.nfo --pager\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view animation.ans --baud 2400\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view file.ans --palette c64\n", meta.Bin)
+	fmt.Fprintf(s, "  %s view manual.txt --width 80 --wrap soft\n", meta.Bin)
 	fmt.Fprintf(s, "  cat file.txt | %s view", meta.Bin)
 	return s.String()
 }
@@ -150,3 +180,53 @@ func dump() string {
 	fmt.Fprintf(s, "  cat file.txt | %s dump", meta.Bin)
 	return s.String()
 }
+
+func export() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s export file.ans\n", meta.Bin)
+	fmt.Fprintf(s, "  %s export file1.ans file2.txt --output-dir html\n", meta.Bin)
+	fmt.Fprintf(s, "  cat file.ans | %s export > file.html", meta.Bin)
+	return s.String()
+}
+
+func render() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s render file.ans\n", meta.Bin)
+	fmt.Fprintf(s, "  %s render file1.ans file2.txt --font cga --output-dir png\n", meta.Bin)
+	fmt.Fprintf(s, "  %s render animation.ans --baud 9600\n", meta.Bin)
+	fmt.Fprintf(s, "  cat file.ans | %s render > file.png", meta.Bin)
+	return s.String()
+}
+
+func convert() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s convert file.txt\n", meta.Bin)
+	fmt.Fprintf(s, "  %s convert \"*.nfo\" --line-break crlf --output-dir utf8\n", meta.Bin)
+	fmt.Fprintf(s, "  cat file.txt | %s convert > file.utf8.txt", meta.Bin)
+	return s.String()
+}
+
+func encode() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s encode file.txt\n", meta.Bin)
+	fmt.Fprintf(s, "  %s encode file.txt --output latin1 --unmappable translit\n", meta.Bin)
+	fmt.Fprintf(s, "  cat file.txt | %s encode --output cp037 > file.ebcdic", meta.Bin)
+	return s.String()
+}
+
+func sauce() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s sauce set file.ans --title \"My art\" --author me --date 19960131\n", meta.Bin)
+	fmt.Fprintf(s, "  %s sauce set file.ans -c \"first comment\" -c \"second comment\"\n", meta.Bin)
+	fmt.Fprintf(s, "  %s sauce strip file.ans\n", meta.Bin)
+	fmt.Fprintf(s, "  %s sauce copy original.ans copy.ans", meta.Bin)
+	return s.String()
+}
+
+func music() string {
+	s := &strings.Builder{}
+	fmt.Fprintf(s, "  %s music file.ans\n", meta.Bin)
+	fmt.Fprintf(s, "  %s music file1.ans file2.ans --output-dir midi\n", meta.Bin)
+	fmt.Fprintf(s, "  cat file.ans | %s music > file.mid", meta.Bin)
+	return s.String()
+}
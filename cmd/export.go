@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/format"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+const exportLong = `Save text files and text art as standalone documents for use on the web.
+
+The export command reads the files in the same way as the view command,
+so the --input flag sets the character encoding of the legacy 8-bit texts.
+
+The HTML format creates a self-contained document with the colors of
+any ANSI art written as CSS classes. Files with SAUCE metadata use the
+title, author and comments for the document title and meta tags, and
+the SAUCE non-blink mode is used for iCE color backgrounds.
+
+The documents are saved using the filename with a .html extension,
+but piped text is written to the standard output.`
+
+func ExportCommand() *cobra.Command {
+	s := "Save text files and art as HTML documents"
+	expl := strings.Builder{}
+	example.Export.String(&expl)
+	return &cobra.Command{
+		Use:     "export " + example.Filenames,
+		Aliases: []string{"e"},
+		GroupID: IDfile,
+		Short:   s,
+		Long:    exportLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return export.Run(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func ExportInit() *cobra.Command {
+	ec := ExportCommand()
+	f := flag.View()
+	s := &strings.Builder{}
+	formats := format.Format().Export
+	term.Options(s, "output format", true, true, formats[:]...)
+	ec.Flags().StringVarP(&flag.Export.Format, "format", "f", "html", s.String())
+	flag.Encode(&f.Input, ec)
+	flag.BBS(&f.BBS, ec)
+	ec.Flags().StringVarP(&flag.Export.OutputDir, "output-dir", "o", "",
+		"directory to save the documents (default is the current directory)")
+	ec.Flags().BoolVar(&flag.Export.Overwrite, "overwrite", false,
+		"overwrite any existing documents instead of using a unique filename")
+	ec.Flags().SortFlags = false
+	return ec
+}
+
+func init() {
+	Cmd.AddCommand(ExportInit())
+}
@@ -0,0 +1,270 @@
+// Package convert provides the convert command run function.
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/byter"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/view"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// Ext is the file extension of the converted UTF-8 files.
+const Ext = ".utf8.txt"
+
+var (
+	ErrLineBreak = errors.New("line break is not known")
+	ErrPipeRead  = errors.New("could not read text stream from piped stdin (standard input)")
+)
+
+// Run parses the arguments supplied with the convert command.
+// Each converted text is saved as a file, except for piped input which is written to w.
+func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd convert run"
+	if w == nil {
+		w = io.Discard
+	}
+	lb, err := LineBreak(flag.Convert.LineBreak)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	out, policy, err := Output(flag.Convert.Output, flag.Convert.Unmappable)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	// piped input from other programs and then exit
+	ok, err := fsys.IsPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if ok {
+		return Pipe(w, cmd, args...)
+	}
+	// read from files, globs or samples
+	args, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if args, err = Globs(args...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for i, arg := range args {
+		if i == 0 && arg == "" {
+			return nil
+		}
+		target := Filename(arg, flag.Convert.OutputDir, Extension(out))
+		if Same(arg, target) {
+			fmt.Fprintln(w, "Skipped", term.Secondary(arg), "as it would replace itself")
+			continue
+		}
+		b, err := flag.ReadArgument(arg, c, samp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r, err := view.Transform(c, flag.Input(cmd, samp, arg, b...), nil, b...)
+		if err != nil {
+			return err
+		}
+		r = ICE(cmd, r, b...)
+		p, err := Encode(out, policy, Bytes(r, lb, flag.Convert.BOM, flag.Convert.StripBOM)...)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", name, arg, err)
+		}
+		path, err := export.Save(target, flag.Convert.Overwrite, p...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Converted", term.Secondary(arg), "to", term.Info(path))
+	}
+	return nil
+}
+
+// Pipe parses a standard input (stdin) stream of data and writes the converted text to w.
+func Pipe(w io.Writer, cmd *cobra.Command, args ...string) error {
+	if w == nil {
+		w = io.Discard
+	}
+	lb, err := LineBreak(flag.Convert.LineBreak)
+	if err != nil {
+		return fmt.Errorf("cmd convert pipe: %w", err)
+	}
+	out, policy, err := Output(flag.Convert.Output, flag.Convert.Unmappable)
+	if err != nil {
+		return fmt.Errorf("cmd convert pipe: %w", err)
+	}
+	_, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("cmd convert pipe: %w", err)
+	}
+	b, err := fsys.ReadPipe()
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrPipeRead, err)
+	}
+	r, err := view.Transform(c, flag.Input(cmd, samp, "", b...), nil, b...)
+	if err != nil {
+		return err
+	}
+	r = ICE(cmd, r, b...)
+	p, err := Encode(out, policy, Bytes(r, lb, flag.Convert.BOM, flag.Convert.StripBOM)...)
+	if err != nil {
+		return fmt.Errorf("cmd convert pipe: %w", err)
+	}
+	if _, err := w.Write(p); err != nil {
+		return fmt.Errorf("cmd convert pipe: %w", err)
+	}
+	return nil
+}
+
+// ICE returns the runes with the blink attribute of the ANSI escape sequences replaced
+// by the bright background colors, when the text of b uses iCE colors.
+func ICE(cmd *cobra.Command, r []rune, b ...byte) []rune {
+	if !view.ICE(cmd, b...) {
+		return r
+	}
+	return ansi.Colors{ICE: true}.Runes(r...)
+}
+
+// LineBreak returns the line break bytes of the named line break.
+// An empty name keeps the line breaks of the original text and returns nil.
+func LineBreak(name string) ([]byte, error) {
+	switch strings.ToLower(name) {
+	case "", "keep":
+		return nil, nil
+	case "lf", "unix", "linux":
+		return byter.LineBreak(fsys.LF()), nil
+	case "crlf", "dos", "windows":
+		return byter.LineBreak(fsys.CRLF()), nil
+	case "cr", "mac":
+		return byter.LineBreak(fsys.CR()), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrLineBreak, name)
+}
+
+// Bytes returns the runes as UTF-8 text using the lb line break.
+// A nil lb keeps the original line breaks.
+// The bom and strip options either add or remove the UTF-8 byte order mark.
+func Bytes(r []rune, lb []byte, bom, strip bool) []byte {
+	s := string(r)
+	if lb != nil {
+		s = strings.ReplaceAll(s, "\r\n", "\n")
+		s = strings.ReplaceAll(s, "\r", "\n")
+		s = strings.ReplaceAll(s, "\n", string(lb))
+	}
+	b := []byte(s)
+	switch {
+	case bom:
+		return byter.Mark(b)
+	case strip:
+		return bytes.TrimPrefix(b, byter.BOM())
+	}
+	return b
+}
+
+// Output returns the named output encoding and the named unmappable policy.
+// An empty or UTF-8 name returns a nil encoding, as the text is already UTF-8.
+func Output(name, unmappable string) (encoding.Encoding, convert.Policy, error) {
+	p, err := convert.ParsePolicy(unmappable)
+	if err != nil {
+		return nil, p, fmt.Errorf("output: %w", err)
+	}
+	if name == "" {
+		return nil, p, nil
+	}
+	e, err := convert.Encoder(name)
+	if err != nil {
+		return nil, p, fmt.Errorf("output: %w", err)
+	}
+	if e == unicode.UTF8 {
+		return nil, p, nil
+	}
+	return e, p, nil
+}
+
+// Encode transforms the UTF-8 text into the out encoding using the unmappable policy.
+// Any UTF-8 byte order mark is removed, and a nil out encoding returns b as is.
+func Encode(out encoding.Encoding, p convert.Policy, b ...byte) ([]byte, error) {
+	if out == nil {
+		return b, nil
+	}
+	b, err := convert.Encode(out, p, bytes.TrimPrefix(b, byter.BOM())...)
+	if err != nil {
+		return nil, fmt.Errorf("encode: %w", err)
+	}
+	return b, nil
+}
+
+// Extension returns the file extension of the texts saved using the out encoding.
+func Extension(out encoding.Encoding) string {
+	if out == nil {
+		return Ext
+	}
+	return "." + convert.Output(out) + ".txt"
+}
+
+// Filename returns the path of the converted text for the named file or sample using the ext file extension.
+// An empty dir saves the text beside the original file,
+// while the samples are saved to the current working directory.
+// Any .utf8 suffix of the named file is not kept.
+func Filename(name, dir, ext string) string {
+	if dir == "" && !sample.Valid(name) {
+		dir = filepath.Dir(name)
+	}
+	base := strings.TrimSuffix(name, filepath.Ext(name))
+	if strings.HasSuffix(strings.ToLower(base), ".utf8") && !sample.Valid(name) {
+		name = base[:len(base)-len(".utf8")] + filepath.Ext(name)
+	}
+	return export.Filename(name, dir, ext)
+}
+
+// Same reports whether the named file and the target path of its converted text are the same file,
+// such as a .utf8.txt file that is converted again to UTF-8.
+func Same(name, target string) bool {
+	if sample.Valid(name) {
+		return false
+	}
+	a, err := filepath.Abs(name)
+	if err != nil {
+		return false
+	}
+	b, err := filepath.Abs(target)
+	if err != nil {
+		return false
+	}
+	return a == b
+}
+
+// Globs returns the arguments with any glob patterns expanded into the matching filenames.
+// Patterns without any matches and the names of the inbuilt samples are returned as is.
+func Globs(args ...string) ([]string, error) {
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		if sample.Valid(arg) || !strings.ContainsAny(arg, "*?[") {
+			names = append(names, arg)
+			continue
+		}
+		m, err := filepath.Glob(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", err, arg)
+		}
+		if len(m) == 0 {
+			names = append(names, arg)
+			continue
+		}
+		names = append(names, m...)
+	}
+	return names, nil
+}
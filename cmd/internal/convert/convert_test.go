@@ -0,0 +1,122 @@
+package convert_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/convert"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	conv "github.com/bengarrett/retrotxtgo/convert"
+	"github.com/nalgeon/be"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestLineBreak(t *testing.T) {
+	t.Parallel()
+	lb, err := convert.LineBreak("")
+	be.Err(t, err, nil)
+	be.Equal(t, len(lb), 0)
+	lb, err = convert.LineBreak("CRLF")
+	be.Err(t, err, nil)
+	be.Equal(t, lb, []byte("\r\n"))
+	lb, err = convert.LineBreak("unix")
+	be.Err(t, err, nil)
+	be.Equal(t, lb, []byte("\n"))
+	_, err = convert.LineBreak("nel")
+	be.Err(t, err, convert.ErrLineBreak)
+}
+
+func TestBytes(t *testing.T) {
+	t.Parallel()
+	r := []rune("a\r\nb\nc")
+	be.Equal(t, string(convert.Bytes(r, nil, false, false)), "a\r\nb\nc")
+	be.Equal(t, string(convert.Bytes(r, []byte("\n"), false, false)), "a\nb\nc")
+	be.Equal(t, string(convert.Bytes(r, []byte("\r\n"), false, false)), "a\r\nb\r\nc")
+	be.Equal(t, string(convert.Bytes(r, []byte("\r"), false, false)), "a\rb\rc")
+	mac := []rune("a\rb\rc")
+	be.Equal(t, string(convert.Bytes(mac, nil, false, false)), "a\rb\rc")
+	be.Equal(t, string(convert.Bytes(mac, []byte("\n"), false, false)), "a\nb\nc")
+	be.Equal(t, string(convert.Bytes(mac, []byte("\r\n"), false, false)), "a\r\nb\r\nc")
+	bom := "\ufeff"
+	be.Equal(t, string(convert.Bytes([]rune("abc"), nil, true, false)), bom+"abc")
+	be.Equal(t, string(convert.Bytes([]rune(bom+"abc"), nil, true, false)), bom+"abc")
+	be.Equal(t, string(convert.Bytes([]rune(bom+"abc"), nil, false, true)), "abc")
+}
+
+func TestFilename(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, convert.Filename("file.nfo", "", convert.Ext), "file.utf8.txt")
+	be.Equal(t, convert.Filename(filepath.Join("a", "file.txt"), "", convert.Ext), filepath.Join("a", "file.utf8.txt"))
+	be.Equal(t, convert.Filename(filepath.Join("a", "file.txt"), "out", convert.Ext), filepath.Join("out", "file.utf8.txt"))
+	be.Equal(t, convert.Filename("ansi.rgb", "", convert.Ext), "ansi.rgb.utf8.txt")
+	be.Equal(t, convert.Filename("file.utf8.txt", "", ".ibm437.txt"), "file.ibm437.txt")
+}
+
+func TestSame(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join("a", "file.utf8.txt")
+	be.True(t, convert.Same(name, convert.Filename(name, "", convert.Ext)))
+	be.True(t, !convert.Same(name, convert.Filename(name, "", ".ibm437.txt")))
+	be.True(t, !convert.Same(name, convert.Filename(name, "out", convert.Ext)))
+	name = filepath.Join("a", "file.txt")
+	be.True(t, !convert.Same(name, convert.Filename(name, "", convert.Ext)))
+	be.True(t, !convert.Same("ansi.rgb", convert.Filename("ansi.rgb", "", convert.Ext)))
+}
+
+func TestGlobs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.ans"} {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0o600)
+		be.Err(t, err, nil)
+	}
+	names, err := convert.Globs(filepath.Join(dir, "*.txt"), "ascii", "nomatch*")
+	be.Err(t, err, nil)
+	be.Equal(t, names, []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.txt"), "ascii", "nomatch*"})
+	_, err = convert.Globs("[")
+	be.Err(t, err, filepath.ErrBadPattern)
+}
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	e, p, err := convert.Output("", "")
+	be.Err(t, err, nil)
+	be.True(t, e == nil)
+	be.Equal(t, p, conv.Replace)
+	e, _, err = convert.Output("utf-8", "error")
+	be.Err(t, err, nil)
+	be.True(t, e == nil)
+	e, p, err = convert.Output("cp437", "translit")
+	be.Err(t, err, nil)
+	be.True(t, e == charmap.CodePage437)
+	be.Equal(t, p, conv.Translit)
+	be.Equal(t, convert.Extension(e), ".ibm437.txt")
+	be.Equal(t, convert.Extension(nil), convert.Ext)
+	_, _, err = convert.Output("cp437", "ignore")
+	be.Err(t, err, conv.ErrPolicy)
+	_, _, err = convert.Output("xyz", "")
+	be.Err(t, err, conv.ErrName)
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	b, err := convert.Encode(nil, conv.Strict, []byte("\ufeffcafé")...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "\ufeffcafé")
+	b, err = convert.Encode(charmap.CodePage437, conv.Strict, []byte("\ufeffcafé")...)
+	be.Err(t, err, nil)
+	be.Equal(t, b, []byte{'c', 'a', 'f', 0x82})
+}
+
+func TestICE(t *testing.T) {
+	t.Parallel()
+	r := []rune("\x1b[5;41mhi")
+	be.Equal(t, convert.ICE(nil, r), r)
+	cmd := &cobra.Command{}
+	var ice bool
+	flag.ICE(&ice, cmd)
+	be.Err(t, cmd.Flags().Set("ice", "true"), nil)
+	be.Equal(t, string(convert.ICE(cmd, r)), "\x1b[0;101mhi")
+}
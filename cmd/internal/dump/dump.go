@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/bengarrett/retrotxtgo/fsys"
 	"github.com/bengarrett/retrotxtgo/sample"
@@ -52,6 +53,14 @@ func Run(w io.Writer, _ *cobra.Command, args ...string) error {
 	return nil
 }
 
+// Lines returns the hexadecimal dump of b as lines of 16 bytes.
+func Lines(b ...byte) []string {
+	if len(b) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(hex.Dump(b), "\n"), "\n")
+}
+
 // tryReadSample attempts to read a sample file if it exists.
 func tryReadSample(name string) ([]byte, error) {
 	if ok := sample.Valid(name); !ok {
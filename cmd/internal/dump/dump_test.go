@@ -33,3 +33,11 @@ func TestRun(t *testing.T) {
 	// This test is skipped to avoid panics from missing command configuration.
 	t.Skip("Skipping dump.Run test due to complex dependencies")
 }
+
+func TestLines(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(dump.Lines()), 0)
+	lines := dump.Lines([]byte("hello world, this is a hex dump")...)
+	be.Equal(t, len(lines), 2)
+	be.Equal(t, lines[0][:8], "00000000")
+}
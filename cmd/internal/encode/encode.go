@@ -0,0 +1,109 @@
+// Package encode provides the encode command run function.
+package encode
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/convert"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	conv "github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+)
+
+// Default is the output encoding used when the "output" flag is unused.
+const Default = "CP437"
+
+var (
+	ErrOutput   = errors.New("output encoding cannot be UTF-8 as the text is already UTF-8")
+	ErrPipeRead = errors.New("could not read text stream from piped stdin (standard input)")
+)
+
+// Run parses the arguments supplied with the encode command.
+// Each encoded text is saved as a file, except for piped input which is written to w.
+func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd encode run"
+	if w == nil {
+		w = io.Discard
+	}
+	out, policy, err := Output(flag.Convert.Output, flag.Convert.Unmappable)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	// piped input from other programs and then exit
+	ok, err := fsys.IsPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if ok {
+		return Pipe(w)
+	}
+	if len(args) == 0 {
+		return flag.Help(cmd, args...)
+	}
+	// read from files or globs
+	if args, err = convert.Globs(args...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for _, arg := range args {
+		b, err := fsys.Read(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		p, err := convert.Encode(out, policy, b...)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", name, arg, err)
+		}
+		dst := convert.Filename(arg, flag.Convert.OutputDir, convert.Extension(out))
+		path, err := export.Save(dst, flag.Convert.Overwrite, p...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Encoded", term.Secondary(arg), "to", term.Info(path))
+	}
+	return nil
+}
+
+// Pipe parses a standard input (stdin) stream of UTF-8 text and writes the encoded text to w.
+func Pipe(w io.Writer) error {
+	if w == nil {
+		w = io.Discard
+	}
+	out, policy, err := Output(flag.Convert.Output, flag.Convert.Unmappable)
+	if err != nil {
+		return fmt.Errorf("cmd encode pipe: %w", err)
+	}
+	b, err := fsys.ReadPipe()
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrPipeRead, err)
+	}
+	p, err := convert.Encode(out, policy, b...)
+	if err != nil {
+		return fmt.Errorf("cmd encode pipe: %w", err)
+	}
+	if _, err := w.Write(p); err != nil {
+		return fmt.Errorf("cmd encode pipe: %w", err)
+	}
+	return nil
+}
+
+// Output returns the named output encoding and the named unmappable policy.
+// An empty name uses the Default encoding.
+func Output(name, unmappable string) (encoding.Encoding, conv.Policy, error) {
+	if name == "" {
+		name = Default
+	}
+	e, p, err := convert.Output(name, unmappable)
+	if err != nil {
+		return nil, p, fmt.Errorf("encode: %w", err)
+	}
+	if e == nil {
+		return nil, p, ErrOutput
+	}
+	return e, p, nil
+}
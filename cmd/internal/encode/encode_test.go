@@ -0,0 +1,26 @@
+package encode_test
+
+import (
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/encode"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	e, p, err := encode.Output("", "")
+	be.Err(t, err, nil)
+	be.True(t, e == charmap.CodePage437)
+	be.Equal(t, p, convert.Replace)
+	e, p, err = encode.Output("cp037", "error")
+	be.Err(t, err, nil)
+	be.True(t, e == charmap.CodePage037)
+	be.Equal(t, p, convert.Strict)
+	_, _, err = encode.Output("utf8", "")
+	be.Err(t, err, encode.ErrOutput)
+	_, _, err = encode.Output("", "skip")
+	be.Err(t, err, convert.ErrPolicy)
+}
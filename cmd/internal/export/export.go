@@ -0,0 +1,165 @@
+// Package export provides the export command run function.
+package export
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/view"
+	"github.com/bengarrett/retrotxtgo/export"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/info"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+var (
+	ErrFormat   = errors.New("export format is not known")
+	ErrPipeRead = errors.New("could not read text stream from piped stdin (standard input)")
+)
+
+// Run parses the arguments supplied with the export command.
+// Each exported document is saved as a file, except for piped input which is written to w.
+func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd export run"
+	if w == nil {
+		w = io.Discard
+	}
+	if err := Format(flag.Export.Format); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	// piped input from other programs and then exit
+	ok, err := fsys.IsPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if ok {
+		return Pipe(w, cmd, args...)
+	}
+	// read from files or samples
+	args, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for i, arg := range args {
+		if i == 0 && arg == "" {
+			return nil
+		}
+		b, err := flag.ReadArgument(arg, c, samp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r, err := view.Transform(c, flag.Input(cmd, samp, arg, b...), nil, b...)
+		if err != nil {
+			return err
+		}
+		// the SAUCE metadata must be read from the original bytes
+		raw := b
+		if sample.Valid(arg) {
+			if raw, err = sample.Open(arg); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		doc := &bytes.Buffer{}
+		if err := export.HTML(doc, Meta(arg, raw...), Rows(r...)...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		path, err := Save(Filename(arg, flag.Export.OutputDir, ".html"), flag.Export.Overwrite, doc.Bytes()...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Exported", term.Secondary(arg), "to", term.Info(path))
+	}
+	return nil
+}
+
+// Pipe parses a standard input (stdin) stream of data and writes the exported document to w.
+func Pipe(w io.Writer, cmd *cobra.Command, args ...string) error {
+	if w == nil {
+		w = io.Discard
+	}
+	_, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("cmd export pipe: %w", err)
+	}
+	b, err := fsys.ReadPipe()
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrPipeRead, err)
+	}
+	r, err := view.Transform(c, flag.Input(cmd, samp, "", b...), nil, b...)
+	if err != nil {
+		return err
+	}
+	if err := export.HTML(w, Meta("", b...), Rows(r...)...); err != nil {
+		return fmt.Errorf("cmd export pipe: %w", err)
+	}
+	return nil
+}
+
+// Format returns an error if the named export format is not supported.
+func Format(name string) error {
+	switch strings.ToLower(name) {
+	case "html", "h", "":
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrFormat, name)
+}
+
+// Meta returns the document metadata using the SAUCE record found in b.
+// The base of the named file is used as the title when there is no SAUCE title.
+func Meta(name string, b ...byte) export.Meta {
+	var d info.Detail
+	_ = d.Parse(name, b...)
+	m := export.Meta{
+		Title:   strings.TrimSpace(d.Sauce.Title),
+		Author:  strings.TrimSpace(d.Sauce.Author),
+		Group:   strings.TrimSpace(d.Sauce.Group),
+		Comment: strings.TrimSpace(strings.Join(d.Sauce.Comnt.Comment, " ")),
+		ICE:     d.ICE(),
+	}
+	if m.Title == "" && name != "" {
+		m.Title = filepath.Base(name)
+	}
+	return m
+}
+
+// Rows draws the runes onto a virtual ANSI.SYS screen and returns the cells.
+func Rows(r ...rune) [][]ansi.Cell {
+	s := ansi.New(ansi.Columns)
+	s.Runes(r...)
+	return s.Rows()
+}
+
+// Filename returns the path of the document for the named file or sample using the ext file extension.
+// An empty dir uses the current working directory.
+func Filename(name, dir, ext string) string {
+	base := filepath.Base(name)
+	base = strings.TrimSuffix(base, filepath.Ext(base))
+	if sample.Valid(name) {
+		base = name
+	}
+	return filepath.Join(dir, base+ext)
+}
+
+// Save writes b to the named file and returns the absolute path.
+// Unless overwrite is true, an existing file is kept and a unique name is used instead.
+func Save(name string, overwrite bool, b ...byte) (string, error) {
+	var err error
+	if !overwrite {
+		if name, err = fsys.UniqueName(name); err != nil {
+			return "", fmt.Errorf("export save: %w", err)
+		}
+	}
+	_, path, err := fsys.Write(name, b...)
+	if err != nil {
+		return path, fmt.Errorf("export save: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,55 @@
+package export_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/nalgeon/be"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	be.Err(t, export.Format(""), nil)
+	be.Err(t, export.Format("HTML"), nil)
+	be.Err(t, export.Format("png"), export.ErrFormat)
+}
+
+func TestFilename(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, export.Filename("file.ans", "", ".html"), "file.html")
+	be.Equal(t, export.Filename(filepath.Join("a", "file.txt"), "out", ".html"), filepath.Join("out", "file.html"))
+	be.Equal(t, export.Filename("ansi.rgb", "out", ".png"), filepath.Join("out", "ansi.rgb.png"))
+}
+
+func TestMeta(t *testing.T) {
+	t.Parallel()
+	m := export.Meta(filepath.Join("a", "file.txt"), []byte("hello")...)
+	be.Equal(t, m.Title, "file.txt")
+	be.Equal(t, m.Author, "")
+	be.True(t, !m.ICE)
+}
+
+func TestRows(t *testing.T) {
+	t.Parallel()
+	rows := export.Rows([]rune("a\x1b[2Cb\r\nc")...)
+	be.Equal(t, len(rows), 2)
+	be.Equal(t, rows[0][3].Rune, 'b')
+}
+
+func TestSave(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "save.html")
+	path, err := export.Save(name, false, []byte("abc")...)
+	be.Err(t, err, nil)
+	be.Equal(t, path, name)
+	unique, err := export.Save(name, false, []byte("def")...)
+	be.Err(t, err, nil)
+	be.True(t, unique != name)
+	path, err = export.Save(name, true, []byte("ghi")...)
+	be.Err(t, err, nil)
+	b, err := os.ReadFile(path)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "ghi")
+}
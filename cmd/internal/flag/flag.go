@@ -10,8 +10,10 @@ import (
 	"strings"
 
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/detect"
 	"github.com/bengarrett/retrotxtgo/fsys"
 	"github.com/bengarrett/retrotxtgo/logs"
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"github.com/bengarrett/retrotxtgo/sample"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/encoding"
@@ -19,6 +21,9 @@ import (
 	"golang.org/x/text/encoding/unicode"
 )
 
+// Auto is the "input" flag value that detects the character encoding of each file.
+const Auto = "auto"
+
 var (
 	ErrInput = errors.New("empty default encoding")
 	ErrNames = errors.New("ignoring [filenames]")
@@ -34,8 +39,19 @@ func Args(cmd *cobra.Command, args ...string) (
 		Controls:  View().Controls,
 		SwapChars: View().Swap,
 		MaxWidth:  View().Width,
+		BBS:       View().BBS,
 	}
 	converter.Args = setFlags(cmd, converter.Args)
+	if _, err := convert.Dialect(converter.Args.BBS); err != nil {
+		return nil, nil, sample.Flags{}, fmt.Errorf("flag bbs: %w", err)
+	}
+	if w := cmd.Flags().Lookup("wrap"); w != nil {
+		mode, err := reflow.ParseMode(w.Value.String())
+		if err != nil {
+			return nil, nil, sample.Flags{}, fmt.Errorf("flag wrap: %w", err)
+		}
+		converter.Args.Wrap = mode
+	}
 	pipeOW, err := fsys.IsPipe()
 	if err != nil {
 		logs.Fatal(err)
@@ -74,10 +90,14 @@ func Args(cmd *cobra.Command, args ...string) (
 // setFlags applies the flag arguments to a convert flag struct.
 func setFlags(cmd *cobra.Command, flag convert.Flag) convert.Flag {
 	const (
+		bbs       = "bbs"
 		controls  = "controls"
 		swapChars = "swap-chars"
 		width     = "width"
 	)
+	if b := cmd.Flags().Lookup(bbs); b != nil && b.Changed {
+		flag.BBS = b.Value.String()
+	}
 	if c := cmd.Flags().Lookup(controls); c != nil && c.Changed {
 		const sep, minChrs = ",", 2
 		val := c.Value.String()
@@ -159,6 +179,10 @@ func parseInput(changed bool, value, fallback string) (encoding.Encoding, error)
 	if name == "" && fallback == "" {
 		return nil, ErrInput
 	}
+	// the encoding is detected later using the content of each file
+	if strings.EqualFold(name, Auto) {
+		return nil, nil
+	}
 	ee, err := convert.Encoder(name)
 	if err != nil {
 		return ee, fmt.Errorf("flag parse input: %w", err)
@@ -166,6 +190,28 @@ func parseInput(changed bool, value, fallback string) (encoding.Encoding, error)
 	return ee, nil
 }
 
+// IsAuto reports whether the "input" flag requests the automatic detection of the character encoding.
+func IsAuto(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	cp := cmd.Flags().Lookup("input")
+	if cp == nil || !cp.Changed {
+		return false
+	}
+	return strings.EqualFold(cp.Value.String(), Auto)
+}
+
+// Input returns the input character encoding of the named file or piped data in b.
+// When the "input" flag requests automatic detection, the most likely encoding of b is returned,
+// except for the inbuilt samples which always use their own encoding.
+func Input(cmd *cobra.Command, f sample.Flags, name string, b ...byte) encoding.Encoding {
+	if !IsAuto(cmd) || sample.Valid(name) {
+		return f.Input
+	}
+	return detect.Likely(b...)
+}
+
 // EndOfFile reports whether end-of-file control flag was requested.
 func EndOfFile(flags convert.Flag) bool {
 	return slices.Contains(flags.Controls, "eof")
@@ -9,6 +9,7 @@ import (
 	"github.com/bengarrett/retrotxtgo/convert"
 	"github.com/bengarrett/retrotxtgo/sample"
 	"github.com/nalgeon/be"
+	"github.com/spf13/cobra"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 )
@@ -66,3 +67,21 @@ func TestView(t *testing.T) {
 	be.Equal(t, view.Width, 0)
 	be.Equal(t, view.Original, false)
 }
+
+func TestInput(t *testing.T) {
+	t.Parallel()
+	cmd := &cobra.Command{}
+	var s string
+	flag.Encode(&s, cmd)
+	f := sample.Flags{Input: cp437}
+	be.True(t, !flag.IsAuto(nil))
+	be.True(t, !flag.IsAuto(cmd))
+	be.True(t, flag.Input(cmd, f, "file.txt", []byte("hello")...) == cp437)
+	err := cmd.Flags().Set("input", "AUTO")
+	be.Err(t, err, nil)
+	be.True(t, flag.IsAuto(cmd))
+	b, err := charmap.Windows1251.NewEncoder().Bytes([]byte("Привет! Я пишу этот текст на русском языке."))
+	be.Err(t, err, nil)
+	be.True(t, flag.Input(cmd, f, "file.txt", b...) == charmap.Windows1251)
+	be.True(t, flag.Input(cmd, f, "ansi", b...) == cp437)
+}
@@ -3,7 +3,11 @@ package flag
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
+	"github.com/bengarrett/retrotxtgo/baud"
+	"github.com/bengarrett/retrotxtgo/convert"
 	"github.com/bengarrett/retrotxtgo/meta"
 	"github.com/bengarrett/retrotxtgo/term"
 	"github.com/spf13/cobra"
@@ -30,26 +34,93 @@ var Info struct {
 	Format   string // output format
 }
 
+// Convert handles the convert and encode command flags.
+var Convert struct {
+	LineBreak  string // line break used by the converted files
+	Output     string // output character encoding of the converted files
+	Unmappable string // handling of characters that cannot be encoded
+	OutputDir  string // directory to save the converted files
+	BOM        bool   // add a UTF-8 byte order mark
+	StripBOM   bool   // remove any UTF-8 byte order mark
+	Overwrite  bool   // overwrite any existing files
+}
+
+// Export handles the export command flags.
+var Export struct {
+	Format    string // output format
+	OutputDir string // directory to save the exported files
+	Overwrite bool   // overwrite any existing files
+}
+
+// Render handles the render command flags.
+var Render struct {
+	Font      string // bitmap font
+	OutputDir string // directory to save the rendered images
+	Overwrite bool   // overwrite any existing files
+	Baud      int    // emulated modem speed used to save an animated image
+}
+
+// Music handles the music command flags.
+var Music struct {
+	OutputDir string // directory to save the MIDI files
+	Overwrite bool   // overwrite any existing files
+}
+
+// Sauce handles the sauce set command flags.
+var Sauce struct {
+	Title    string   // title of the work
+	Author   string   // author of the work
+	Group    string   // group or company of the author
+	Date     string   // date of creation
+	Comments []string // comment lines
+	TInfo1   uint16   // type information, such as the character width
+	Flags    uint8    // type flags, such as iCE colors
+}
+
 // Views handles the view command flags.
 type Views struct {
 	Input    string   // input character encoding used by the files
+	BBS      string   // BBS color code dialect to interpret
 	Controls []string // control codes to implement
 	Swap     []string // swap out these characters with Unicode control pictures
 	Width    int      // maximum document character/column width
+	Wrap     string   // wrap mode used to fit the text to the width
 	Original bool     // output the sample's original character encoding to stdout
+	Pager    bool     // display the text in the interactive pager
+	Baud     int      // emulated modem speed used to play the text
+	Palette  string   // true color palette used for the 16 standard colors
+	ICE      bool     // use the blink attribute for the bright background colors
 }
 
 // View returns the Views struct with default values.
 func View() Views {
 	return Views{
 		Input:    "CP437",
+		BBS:      convert.AutoBBS,
 		Controls: []string{"eof", "tab"},
 		Swap:     []string{"null", "bar"},
 		Width:    0,
+		Wrap:     "hard",
 		Original: false,
 	}
 }
 
+// BBS handles the "bbs" color codes flag.
+func BBS(p *string, cc *cobra.Command) {
+	cc.Flags().StringVar(p, "bbs", "",
+		`interpret the color codes of a BBS dialect as ANSI colors (default "`+View().BBS+`")
+  auto       detect the dialect used by each file
+  none       keep the color codes as text
+  celerity   Celerity |c pipe codes
+  pcboard    PCBoard @X1F codes
+  renegade   Renegade |07 pipe codes
+  telegard   Telegard `+"`"+`1F grave accent codes
+  wildcat    Wildcat! @1F@ codes
+  wwiv       WWIV |#7 hash codes
+  wwiv-heart WWIV ♥7 heart codes
+`)
+}
+
 // Controls handles the "controls" flag.
 func Controls(p *[]string, cc *cobra.Command) {
 	cc.Flags().StringSliceVarP(p, "controls", "c", []string{},
@@ -69,12 +140,31 @@ func Controls(p *[]string, cc *cobra.Command) {
 // Encode handles the "input" flag.
 func Encode(p *string, cc *cobra.Command) {
 	cc.Flags().StringVarP(p, "input", "i", "",
-		fmt.Sprintf("character encoding used by the filename(s) (default \"CP437\")\n%s%s\n%s\n",
+		fmt.Sprintf("character encoding used by the filename(s) (default \"CP437\")\n%s%s\n%s\n%s\n",
 			"see the list of encode values ",
 			term.Example(meta.Bin+" list codepages"),
+			"use \""+Auto+"\" to detect the encoding of each file",
 			"this flag has no effect for the inbuilt samples"))
 }
 
+// Output handles the "output" encoding flag, the def value is only used for the help usage.
+func Output(p *string, def string, cc *cobra.Command) {
+	cc.Flags().StringVar(p, "output", "",
+		fmt.Sprintf("character encoding used by the saved file(s) (default %q)\n%s%s\n",
+			def, "see the list of encode values ",
+			term.Example(meta.Bin+" list codepages")))
+}
+
+// Unmappable handles the "unmappable" flag.
+func Unmappable(p *string, cc *cobra.Command) {
+	cc.Flags().StringVarP(p, "unmappable", "u", "",
+		`handling of the characters that do not exist in the output encoding (default "replace")
+  error     stop with the offset of the character
+  replace   use a question mark ?
+  translit  use the nearest glyph, such as " for “
+`)
+}
+
 // SwapChars handles the "swap-chars" flag.
 func SwapChars(p *[]string, cc *cobra.Command) {
 	cc.Flags().StringSliceVarP(p, "swap-chars", "x", []string{},
@@ -99,11 +189,70 @@ func OG(p *bool, cc *cobra.Command) error {
 	return nil
 }
 
+// Pager handles the "pager" flag.
+func Pager(p *bool, cc *cobra.Command) {
+	cc.Flags().BoolVarP(p, "pager", "p", false,
+		`display the text in a full-screen pager with scrolling and search
+press ? in the pager to list the keys
+`)
+}
+
+// Baud handles the "baud" flag.
+func Baud(p *int, cc *cobra.Command) {
+	cc.Flags().IntVarP(p, "baud", "b", 0,
+		`play the text at the speed of a modem connection to watch ANSI animations
+  `+Rates()+`
+press space to pause, n to step while paused, or q to stop
+`)
+}
+
+// ICE handles the "ice" colors flag.
+func ICE(p *bool, cc *cobra.Command) {
+	cc.Flags().BoolVar(p, "ice", false,
+		`use the blink attribute for bright background colors, known as iCE colors
+by default, the non-blink flag of the SAUCE metadata is used
+use --ice=false to keep the blink attribute
+`)
+}
+
+// Palette handles the "palette" flag.
+func Palette(p *string, cc *cobra.Command) {
+	cc.Flags().StringVar(p, "palette", "",
+		`show the 16 standard colors using the true colors of a classic computer
+the colors are reduced to suit terminals without true color support
+  vga    IBM VGA and MS-DOS
+  cga    IBM CGA with a dark yellow
+  ega    IBM EGA
+  amiga  Commodore Amiga Workbench 1.3
+  c64    Commodore 64
+`)
+}
+
+// Rates returns the supported baud rates as a comma separated list.
+func Rates() string {
+	s := []string{}
+	for _, rate := range baud.Rates() {
+		s = append(s, strconv.Itoa(rate))
+	}
+	return strings.Join(s, ", ")
+}
+
 // Width handles the "width" flag.
 func Width(p *int, cc *cobra.Command) {
 	cc.Flags().IntVarP(p, "width", "w", View().Width,
-		`maximum document character/column width
-any horizontal tab characters are replaced with three spaces
-any newline characters are replaced with a space
+		`maximum display width of the lines of text, where the
+lines are wrapped at the word boundaries using the --wrap mode
+the ANSI controls are not counted and wide characters use two columns
+`)
+}
+
+// Wrap handles the "wrap" mode flag that is used with the "width" flag.
+func Wrap(p *string, cc *cobra.Command) {
+	cc.Flags().StringVar(p, "wrap", "",
+		`the method used to fit the lines of text to the --width (default "`+View().Wrap+`")
+  hard       wrap the long lines but keep every line break
+  soft       join the lines of each paragraph and wrap them
+             a paragraph ends with a blank or an indented line
+  truncate   cut off the long lines
 `)
 }
@@ -3,12 +3,14 @@ package format
 
 // Syntax choices for the input format flag.
 type Syntax struct {
-	Info [5]string
+	Export [1]string
+	Info   [5]string
 }
 
-// Format flag choices for the info command.
+// Format flag choices for the export and info commands.
 func Format() Syntax {
 	return Syntax{
-		Info: [5]string{"color", "json", "json.min", "text", "xml"},
+		Export: [1]string{"html"},
+		Info:   [5]string{"color", "json", "json.min", "text", "xml"},
 	}
 }
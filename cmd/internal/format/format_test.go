@@ -17,4 +17,6 @@ func TestFormat(t *testing.T) {
 	be.Equal(t, s.Info[2], "json.min")
 	be.Equal(t, s.Info[3], "text")
 	be.Equal(t, s.Info[4], "xml")
+	be.Equal(t, len(s.Export), 1)
+	be.Equal(t, s.Export[0], "html")
 }
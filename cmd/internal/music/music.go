@@ -0,0 +1,91 @@
+// Package music provides the music command run function.
+package music
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/music"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+var ErrPipeRead = errors.New("could not read text stream from piped stdin (standard input)")
+
+// Run parses the arguments supplied with the music command.
+// The ANSI music of each file is saved as a MIDI file, except for piped input which is written to w.
+func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd music run"
+	if w == nil {
+		w = io.Discard
+	}
+	// piped input from other programs and then exit
+	ok, err := fsys.IsPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if ok {
+		return Pipe(w)
+	}
+	if len(args) == 0 {
+		return flag.Help(cmd, args...)
+	}
+	for _, arg := range args {
+		b, err := Read(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		mml := music.Find(b...)
+		if len(mml) == 0 {
+			fmt.Fprintln(w, "No ANSI music was found in", term.Secondary(arg))
+			continue
+		}
+		mid := &bytes.Buffer{}
+		if err := music.MIDI(mid, mml...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		path, err := export.Save(export.Filename(arg, flag.Music.OutputDir, ".mid"), flag.Music.Overwrite, mid.Bytes()...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Saved the ANSI music of", term.Secondary(arg), "to", term.Info(path))
+	}
+	return nil
+}
+
+// Pipe parses a standard input (stdin) stream of data and writes the MIDI file to w.
+func Pipe(w io.Writer) error {
+	if w == nil {
+		w = io.Discard
+	}
+	b, err := fsys.ReadPipe()
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrPipeRead, err)
+	}
+	if err := music.MIDI(w, music.Find(b...)...); err != nil {
+		return fmt.Errorf("cmd music pipe: %w", err)
+	}
+	return nil
+}
+
+// Read returns the original bytes of the named file or embedded sample file.
+func Read(name string) ([]byte, error) {
+	if sample.Valid(name) {
+		b, err := sample.Open(name)
+		if err != nil {
+			return nil, fmt.Errorf("music read: %w", err)
+		}
+		return b, nil
+	}
+	b, err := fsys.Read(name)
+	if err != nil {
+		return nil, fmt.Errorf("music read: %w", err)
+	}
+	return b, nil
+}
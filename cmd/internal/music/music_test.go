@@ -0,0 +1,25 @@
+package music_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/music"
+	"github.com/nalgeon/be"
+)
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "tune.ans")
+	err := os.WriteFile(name, []byte("\x1b[MFcde\x0e"), 0o600)
+	be.Err(t, err, nil)
+	b, err := music.Read(name)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "\x1b[MFcde\x0e")
+	b, err = music.Read("ansi")
+	be.Err(t, err, nil)
+	be.True(t, len(b) > 0)
+	_, err = music.Read(filepath.Join(t.TempDir(), "missing.ans"))
+	be.True(t, err != nil)
+}
@@ -0,0 +1,83 @@
+package pager
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// The names of the special keys, other keys use the character they type.
+const (
+	Up        = "up"
+	Down      = "down"
+	Left      = "left"
+	Right     = "right"
+	PageUp    = "pgup"
+	PageDown  = "pgdown"
+	Home      = "home"
+	End       = "end"
+	Enter     = "enter"
+	Escape    = "esc"
+	Backspace = "backspace"
+	Interrupt = "ctrl+c"
+)
+
+// Keys returns the names of the key presses read from a terminal in raw mode.
+// Unknown escape sequences are ignored.
+func Keys(b ...byte) []string {
+	keys := []string{}
+	for len(b) > 0 {
+		if b[0] == esc {
+			k, n := sequence(b...)
+			if k != "" {
+				keys = append(keys, k)
+			}
+			b = b[n:]
+			continue
+		}
+		switch b[0] {
+		case '\r', '\n':
+			keys = append(keys, Enter)
+			b = b[1:]
+			continue
+		case 0x7f, '\b':
+			keys = append(keys, Backspace)
+			b = b[1:]
+			continue
+		case 0x03:
+			keys = append(keys, Interrupt)
+			b = b[1:]
+			continue
+		}
+		r, n := utf8.DecodeRune(b)
+		if r >= ' ' {
+			keys = append(keys, string(r))
+		}
+		b = b[n:]
+	}
+	return keys
+}
+
+const esc = 0x1b
+
+// sequence returns the name of the key of the escape sequence at the start of b
+// and the number of bytes it uses.
+func sequence(b ...byte) (string, int) {
+	if len(b) == 1 || (b[1] != '[' && b[1] != 'O') {
+		return Escape, 1
+	}
+	// find the final byte of the control sequence
+	end := bytes.IndexFunc(b[2:], func(r rune) bool {
+		return r >= '@' && r <= '~'
+	})
+	if end < 0 {
+		return "", len(b)
+	}
+	n := end + 3 //nolint:mnd
+	seq := string(b[2:n])
+	names := map[string]string{
+		"A": Up, "B": Down, "C": Right, "D": Left,
+		"H": Home, "F": End, "1~": Home, "7~": Home, "4~": End, "8~": End,
+		"5~": PageUp, "6~": PageDown,
+	}
+	return names[seq], n
+}
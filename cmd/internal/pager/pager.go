@@ -0,0 +1,457 @@
+// Package pager provides the interactive full-screen pager of the view command.
+package pager
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/dump"
+	"github.com/bengarrett/retrotxtgo/info"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/text/encoding"
+)
+
+var (
+	ErrDecode = errors.New("pager decode function cannot be nil")
+	ErrNoTerm = errors.New("pager requires an interactive terminal")
+)
+
+// Decoder returns the text decoded using the character encoding,
+// with any ANSI controls already interpreted.
+type Decoder func(e encoding.Encoding) ([]rune, error)
+
+// Pager is the state of the interactive pager for a single text.
+type Pager struct {
+	name      string              // name of the file or sample
+	raw       []byte              // raw is the original bytes used by the hex panel and the SAUCE overlay
+	decode    Decoder             // decode the raw bytes using another encoding
+	encodings []encoding.Encoding // encodings that can be toggled
+	encoding  int                 // encoding is the index of the encodings in use
+	lines     []string            // lines of the decoded text
+	plain     []string            // plain text of the lines used by the search
+	hex       []string            // hex are the lines of the hex dump
+	sauce     []string            // sauce are the lines of the SAUCE overlay
+	matches   []int               // matches are the line numbers of the search matches
+	current   int                 // current is the index of the matches in view, or -1
+	query     string              // query is the search term
+	message   string              // message is a notice shown in the status bar
+	top       int                 // top is the line number at the top of the screen
+	width     int                 // width of the terminal in columns
+	height    int                 // height of the terminal in rows
+	overlay   overlay             // overlay in use
+	prompt    bool                // prompt is true when typing a search term
+	showHex   bool                // showHex displays the hex panel
+	quit      bool                // quit the pager
+}
+
+type overlay int
+
+const (
+	noOverlay overlay = iota
+	helpOverlay
+	sauceOverlay
+)
+
+// hexWidth is the number of columns used by a hex dump line.
+const hexWidth = 78
+
+// New returns a pager of the named text using the decode function.
+// The encodings can be toggled, starting with the e encoding used to decode the text.
+func New(name string, raw []byte, e encoding.Encoding, decode Decoder, encodings ...encoding.Encoding) (*Pager, error) {
+	if decode == nil {
+		return nil, ErrDecode
+	}
+	p := Pager{
+		name:   name,
+		raw:    raw,
+		decode: decode,
+		width:  80, //nolint:mnd
+		height: 25, //nolint:mnd
+	}
+	p.encodings = slices.Clone(encodings)
+	if e != nil {
+		p.encodings = slices.DeleteFunc(p.encodings, func(x encoding.Encoding) bool { return x == e })
+		p.encodings = slices.Insert(p.encodings, 0, e)
+	}
+	if len(p.encodings) == 0 {
+		p.encodings = []encoding.Encoding{nil}
+	}
+	if err := p.load(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// load decodes the text using the encoding in use.
+func (p *Pager) load() error {
+	r, err := p.decode(p.encodings[p.encoding])
+	if err != nil {
+		return fmt.Errorf("pager: %w", err)
+	}
+	s := strings.ReplaceAll(string(r), "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\t", "   ")
+	p.lines = strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	p.plain = Plain(p.lines...)
+	p.search()
+	p.scroll(0)
+	return nil
+}
+
+// Resize sets the size of the terminal.
+func (p *Pager) Resize(width, height int) {
+	if width > 0 {
+		p.width = width
+	}
+	if height > 1 {
+		p.height = height
+	}
+	p.scroll(0)
+}
+
+// Quit reports whether the pager has been closed.
+func (p *Pager) Quit() bool {
+	return p.quit
+}
+
+// Top returns the line number at the top of the screen.
+func (p *Pager) Top() int {
+	return p.top
+}
+
+// Encoding returns the character encoding in use.
+func (p *Pager) Encoding() encoding.Encoding {
+	return p.encodings[p.encoding]
+}
+
+// Press handles a key press named by the Keys function.
+func (p *Pager) Press(key string) {
+	p.message = ""
+	if key == Interrupt {
+		p.quit = true
+		return
+	}
+	if p.prompt {
+		p.typing(key)
+		return
+	}
+	if p.overlay != noOverlay {
+		// any key closes the overlay
+		p.overlay = noOverlay
+		return
+	}
+	if p.move(key) {
+		return
+	}
+	switch key {
+	case "q", "Q", Escape:
+		p.quit = true
+	case "/":
+		p.prompt, p.query = true, ""
+	case "n":
+		p.next(true)
+	case "N":
+		p.next(false)
+	case "e":
+		p.toggle(1)
+	case "E":
+		p.toggle(-1)
+	case "x":
+		p.showHex = !p.showHex
+	case "s":
+		p.overlay = sauceOverlay
+	case "?", "h":
+		p.overlay = helpOverlay
+	}
+}
+
+// move handles the scrolling keys and reports whether the key was used.
+func (p *Pager) move(key string) bool {
+	page := p.rows()
+	switch key {
+	case Down, "j", Enter:
+		p.scroll(1)
+	case Up, "k":
+		p.scroll(-1)
+	case PageDown, " ", "f":
+		p.scroll(page)
+	case PageUp, "b":
+		p.scroll(-page)
+	case "d":
+		p.scroll(page / 2) //nolint:mnd
+	case "u":
+		p.scroll(-page / 2) //nolint:mnd
+	case Home, "g":
+		p.top = 0
+	case End, "G":
+		p.top = len(p.lines)
+		p.scroll(0)
+	default:
+		return false
+	}
+	return true
+}
+
+// typing handles the key presses of the search prompt.
+func (p *Pager) typing(key string) {
+	switch key {
+	case Enter:
+		p.prompt = false
+		p.search()
+		p.next(true)
+	case Escape:
+		p.prompt, p.query = false, ""
+		p.search()
+	case Backspace:
+		if r := []rune(p.query); len(r) > 0 {
+			p.query = string(r[:len(r)-1])
+		}
+	default:
+		if len([]rune(key)) == 1 {
+			p.query += key
+		}
+	}
+}
+
+// search finds the line numbers of the lines that contain the query, ignoring case.
+func (p *Pager) search() {
+	p.matches, p.current = nil, -1
+	if p.query == "" {
+		return
+	}
+	q := strings.ToLower(p.query)
+	for i, line := range p.plain {
+		if strings.Contains(strings.ToLower(line), q) {
+			p.matches = append(p.matches, i)
+		}
+	}
+}
+
+// next scrolls to the next or previous search match.
+func (p *Pager) next(forward bool) {
+	if p.query == "" {
+		return
+	}
+	if len(p.matches) == 0 {
+		p.message = "pattern not found: " + p.query
+		return
+	}
+	n := len(p.matches)
+	switch {
+	case p.current < 0 && forward:
+		// start with the first match from the top of the screen
+		i := slices.IndexFunc(p.matches, func(line int) bool { return line >= p.top })
+		p.current = max(i, 0)
+	case p.current < 0:
+		p.current = n - 1
+	case forward:
+		p.current = (p.current + 1) % n
+	default:
+		p.current = (p.current - 1 + n) % n
+	}
+	p.top = p.matches[p.current]
+	p.scroll(0)
+	p.message = fmt.Sprintf("match %d of %d", p.current+1, n)
+}
+
+// toggle decodes the text using the next or previous encoding.
+func (p *Pager) toggle(step int) {
+	if len(p.encodings) < 2 { //nolint:mnd
+		return
+	}
+	prev := p.encoding
+	p.encoding = (p.encoding + step + len(p.encodings)) % len(p.encodings)
+	if err := p.load(); err != nil {
+		p.encoding = prev
+		p.message = err.Error()
+	}
+}
+
+// scroll moves the top line by n lines within the bounds of the text.
+func (p *Pager) scroll(n int) {
+	p.top = max(min(p.top+n, len(p.lines)-p.rows()), 0)
+}
+
+// rows returns the number of rows used to display the text.
+func (p *Pager) rows() int {
+	return max(p.height-1, 1)
+}
+
+// View returns the screen of the pager, with the status bar on the last row.
+func (p *Pager) View() string {
+	var body []string
+	switch p.overlay {
+	case helpOverlay:
+		body = p.box("Keys", help()...)
+	case sauceOverlay:
+		body = p.box("SAUCE", p.sauceLines()...)
+	case noOverlay:
+		body = p.body()
+	}
+	sb := strings.Builder{}
+	for _, line := range body {
+		sb.WriteString(line)
+		sb.WriteString("\x1b[0m\x1b[K\r\n")
+	}
+	sb.WriteString(p.status())
+	sb.WriteString("\x1b[0m\x1b[K")
+	return sb.String()
+}
+
+// body returns the rows of the text and the optional hex panel.
+func (p *Pager) body() []string {
+	rows := p.rows()
+	textWidth := p.width
+	hex := p.showHex && p.width > hexWidth
+	if hex {
+		textWidth = p.width - hexWidth - 1
+		if p.hex == nil {
+			p.hex = dump.Lines(p.raw...)
+		}
+	}
+	crop := lipgloss.NewStyle().Inline(true).MaxWidth(textWidth)
+	body := make([]string, rows)
+	for i := range rows {
+		n := p.top + i
+		if n < len(p.lines) {
+			body[i] = crop.Render(p.lines[n])
+		}
+		if !hex {
+			continue
+		}
+		pad := strings.Repeat(" ", max(textWidth-lipgloss.Width(body[i]), 0))
+		body[i] += "\x1b[0m" + pad + "│" + p.hexLine(i)
+	}
+	return body
+}
+
+// hexLine returns the row of the hex panel, which scrolls in proportion to the text.
+func (p *Pager) hexLine(row int) string {
+	top := 0
+	if len(p.lines) > p.rows() {
+		maxTop := max(len(p.hex)-p.rows(), 0)
+		top = p.top * maxTop / (len(p.lines) - p.rows())
+	}
+	if n := top + row; n < len(p.hex) {
+		return p.hex[n]
+	}
+	return ""
+}
+
+// status returns the status bar.
+func (p *Pager) status() string {
+	bar := lipgloss.NewStyle().Reverse(true).Inline(true).MaxWidth(p.width)
+	if p.prompt {
+		return bar.Render(fmt.Sprintf("%-*s", p.width, "/"+p.query))
+	}
+	last := min(p.top+p.rows(), len(p.lines))
+	percent := 100 //nolint:mnd
+	if len(p.lines) > 0 {
+		percent = last * 100 / len(p.lines) //nolint:mnd
+	}
+	name := "UTF-8"
+	if e := p.Encoding(); e != nil {
+		name = fmt.Sprint(e)
+	}
+	s := fmt.Sprintf(" %s │ %s │ lines %d-%d of %d %d%% ", p.name, name, p.top+1, last, len(p.lines), percent)
+	if p.message != "" {
+		s += "│ " + p.message + " "
+	} else {
+		s += "│ ? help  q quit "
+	}
+	return bar.Render(fmt.Sprintf("%-*s", p.width, s))
+}
+
+// box returns the lines in a border placed at the center of the text rows.
+func (p *Pager) box(title string, lines ...string) []string {
+	border := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		Padding(0, 1).
+		MaxWidth(p.width)
+	content := lipgloss.NewStyle().Bold(true).Render(title) + "\n\n" + strings.Join(lines, "\n")
+	s := lipgloss.Place(p.width, p.rows(), lipgloss.Center, lipgloss.Center, border.Render(content))
+	rows := strings.Split(s, "\n")
+	return rows[:min(len(rows), p.rows())]
+}
+
+// sauceLines returns the lines of the SAUCE overlay.
+func (p *Pager) sauceLines() []string {
+	if p.sauce != nil {
+		return p.sauce
+	}
+	d := info.Detail{}
+	if err := d.Parse(p.name, p.raw...); err != nil {
+		return []string{err.Error()}
+	}
+	p.sauce = Sauce(d)
+	return p.sauce
+}
+
+// Sauce returns the SAUCE metadata of the file details as lines of text.
+func Sauce(d info.Detail) []string {
+	s := d.Sauce
+	if s.ID == "" {
+		return []string{"No SAUCE metadata found."}
+	}
+	fields := []struct{ k, v string }{
+		{"title", s.Title},
+		{"author", s.Author},
+		{"group", s.Group},
+		{"date", s.Date.Value},
+		{"original size", s.FileSize.Decimal},
+		{"file type", s.File.Name},
+		{"data type", s.Data.Name},
+		{"description", s.Desc},
+		{s.Info.Info1.Info, strconv.Itoa(int(s.Info.Info1.Value))},
+		{s.Info.Info2.Info, strconv.Itoa(int(s.Info.Info2.Value))},
+		{"interpretation", s.Info.Flags.String()},
+		{"font", s.Info.Font},
+	}
+	lines := []string{}
+	for _, f := range fields {
+		if f.k == "" || f.v == "" || f.v == "0" {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%-15s %s", f.k, f.v))
+	}
+	for i, c := range s.Comnt.Comment {
+		k := ""
+		if i == 0 {
+			k = "comment"
+		}
+		lines = append(lines, fmt.Sprintf("%-15s %s", k, strings.TrimSpace(c)))
+	}
+	return lines
+}
+
+// help returns the lines of the keys overlay.
+func help() []string {
+	return []string{
+		"↓ j enter     down one line",
+		"↑ k           up one line",
+		"pgdn space f  down one page",
+		"pgup b        up one page",
+		"d u           down or up half a page",
+		"home g        go to the top",
+		"end G         go to the bottom",
+		"/             search",
+		"n N           next or previous match",
+		"e E           next or previous code page",
+		"x             toggle the hex panel",
+		"s             show the SAUCE metadata",
+		"q esc         quit",
+	}
+}
+
+// Plain returns the text of the lines without any ANSI escape controls.
+func Plain(lines ...string) []string {
+	re := regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]`)
+	plain := make([]string, len(lines))
+	for i, line := range lines {
+		plain[i] = re.ReplaceAllString(line, "")
+	}
+	return plain
+}
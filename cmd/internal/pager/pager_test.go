@@ -0,0 +1,147 @@
+package pager_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/pager"
+	"github.com/bengarrett/retrotxtgo/info"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// text returns a decoder of 100 numbered lines.
+func text(e encoding.Encoding) ([]rune, error) {
+	if e == charmap.CodePage037 {
+		return nil, errors.New("unsupported")
+	}
+	sb := strings.Builder{}
+	for i := range 100 {
+		fmt.Fprintf(&sb, "line %d \x1b[1mbold\x1b[0m\r\n", i)
+	}
+	return []rune(sb.String()), nil
+}
+
+func ExampleKeys() {
+	fmt.Println(pager.Keys([]byte("j\x1b[B\x1b[6~/\r\x1b")...))
+	// Output: [j down pgdown / enter esc]
+}
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, pager.Keys(), []string{})
+	be.Equal(t, pager.Keys([]byte("\x1bOA\x1b[5~\x1b[1~\x1b[F")...),
+		[]string{pager.Up, pager.PageUp, pager.Home, pager.End})
+	be.Equal(t, pager.Keys([]byte("é\x7f\x03")...), []string{"é", pager.Backspace, pager.Interrupt})
+	be.Equal(t, pager.Keys([]byte("\x1b[99z")...), []string{})
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	_, err := pager.New("file.txt", nil, nil, nil)
+	be.Err(t, err, pager.ErrDecode)
+	p, err := pager.New("file.txt", nil, charmap.CodePage850, text, charmap.CodePage437, charmap.CodePage850)
+	be.Err(t, err, nil)
+	be.Equal(t, p.Encoding(), encoding.Encoding(charmap.CodePage850))
+}
+
+func TestScroll(t *testing.T) {
+	t.Parallel()
+	p, err := pager.New("file.txt", nil, nil, text)
+	be.Err(t, err, nil)
+	p.Resize(80, 11)
+	p.Press(pager.Down)
+	be.Equal(t, p.Top(), 1)
+	p.Press(pager.PageDown)
+	be.Equal(t, p.Top(), 11)
+	p.Press("k")
+	be.Equal(t, p.Top(), 10)
+	p.Press(pager.End)
+	be.Equal(t, p.Top(), 90)
+	p.Press(pager.PageDown)
+	be.Equal(t, p.Top(), 90)
+	p.Press("g")
+	be.Equal(t, p.Top(), 0)
+	p.Press(pager.PageUp)
+	be.Equal(t, p.Top(), 0)
+	be.True(t, !p.Quit())
+	p.Press("q")
+	be.True(t, p.Quit())
+}
+
+func TestSearch(t *testing.T) {
+	t.Parallel()
+	p, err := pager.New("file.txt", nil, nil, text)
+	be.Err(t, err, nil)
+	p.Resize(80, 11)
+	for _, key := range pager.Keys([]byte("/LINE 5\r")...) {
+		p.Press(key)
+	}
+	be.Equal(t, p.Top(), 5)
+	p.Press("n")
+	be.Equal(t, p.Top(), 50)
+	p.Press("N")
+	be.Equal(t, p.Top(), 5)
+	p.Press("N")
+	be.Equal(t, p.Top(), 59)
+	be.True(t, strings.Contains(p.View(), "match 11 of 11"))
+	for _, key := range pager.Keys([]byte("/bold x\r")...) {
+		p.Press(key)
+	}
+	be.True(t, strings.Contains(p.View(), "pattern not found: bold x"))
+}
+
+func TestToggle(t *testing.T) {
+	t.Parallel()
+	p, err := pager.New("file.txt", nil, charmap.CodePage437, text, charmap.CodePage850, charmap.CodePage037)
+	be.Err(t, err, nil)
+	p.Press("e")
+	be.Equal(t, p.Encoding(), encoding.Encoding(charmap.CodePage850))
+	p.Press("e")
+	be.Equal(t, p.Encoding(), encoding.Encoding(charmap.CodePage850))
+	be.True(t, strings.Contains(p.View(), "unsupported"))
+	p.Press("E")
+	be.Equal(t, p.Encoding(), encoding.Encoding(charmap.CodePage437))
+}
+
+func TestView(t *testing.T) {
+	t.Parallel()
+	p, err := pager.New("file.txt", []byte("hello"), nil, text)
+	be.Err(t, err, nil)
+	p.Resize(120, 5)
+	v := p.View()
+	be.Equal(t, strings.Count(v, "\r\n"), 4)
+	be.True(t, strings.Contains(v, "line 3 \x1b[1mbold"))
+	be.True(t, strings.Contains(v, "lines 1-4 of 100"))
+	p.Press("x")
+	be.True(t, strings.Contains(p.View(), "│00000000  68 65 6c 6c 6f"))
+	p.Resize(120, 25)
+	p.Press("?")
+	be.True(t, strings.Contains(p.View(), "toggle the hex panel"))
+	p.Press("j")
+	be.Equal(t, p.Top(), 0)
+	p.Press("/")
+	be.True(t, strings.Contains(p.View(), "/"))
+}
+
+func TestSauce(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, pager.Sauce(info.Detail{}), []string{"No SAUCE metadata found."})
+	d := info.Detail{}
+	d.Sauce.ID = "SAUCE"
+	d.Sauce.Title = "Example"
+	d.Sauce.Comnt.Comment = []string{"hello  ", "world"}
+	be.Equal(t, pager.Sauce(d), []string{
+		"title           Example",
+		"comment         hello",
+		"                world",
+	})
+}
+
+func TestPlain(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, pager.Plain("\x1b[0;1;31mred\x1b[0m text", "plain"), []string{"red text", "plain"})
+}
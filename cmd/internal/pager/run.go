@@ -0,0 +1,72 @@
+package pager
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// The escape controls used to switch the terminal to and from the full-screen pager.
+const (
+	enter = "\x1b[?1049h\x1b[?25l" // enter uses the alternate screen and hides the cursor
+	leave = "\x1b[?25h\x1b[?1049l" // leave restores the cursor and the main screen
+	home  = "\x1b[H"               // home moves the cursor to the top left
+)
+
+// Run displays the pager in the terminal of w until it is closed.
+// The key presses are read from the controlling terminal,
+// so the pager works with piped text.
+func Run(w *os.File, p *Pager) error {
+	if p == nil || !IsTerminal(w) {
+		return ErrNoTerm
+	}
+	in, closer := TTY()
+	defer closer()
+	fd := int(in.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrNoTerm, err)
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+	fmt.Fprint(w, enter)
+	defer fmt.Fprint(w, leave)
+	buf := make([]byte, 64) //nolint:mnd
+	for !p.Quit() {
+		if width, height, err := term.GetSize(int(w.Fd())); err == nil {
+			p.Resize(width, height)
+		}
+		fmt.Fprint(w, home+p.View())
+		n, err := in.Read(buf)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("pager read: %w", err)
+		}
+		for _, key := range Keys(buf[:n]...) {
+			p.Press(key)
+		}
+	}
+	return nil
+}
+
+// IsTerminal reports whether the file is an interactive terminal.
+func IsTerminal(f *os.File) bool {
+	return f != nil && term.IsTerminal(int(f.Fd()))
+}
+
+// TTY returns the controlling terminal used for the key presses,
+// otherwise the standard input is returned.
+func TTY() (*os.File, func()) {
+	for _, name := range []string{"/dev/tty", "CONIN$"} {
+		f, err := os.Open(name)
+		if err == nil {
+			return f, func() { _ = f.Close() }
+		}
+	}
+	return os.Stdin, func() {}
+}
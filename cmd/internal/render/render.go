@@ -0,0 +1,173 @@
+// Package render provides the render command run function.
+package render
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/baud"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/export"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/view"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/info"
+	"github.com/bengarrett/retrotxtgo/render"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+	"golang.org/x/text/encoding"
+)
+
+var ErrPipeRead = errors.New("could not read text stream from piped stdin (standard input)")
+
+// Run parses the arguments supplied with the render command.
+// Each rendered image is saved as a file, except for piped input which is written to w.
+func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd render run"
+	if w == nil {
+		w = io.Discard
+	}
+	font, err := render.Open(flag.Render.Font)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	// piped input from other programs and then exit
+	ok, err := fsys.IsPipe()
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if flag.Render.Baud > 0 {
+		if err := baud.Valid(flag.Render.Baud); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	if ok {
+		return Pipe(w, cmd, args...)
+	}
+	// read from files or samples
+	args, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	for i, arg := range args {
+		if i == 0 && arg == "" {
+			return nil
+		}
+		b, err := flag.ReadArgument(arg, c, samp)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		// the SAUCE metadata must be read from the original bytes
+		raw := b
+		if sample.Valid(arg) {
+			if raw, err = sample.Open(arg); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		o := Options(font, arg, raw...)
+		o.ICE = view.ICE(cmd, raw...)
+		img, ext := &bytes.Buffer{}, ".png"
+		if flag.Render.Baud > 0 {
+			ext = ".gif"
+		}
+		if err := Image(img, c, o, flag.Input(cmd, samp, arg, b...), b...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		path, err := export.Save(export.Filename(arg, flag.Render.OutputDir, ext), flag.Render.Overwrite, img.Bytes()...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Rendered", term.Secondary(arg), "to", term.Info(path))
+	}
+	return nil
+}
+
+// Pipe parses a standard input (stdin) stream of data and writes the rendered image to w.
+func Pipe(w io.Writer, cmd *cobra.Command, args ...string) error {
+	if w == nil {
+		w = io.Discard
+	}
+	font, err := render.Open(flag.Render.Font)
+	if err != nil {
+		return fmt.Errorf("cmd render pipe: %w", err)
+	}
+	_, c, samp, err := flag.Args(cmd, args...)
+	if err != nil {
+		return fmt.Errorf("cmd render pipe: %w", err)
+	}
+	b, err := fsys.ReadPipe()
+	if err != nil {
+		return fmt.Errorf("%w, %w", ErrPipeRead, err)
+	}
+	o := Options(font, "", b...)
+	o.ICE = view.ICE(cmd, b...)
+	if err := Image(w, c, o, flag.Input(cmd, samp, "", b...), b...); err != nil {
+		return fmt.Errorf("cmd render pipe: %w", err)
+	}
+	return nil
+}
+
+// Image converts the bytes using the in encoding and writes the rendered image to w.
+// With a baud rate, the image is an animated GIF of the text drawn at the speed of
+// a modem connection, otherwise it is a PNG image.
+func Image(w io.Writer, c *convert.Convert, o render.Options, in encoding.Encoding, b ...byte) error {
+	if rate := flag.Render.Baud; rate > 0 {
+		const hundredth = 10 * time.Millisecond // hundredth is the unit of the GIF frame delays.
+		r, err := view.ANSI(c, in, b...)
+		if err != nil {
+			return err
+		}
+		return render.GIF(w, o, int(baud.Delay/hundredth), Frames(o.Columns, rate, r...))
+	}
+	r, err := view.Transform(c, in, nil, b...)
+	if err != nil {
+		return err
+	}
+	return render.PNG(w, o, Rows(o.Columns, r...)...)
+}
+
+// Options returns the image options using the font and the SAUCE record found in b.
+// Without a SAUCE character width, the image uses the 80 columns of the ANSI.SYS screen.
+func Options(font render.Font, name string, b ...byte) render.Options {
+	const nine = 9
+	var d info.Detail
+	_ = d.Parse(name, b...)
+	o := render.Options{
+		Font:    font,
+		Columns: d.Columns(),
+		Nine:    d.LetterSpacing() == nine,
+		Aspect:  d.LegacyAspect(),
+		ICE:     d.ICE(),
+	}
+	if o.Columns == 0 {
+		o.Columns = ansi.Columns
+	}
+	return o
+}
+
+// Frames returns the cells of a virtual ANSI.SYS screen with the number of columns
+// after each frame of the runes is sent at the baud rate.
+func Frames(columns, rate int, r ...rune) iter.Seq[[][]ansi.Cell] {
+	return func(yield func([][]ansi.Cell) bool) {
+		s := ansi.New(columns)
+		for _, frame := range baud.Frames(rate, r...) {
+			s.Runes(frame...)
+			if !yield(s.Rows()) {
+				return
+			}
+		}
+	}
+}
+
+// Rows draws the runes onto a virtual ANSI.SYS screen with the number of columns and returns the cells.
+func Rows(columns int, r ...rune) [][]ansi.Cell {
+	s := ansi.New(columns)
+	s.Runes(r...)
+	return s.Rows()
+}
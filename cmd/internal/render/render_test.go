@@ -0,0 +1,41 @@
+package render_test
+
+import (
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/render"
+	fonts "github.com/bengarrett/retrotxtgo/render"
+	"github.com/nalgeon/be"
+)
+
+func TestOptions(t *testing.T) {
+	t.Parallel()
+	o := render.Options(fonts.CGA(), "file.txt", []byte("hello")...)
+	be.Equal(t, o.Font.Name, "cga")
+	be.Equal(t, o.Columns, 80)
+	be.True(t, !o.Nine)
+	be.True(t, !o.Aspect)
+	be.True(t, !o.ICE)
+}
+
+func TestRows(t *testing.T) {
+	t.Parallel()
+	rows := render.Rows(4, []rune("abcdef")...)
+	be.Equal(t, len(rows), 2)
+	be.Equal(t, rows[1][0].Rune, 'e')
+}
+
+func TestFrames(t *testing.T) {
+	t.Parallel()
+	n := 0
+	var last [][]ansi.Cell
+	for rows := range render.Frames(80, 300, []rune("\x1b[31mab\r\ncd")...) {
+		n++
+		last = rows
+	}
+	be.Equal(t, n, 8)
+	be.Equal(t, len(last), 2)
+	be.Equal(t, last[1][1].Rune, 'd')
+	be.Equal(t, last[0][0].Attr.FG, ansi.Index(1))
+}
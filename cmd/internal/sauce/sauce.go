@@ -0,0 +1,138 @@
+// Package sauce provides the sauce command run functions.
+package sauce
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/saucer"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+// Set creates or updates the SAUCE metadata of the named files using the sauce set flags.
+// Only the flags that are used are changed in any existing metadata.
+func Set(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd sauce set"
+	if w == nil {
+		w = io.Discard
+	}
+	if len(args) == 0 {
+		return flag.Help(cmd, args...)
+	}
+	for _, arg := range args {
+		b, err := fsys.Read(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		r, err := saucer.Read(b...)
+		if errors.Is(err, saucer.ErrNone) {
+			r = saucer.New(b...)
+		}
+		r = Update(cmd, r)
+		p, err := saucer.Write(r, b...)
+		if err != nil {
+			return fmt.Errorf("%s: %s: %w", name, arg, err)
+		}
+		if _, _, err := fsys.Write(arg, p...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Saved the SAUCE metadata to", term.Info(arg))
+	}
+	return nil
+}
+
+// Update returns the record with the fields of the sauce set flags that have been changed.
+func Update(cmd *cobra.Command, r saucer.Record) saucer.Record {
+	if cmd == nil {
+		return r
+	}
+	changed := cmd.Flags().Changed
+	if changed("title") {
+		r.Title = flag.Sauce.Title
+	}
+	if changed("author") {
+		r.Author = flag.Sauce.Author
+	}
+	if changed("group") {
+		r.Group = flag.Sauce.Group
+	}
+	if changed("date") {
+		r.Date = strings.ReplaceAll(flag.Sauce.Date, "-", "")
+	}
+	if changed("tinfo1") {
+		r.TInfo[0] = flag.Sauce.TInfo1
+	}
+	if changed("flags") {
+		r.Flags = flag.Sauce.Flags
+	}
+	if changed("comment") {
+		r.Comments = []string{}
+		for _, line := range flag.Sauce.Comments {
+			if line != "" {
+				r.Comments = append(r.Comments, line)
+			}
+		}
+	}
+	return r
+}
+
+// Strip removes the SAUCE metadata from the named files.
+func Strip(w io.Writer, cmd *cobra.Command, args ...string) error {
+	const name = "cmd sauce strip"
+	if w == nil {
+		w = io.Discard
+	}
+	if len(args) == 0 {
+		return flag.Help(cmd, args...)
+	}
+	for _, arg := range args {
+		b, err := fsys.Read(arg)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		if saucer.Index(b...) < 0 {
+			fmt.Fprintln(w, "No SAUCE metadata found in", term.Secondary(arg))
+			continue
+		}
+		if _, _, err := fsys.Write(arg, saucer.Strip(b...)...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		fmt.Fprintln(w, "Removed the SAUCE metadata from", term.Info(arg))
+	}
+	return nil
+}
+
+// Copy transfers the SAUCE metadata of the src file to the dst file,
+// replacing any existing metadata in the dst file.
+func Copy(w io.Writer, src, dst string) error {
+	const name = "cmd sauce copy"
+	if w == nil {
+		w = io.Discard
+	}
+	b, err := fsys.Read(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	r, err := saucer.Read(b...)
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", name, src, err)
+	}
+	b, err = fsys.Read(dst)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	p, err := saucer.Write(r, b...)
+	if err != nil {
+		return fmt.Errorf("%s: %s: %w", name, dst, err)
+	}
+	if _, _, err := fsys.Write(dst, p...); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	fmt.Fprintln(w, "Copied the SAUCE metadata of", term.Secondary(src), "to", term.Info(dst))
+	return nil
+}
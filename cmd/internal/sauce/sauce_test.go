@@ -0,0 +1,42 @@
+package sauce_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/cmd/internal/sauce"
+	"github.com/bengarrett/retrotxtgo/saucer"
+	"github.com/nalgeon/be"
+)
+
+func TestStripCopy(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	src := filepath.Join(dir, "src.txt")
+	dst := filepath.Join(dir, "dst.txt")
+	b, err := saucer.Write(saucer.Record{Title: "Example"}, []byte("hello")...)
+	be.Err(t, err, nil)
+	be.Err(t, os.WriteFile(src, b, 0o600), nil)
+	be.Err(t, os.WriteFile(dst, []byte("hello world"), 0o600), nil)
+
+	be.Err(t, sauce.Copy(nil, src, dst), nil)
+	p, err := os.ReadFile(dst)
+	be.Err(t, err, nil)
+	r, err := saucer.Read(p...)
+	be.Err(t, err, nil)
+	be.Equal(t, r.Title, "Example")
+	be.Equal(t, r.FileSize, uint32(len("hello world")))
+
+	be.Err(t, sauce.Strip(nil, nil, dst), nil)
+	p, err = os.ReadFile(dst)
+	be.Err(t, err, nil)
+	be.Equal(t, string(p), "hello world")
+	be.Err(t, sauce.Copy(nil, dst, src), saucer.ErrNone)
+}
+
+func TestUpdate(t *testing.T) {
+	t.Parallel()
+	r := saucer.Record{Title: "keep"}
+	be.Equal(t, sauce.Update(nil, r).Title, "keep")
+}
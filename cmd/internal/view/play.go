@@ -0,0 +1,166 @@
+package view
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/baud"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/pager"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+	"golang.org/x/text/encoding"
+)
+
+const reset = "\x1b[0m" // reset restores the default display attributes.
+
+// Baud returns the rate of the "baud" flag, or zero when the text is not played.
+func Baud(cmd *cobra.Command) int {
+	if cmd == nil {
+		return 0
+	}
+	rate, err := cmd.Flags().GetInt("baud")
+	if err != nil {
+		return 0
+	}
+	return rate
+}
+
+// Play writes the ANSI text to w at the speed of a modem connection of the baud rate,
+// so ANSI animations are drawn the same as they were on a BBS. The escape sequences
+// are written as is for the terminal to interpret, instead of using the virtual screen.
+// The colors of the text are converted for the terminal using out.
+// When w is a terminal, the space key pauses and resumes the playback, the n or right
+// key draws the next frame while paused, and the q key stops the playback.
+func Play(w io.Writer, c *convert.Convert, out ansi.Colors, in encoding.Encoding, rate int, b ...byte) error {
+	if c == nil {
+		return ErrConv
+	}
+	if err := baud.Valid(rate); err != nil {
+		return fmt.Errorf("play: %w", err)
+	}
+	r, err := ANSI(c, in, b...)
+	if err != nil {
+		return fmt.Errorf("play: %w", err)
+	}
+	frames := baud.Frames(rate, out.Runes(r...)...)
+	f, ok := w.(*os.File)
+	if !ok || !pager.IsTerminal(f) {
+		return play(w, nil, frames...)
+	}
+	tty, closer := pager.TTY()
+	defer closer()
+	fd := int(tty.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return fmt.Errorf("play: %w: %w", pager.ErrNoTerm, err)
+	}
+	defer func() {
+		_ = term.Restore(fd, state)
+	}()
+	keys, done := make(chan string), make(chan struct{})
+	defer close(done)
+	go read(tty, keys, done)
+	return play(&newlines{w: w}, keys, frames...)
+}
+
+// ANSI converts the bytes using the in encoding and returns the runes with the
+// ANSI escape sequences as is. The settings of c are not changed.
+func ANSI(c *convert.Convert, in encoding.Encoding, b ...byte) ([]rune, error) {
+	if c == nil {
+		return nil, ErrConv
+	}
+	x := convert.Convert{Args: c.Args}
+	x.Input.Encoding = c.Input.Encoding
+	if in != nil {
+		x.Input.Encoding = in
+	}
+	r, err := x.ANSI(b...)
+	if err != nil {
+		return nil, fmt.Errorf("ansi: %w", err)
+	}
+	return r, nil
+}
+
+// play writes a frame to w every baud.Delay, while the keys pause, step and stop the playback.
+func play(w io.Writer, keys <-chan string, frames ...[]rune) error {
+	tick := time.NewTicker(baud.Delay)
+	defer tick.Stop()
+	paused := false
+	for i := 0; i < len(frames); {
+		select {
+		case key := <-keys:
+			switch key {
+			case " ", "p", "P":
+				paused = !paused
+				continue
+			case "q", "Q", pager.Escape, pager.Interrupt:
+				fmt.Fprint(w, reset)
+				return nil
+			case "n", "N", pager.Right:
+				if !paused {
+					continue
+				}
+			default:
+				continue
+			}
+		case <-tick.C:
+			if paused {
+				continue
+			}
+		}
+		if _, err := io.WriteString(w, string(frames[i])); err != nil {
+			return fmt.Errorf("play: %w", err)
+		}
+		i++
+	}
+	return nil
+}
+
+// read sends the key presses of the terminal until done is closed.
+func read(tty io.Reader, keys chan<- string, done <-chan struct{}) {
+	buf := make([]byte, 64) //nolint:mnd
+	for {
+		n, err := tty.Read(buf)
+		if err != nil {
+			return
+		}
+		for _, key := range pager.Keys(buf[:n]...) {
+			select {
+			case keys <- key:
+			case <-done:
+				return
+			}
+		}
+	}
+}
+
+// newlines writes a carriage return before any line feed that is without one,
+// as a terminal in raw mode only moves the cursor down a line.
+type newlines struct {
+	w    io.Writer
+	last byte
+}
+
+func (n *newlines) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	b := make([]byte, 0, len(p))
+	prev := n.last
+	for _, c := range p {
+		if c == '\n' && prev != '\r' {
+			b = append(b, '\r')
+		}
+		b = append(b, c)
+		prev = c
+	}
+	n.last = prev
+	if _, err := n.w.Write(b); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -2,14 +2,23 @@
 package view
 
 import (
+	"bufio"
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
-	"unicode/utf8"
+	"os"
 
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/baud"
+	"github.com/bengarrett/retrotxtgo/bintext"
 	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/pager"
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/detect"
 	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/saucer"
 	"github.com/bengarrett/retrotxtgo/term"
 	"github.com/spf13/cobra"
 	"golang.org/x/text/encoding"
@@ -31,6 +40,15 @@ func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
 	if err != nil {
 		return fmt.Errorf("%s: %w", name, err)
 	}
+	if rate := Baud(cmd); rate > 0 {
+		if err := baud.Valid(rate); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	out, err := Colors(cmd)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
 	if ok {
 		return Pipe(w, cmd, args...)
 	}
@@ -48,6 +66,12 @@ func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
 			fmt.Fprintln(w)
 			term.HR(w, halfPage)
 		}
+		if Streamable(cmd, c) && !sample.Valid(arg) && fsys.Large(arg) {
+			if err := Stream(w, cmd, c, samp, arg); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
 		b, err := flag.ReadArgument(arg, c, samp)
 		if err != nil {
 			return fmt.Errorf("%s: %w", name, err)
@@ -58,12 +82,31 @@ func Run(w io.Writer, cmd *cobra.Command, args ...string) error {
 			fmt.Fprint(w, string(b))
 			continue
 		}
+		out.ICE = ICE(cmd, b...)
+		if ok, err := Art(w, out, arg, b...); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		} else if ok {
+			continue
+		}
+		in := flag.Input(cmd, samp, arg, b...)
+		if Paging(cmd) {
+			if err := Page(w, c, out, in, arg, b...); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
+		if rate := Baud(cmd); rate > 0 {
+			if err := Play(w, c, out, in, rate, b...); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			continue
+		}
 		// write out the sample with the utf-8 encoding
-		r, err := Transform(c, samp.Input, nil, b...)
+		r, err := Transform(c, in, nil, b...)
 		if err != nil {
 			return err
 		}
-		fmt.Fprint(w, string(r))
+		fmt.Fprint(w, string(out.Runes(Screen(r...)...)))
 	}
 	fmt.Fprintln(w)
 	return nil
@@ -78,30 +121,259 @@ func Pipe(w io.Writer, cmd *cobra.Command, args ...string) error {
 	if err != nil {
 		return fmt.Errorf("cmd view pipe: %w", err)
 	}
-	b, err := fsys.ReadPipe()
+	out, err := Colors(cmd)
+	if err != nil {
+		return fmt.Errorf("cmd view pipe: %w", err)
+	}
+	b, large, err := fsys.ReadLargePipe()
 	if err != nil {
 		return fmt.Errorf("%w, %w", ErrPipeRead, err)
 	}
+	if large != nil {
+		if Streamable(cmd, c) {
+			// the SAUCE metadata at the end of piped data is not known,
+			// so only the "ice" flag is used
+			return stream(w, cmd, c, samp, "", large, nil)
+		}
+		if b, err = io.ReadAll(large); err != nil {
+			return fmt.Errorf("%w, %w", ErrPipeRead, err)
+		}
+	}
+	out.ICE = ICE(cmd, b...)
+	if ok, err := Art(w, out, "", b...); err != nil {
+		return fmt.Errorf("cmd view pipe: %w", err)
+	} else if ok {
+		fmt.Fprintln(w)
+		return nil
+	}
+	in := flag.Input(cmd, samp, "", b...)
+	if Paging(cmd) {
+		if err := Page(w, c, out, in, "stdin", b...); err != nil {
+			return fmt.Errorf("cmd view pipe: %w", err)
+		}
+		return nil
+	}
+	if rate := Baud(cmd); rate > 0 {
+		if err := Play(w, c, out, in, rate, b...); err != nil {
+			return fmt.Errorf("cmd view pipe: %w", err)
+		}
+		return nil
+	}
 	// write out the sample with the utf-8 encoding
-	r, err := Transform(c, samp.Input, nil, b...)
+	r, err := Transform(c, in, nil, b...)
+	if err != nil {
+		return err
+	}
+	fmt.Fprint(w, string(out.Runes(Screen(r...)...)))
+	return nil
+}
+
+// Streamable reports whether large texts can be converted in chunks,
+// which is not possible with the pager, the baud rate playback or a maximum text width.
+func Streamable(cmd *cobra.Command, c *convert.Convert) bool {
+	return c != nil && c.Args.MaxWidth < 1 && !Paging(cmd) && Baud(cmd) < 1
+}
+
+// Stream converts and writes the named large file to w in chunks,
+// so the whole of the file is never held in memory.
+// The colors are converted for the terminal and the iCE colors are found using the SAUCE
+// metadata at the end of the file. But unlike Transform, the ANSI cursor controls are
+// written as is without using the virtual screen.
+func Stream(w io.Writer, cmd *cobra.Command, c *convert.Convert, samp sample.Flags, name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	tail := make([]byte, min(st.Size(), saucer.Size))
+	if _, err := f.ReadAt(tail, st.Size()-int64(len(tail))); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("stream: %w", err)
+	}
+	return stream(w, cmd, c, samp, name, f, tail)
+}
+
+// stream converts and writes the text read from r to w in chunks,
+// the start of the text is used to detect the character encoding and any binary text art,
+// while the tail of the text is used to find the SAUCE metadata.
+func stream(w io.Writer, cmd *cobra.Command, c *convert.Convert, samp sample.Flags, name string, r io.Reader, tail []byte,
+) error {
+	if c == nil {
+		return ErrConv
+	}
+	out, err := Colors(cmd)
+	if err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	out.ICE = ICE(cmd, tail...)
+	const detectSize = 64 * 1024
+	br := bufio.NewReaderSize(r, detectSize)
+	head, _ := br.Peek(detectSize) // a short or failed peek is returned by the convert reader
+	if bintext.Detect(name, head...) != bintext.Unknown {
+		// the binary text art is drawn from all of its cells
+		b, err := io.ReadAll(br)
+		if err != nil {
+			return fmt.Errorf("stream: %w", err)
+		}
+		ok, err := Art(w, out, name, b...)
+		if err != nil {
+			return fmt.Errorf("stream: %w", err)
+		}
+		if ok {
+			return nil
+		}
+		br = bufio.NewReader(bytes.NewReader(b))
+	}
+	in := flag.Input(cmd, samp, name, head...)
+	if in == nil {
+		in = c.Input.Encoding
+	}
+	cw := out.NewWriter(w)
+	if _, err := io.Copy(cw, convert.NewReader(br, in, c.Args)); err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	if err := cw.Flush(); err != nil {
+		return fmt.Errorf("stream: %w", err)
+	}
+	return nil
+}
+
+// Paging reports whether the "pager" flag requests the interactive pager.
+func Paging(cmd *cobra.Command) bool {
+	if cmd == nil {
+		return false
+	}
+	ok, err := cmd.Flags().GetBool("pager")
+	return err == nil && ok
+}
+
+// Page displays the named text in the interactive pager, where the in encoding
+// can be toggled with the other detection candidates.
+// The colors of the text are converted for the terminal using out.
+// When w is not a terminal, the text is written to w as is.
+func Page(w io.Writer, c *convert.Convert, out ansi.Colors, in encoding.Encoding, name string, b ...byte) error {
+	if c == nil {
+		return ErrConv
+	}
+	if in == nil {
+		in = c.Input.Encoding
+	}
+	decode := func(e encoding.Encoding) ([]rune, error) {
+		x := convert.Convert{Args: c.Args}
+		x.Input.Encoding = c.Input.Encoding
+		r, err := Transform(&x, e, nil, b...)
+		if err != nil {
+			return nil, err
+		}
+		return out.Runes(Screen(r...)...), nil
+	}
+	f, ok := w.(*os.File)
+	if !ok || !pager.IsTerminal(f) {
+		return write(w, decode, in)
+	}
+	p, err := pager.New(name, b, in, decode, detect.Candidates()...)
+	if err != nil {
+		return fmt.Errorf("page: %w", err)
+	}
+	if err := pager.Run(f, p); err != nil {
+		return fmt.Errorf("page: %w", err)
+	}
+	return nil
+}
+
+// write writes the text decoded using the in encoding to w.
+func write(w io.Writer, decode pager.Decoder, in encoding.Encoding) error {
+	r, err := decode(in)
 	if err != nil {
 		return err
 	}
-	fmt.Fprint(w, string(r))
+	fmt.Fprintln(w, string(r))
 	return nil
 }
 
+// Art writes the named binary text art, such as XBin or BinaryText, to w
+// using the out colors and reports whether the bytes are a binary text format.
+func Art(w io.Writer, out ansi.Colors, name string, b ...byte) (bool, error) {
+	img, err := bintext.Decode(name, b...)
+	if errors.Is(err, bintext.ErrFormat) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("art: %w", err)
+	}
+	fmt.Fprint(w, string(out.Runes([]rune(img.String())...)))
+	return true, nil
+}
+
+// Colors returns the output colors using the color depth of the terminal and
+// the palette of the "palette" flag, where an empty palette uses the terminal theme.
+// The terminal is described by the COLORTERM and TERM environment variables.
+func Colors(cmd *cobra.Command) (ansi.Colors, error) {
+	const highColor, basicColor = "COLORTERM", "TERM"
+	out := ansi.Colors{Depth: Depth(term.Term(term.GetEnv(highColor), term.GetEnv(basicColor)))}
+	if cmd == nil {
+		return out, nil
+	}
+	name, err := cmd.Flags().GetString("palette")
+	if err != nil || name == "" {
+		return out, nil //nolint:nilerr
+	}
+	p, err := ansi.Named(name)
+	if err != nil {
+		return ansi.Colors{}, fmt.Errorf("colors: %w", err)
+	}
+	out.Palette = &p
+	return out, nil
+}
+
+// ICE reports whether the blink attribute of the text is used for the bright
+// background colors, known as iCE colors. The "ice" flag is used when it is set,
+// otherwise the non-blink flag of the SAUCE metadata found in b is used.
+func ICE(cmd *cobra.Command, b ...byte) bool {
+	if cmd != nil && cmd.Flags().Changed("ice") {
+		ok, err := cmd.Flags().GetBool("ice")
+		return err == nil && ok
+	}
+	r, err := saucer.Read(b...)
+	return err == nil && r.ICE()
+}
+
+// Depth returns the color depth of the named terminal type returned by term.Term.
+// A monochrome terminal uses the 16 colors, as most ignore the color sequences they cannot display.
+func Depth(name string) ansi.Depth {
+	switch name {
+	case term.TermMono.String(), term.Term16.String():
+		return ansi.Depth16
+	case term.Term256.String():
+		return ansi.Depth256
+	}
+	return ansi.DepthTrue
+}
+
+// Screen interprets any ANSI cursor and display controls in the runes
+// using a virtual ANSI.SYS screen, so the text renders the same on every terminal.
+// Runes without any ANSI controls are returned as is.
+func Screen(r ...rune) []rune {
+	if !ansi.Contains(r...) {
+		return r
+	}
+	return ansi.Render(ansi.Columns, r...)
+}
+
 // Transform bytes into Unicode runes.
 // The optional in encoding argument is the bytes original character encoding.
 // The optional out encoding argument is the encoding to replicate.
 // When no encoding arguments are provided, UTF-8 unicode encoding is used.
+// Large inputs are converted in parallel.
 func Transform(c *convert.Convert, in, out encoding.Encoding, b ...byte,
 ) ([]rune, error) {
 	const name = "cmd view transform"
 	if c == nil {
 		return nil, ErrConv
 	}
-	var err error
 	if b == nil {
 		return nil, nil
 	}
@@ -109,25 +381,18 @@ func Transform(c *convert.Convert, in, out encoding.Encoding, b ...byte,
 	if in != nil {
 		c.Input.Encoding = in
 	}
-	p := b
-	// handle any encoding BEFORE outputting to Unicode
-	// we also make sure the bytes are not valid UTF-8
+	// convert the bytes into runes,
+	// any out encoding is handled BEFORE outputting to Unicode
+	// but only when the bytes are not valid UTF-8
 	// otherwise the bytes will become corrupted
-	if out != nil && !utf8.Valid(b) {
-		p, err = out.NewDecoder().Bytes(b)
-		if err != nil {
-			return nil, fmt.Errorf("%s: %w", name, err)
-		}
-	}
-	// convert the bytes into runes
 	if flag.EndOfFile(c.Args) {
-		r, err := c.Text(p...)
+		r, err := c.ParallelConvert(c.Input.Encoding, out, b...)
 		if err != nil {
 			return r, fmt.Errorf("%s: %w", name, err)
 		}
 		return r, nil
 	}
-	r, err := c.Dump(p...)
+	r, err := c.ParallelDump(c.Input.Encoding, out, b...)
 	if err != nil {
 		return r, fmt.Errorf("%s: %w", name, err)
 	}
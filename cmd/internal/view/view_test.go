@@ -1,11 +1,19 @@
 package view_test
 
 import (
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/baud"
 	"github.com/bengarrett/retrotxtgo/cmd/internal/view"
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/sample"
+	"github.com/bengarrett/retrotxtgo/saucer"
+	"github.com/bengarrett/retrotxtgo/xud"
 	"github.com/nalgeon/be"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
@@ -106,6 +114,22 @@ func TestEncodingScenarios(t *testing.T) {
 	be.Equal(t, string(r), "Hello World")
 }
 
+// Test the 7-bit ASA X3.4 texts are decoded, even though they are valid UTF-8.
+func TestTransformX34(t *testing.T) {
+	t.Parallel()
+	b := []byte("HELLO ^_ | ~ @")
+	r, err := view.Transform(&convert.Convert{}, xud.XUserDefined1965, nil, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "HELLO ^_ ¬ | `")
+	name := filepath.Join(t.TempDir(), "file.txt")
+	be.Err(t, os.WriteFile(name, b, 0o600), nil)
+	c := convert.Convert{}
+	c.Input.Encoding = xud.XUserDefined1965
+	w := &strings.Builder{}
+	be.Err(t, view.Stream(w, nil, &c, sample.Flags{}, name), nil)
+	be.Equal(t, w.String(), "HELLO ^_ ¬ | `")
+}
+
 // Test edge cases in Transform.
 func TestTransformEdgeCases(t *testing.T) {
 	t.Parallel()
@@ -131,3 +155,109 @@ func TestTransformEdgeCases(t *testing.T) {
 	be.Err(t, err, nil)
 	be.True(t, len(r) > 0)
 }
+
+// Test the virtual screen rendering of ANSI controls.
+func TestScreen(t *testing.T) {
+	t.Parallel()
+	s := []rune("Hello world")
+	be.Equal(t, string(view.Screen(s...)), string(s))
+	s = []rune("\x1b[6Cworld\x1b[11DHello")
+	be.Equal(t, string(view.Screen(s...)), "Hello world")
+	s = []rune("\x1b[1mbold")
+	be.Equal(t, string(view.Screen(s...)), "\x1b[0;1mbold\x1b[0m")
+}
+
+// Test the binary text art is drawn from its cells.
+func TestArt(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	ok, err := view.Art(&sb, ansi.Colors{}, "art.bin", 'H', 0x07, 'i', 0x0c)
+	be.Err(t, err, nil)
+	be.True(t, ok)
+	be.Equal(t, sb.String(), "H\x1b[0;91mi\x1b[0m")
+	ok, err = view.Art(&sb, ansi.Colors{}, "readme.txt", 'H', 0x07)
+	be.Err(t, err, nil)
+	be.True(t, !ok)
+	_, err = view.Art(&sb, ansi.Colors{}, "", []byte("XBIN\x1a\x00\x00\x01\x00\x10\x00")...)
+	be.True(t, err != nil)
+}
+
+func TestStream(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "file.txt")
+	be.Err(t, os.WriteFile(name, []byte("\x01 Hello\r\nworld\x1a!"), 0o600), nil)
+	c := convert.Convert{}
+	c.Args.Controls = []string{"eof"}
+	c.Input.Encoding = charmap.CodePage437
+	be.True(t, view.Streamable(nil, &c))
+	w := &strings.Builder{}
+	be.Err(t, view.Stream(w, nil, &c, sample.Flags{}, name), nil)
+	be.Equal(t, w.String(), "☺ Hello\r\nworld")
+	err := view.Stream(w, nil, &c, sample.Flags{}, filepath.Join(t.TempDir(), "missing.txt"))
+	be.True(t, err != nil)
+	c.Args.MaxWidth = 80
+	be.True(t, !view.Streamable(nil, &c))
+}
+
+// Test the large texts streamed in chunks still use the iCE colors and draw the binary text art.
+func TestStream_colors(t *testing.T) {
+	t.Parallel()
+	const text = "\x1b[5;44mhi"
+	r := saucer.New([]byte(text)...)
+	r.Flags = saucer.NonBlink
+	b, err := saucer.Write(r, []byte(text)...)
+	be.Err(t, err, nil)
+	name := filepath.Join(t.TempDir(), "ice.ans")
+	be.Err(t, os.WriteFile(name, b, 0o600), nil)
+	c := convert.Convert{}
+	c.Args.Controls = []string{"eof"}
+	c.Input.Encoding = charmap.CodePage437
+	w := &strings.Builder{}
+	be.Err(t, view.Stream(w, nil, &c, sample.Flags{}, name), nil)
+	be.Equal(t, w.String(), "\x1b[0;104mhi")
+	name = filepath.Join(t.TempDir(), "art.bin")
+	be.Err(t, os.WriteFile(name, []byte{'H', 0x07, 'i', 0x0c}, 0o600), nil)
+	w.Reset()
+	be.Err(t, view.Stream(w, nil, &c, sample.Flags{}, name), nil)
+	be.Equal(t, w.String(), "H\x1b[0;91mi\x1b[0m")
+}
+
+func TestPlay(t *testing.T) {
+	t.Parallel()
+	c := convert.Convert{}
+	c.Input.Encoding = charmap.CodePage437
+	w := &strings.Builder{}
+	be.Err(t, view.Play(w, &c, ansi.Colors{}, nil, 115200, []byte("\x1b[1;31mHi\x01\x1a!")...), nil)
+	be.Equal(t, w.String(), "\x1b[1;31mHi☺")
+	be.Equal(t, c.Input.Input, nil)
+	err := view.Play(w, &c, ansi.Colors{}, nil, 2401, 'a')
+	be.Err(t, err, baud.ErrRate)
+	err = view.Play(w, nil, ansi.Colors{}, nil, 2400, 'a')
+	be.Err(t, err, view.ErrConv)
+	be.Equal(t, view.Baud(nil), 0)
+}
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, view.Depth("none"), ansi.Depth16)
+	be.Equal(t, view.Depth("terminal"), ansi.Depth16)
+	be.Equal(t, view.Depth("terminal256"), ansi.Depth256)
+	be.Equal(t, view.Depth("terminal16m"), ansi.DepthTrue)
+	out, err := view.Colors(nil)
+	be.Err(t, err, nil)
+	be.True(t, out.Palette == nil)
+}
+
+func TestICE(t *testing.T) {
+	t.Parallel()
+	const text = "\x1b[5;44mhi"
+	r := saucer.New([]byte(text)...)
+	b, err := saucer.Write(r, []byte(text)...)
+	be.Err(t, err, nil)
+	be.True(t, !view.ICE(nil, b...))
+	r.Flags = saucer.NonBlink
+	b, err = saucer.Write(r, []byte(text)...)
+	be.Err(t, err, nil)
+	be.True(t, view.ICE(nil, b...))
+	be.True(t, !view.ICE(nil, []byte(text)...))
+}
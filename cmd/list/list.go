@@ -9,7 +9,9 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
 	"github.com/bengarrett/retrotxtgo/meta"
+	"github.com/bengarrett/retrotxtgo/micro"
 	"github.com/bengarrett/retrotxtgo/sample"
 	"github.com/bengarrett/retrotxtgo/table"
 	"github.com/bengarrett/retrotxtgo/xud"
@@ -298,6 +300,15 @@ func Tables(w io.Writer) error {
 			xud.XUserDefined1967:
 			name = xud.Name(e)
 		}
+		if xud.CodeDOS(e) || xud.CodeEBCDIC(e) {
+			name = xud.Name(e)
+		}
+		switch m := e.(type) {
+		case *micro.Encoding:
+			name = m.Value
+		case *mapping.Encoding:
+			name = m.Value
+		}
 		if name == "" {
 			name, err = ianaindex.MIME.Name(e)
 			if err != nil {
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/music"
+	"github.com/spf13/cobra"
+)
+
+const musicLong = `Save the ANSI music of text art as standard MIDI files.
+
+ANSI music is the BBS terminal extension that plays tunes on the PC speaker,
+using the ESC[M or ESC[N controls followed by the music macro language of
+the BASIC PLAY statement. The view, export, render and convert commands
+remove these sequences from the displayed text.
+
+All of the music sequences of a file are saved in order to a single MIDI file,
+played by a square wave lead that sounds like the PC speaker.
+
+The files are saved using the filename with a .mid extension,
+but piped text is written to the standard output.`
+
+func MusicCommand() *cobra.Command {
+	s := "Save the ANSI music of art as MIDI files"
+	expl := strings.Builder{}
+	example.Music.String(&expl)
+	return &cobra.Command{
+		Use:     "music " + example.Filenames,
+		Aliases: []string{"midi"},
+		GroupID: IDfile,
+		Short:   s,
+		Long:    musicLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return music.Run(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func MusicInit() *cobra.Command {
+	mc := MusicCommand()
+	mc.Flags().StringVarP(&flag.Music.OutputDir, "output-dir", "o", "",
+		"directory to save the MIDI files (default is the current directory)")
+	mc.Flags().BoolVar(&flag.Music.Overwrite, "overwrite", false,
+		"overwrite any existing MIDI files instead of using a unique filename")
+	mc.Flags().SortFlags = false
+	return mc
+}
+
+func init() {
+	Cmd.AddCommand(MusicInit())
+}
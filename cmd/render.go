@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/render"
+	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/spf13/cobra"
+)
+
+const renderLong = `Save text files and text art as PNG images.
+
+The render command reads the files in the same way as the view command,
+so the --input flag sets the character encoding of the legacy 8-bit texts.
+
+The text is drawn using the built-in bitmap fonts of the IBM PC with the
+16 colors of the VGA palette. The VGA font is 8x16 pixels per character
+and the CGA font is 8x8 pixels. Besides the characters of code page 437,
+the fonts draw the Latin, Greek and Cyrillic letters and the punctuation
+of the other 8-bit code pages, with the accented letters composed from
+their base letter and marks. Other characters, such as Arabic, Hebrew
+or Thai, are drawn using their base letter, otherwise a question mark.
+
+Files with SAUCE metadata use the character width for the number of
+columns, the 9 pixel letter-spacing and the legacy aspect ratio flags,
+and the non-blink mode for iCE color backgrounds. The --ice flag
+overrides the non-blink mode.
+
+ANSI animations (ANSImations) are saved as animated GIF images using
+--baud, where the text is drawn at the speed of a modem connection,
+such as --baud 2400 for 240 characters per second.
+
+The images are saved using the filename with a .png or .gif extension,
+but piped text is written to the standard output.`
+
+func RenderCommand() *cobra.Command {
+	s := "Save text files and art as PNG images"
+	expl := strings.Builder{}
+	example.Render.String(&expl)
+	return &cobra.Command{
+		Use:     "render " + example.Filenames,
+		Aliases: []string{"r", "png"},
+		GroupID: IDfile,
+		Short:   s,
+		Long:    renderLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return render.Run(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func RenderInit() *cobra.Command {
+	rc := RenderCommand()
+	f := flag.View()
+	s := &strings.Builder{}
+	term.Options(s, "bitmap font", true, true, "vga", "cga")
+	rc.Flags().StringVar(&flag.Render.Font, "font", "vga", s.String())
+	flag.Encode(&f.Input, rc)
+	flag.BBS(&f.BBS, rc)
+	flag.ICE(&f.ICE, rc)
+	rc.Flags().StringVarP(&flag.Render.OutputDir, "output-dir", "o", "",
+		"directory to save the images (default is the current directory)")
+	rc.Flags().BoolVar(&flag.Render.Overwrite, "overwrite", false,
+		"overwrite any existing images instead of using a unique filename")
+	rc.Flags().IntVarP(&flag.Render.Baud, "baud", "b", 0,
+		"save an animated GIF of the text drawn at the speed of a modem connection\n  "+flag.Rates()+"\n")
+	rc.Flags().SortFlags = false
+	return rc
+}
+
+func init() {
+	Cmd.AddCommand(RenderInit())
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/cmd/example"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/flag"
+	"github.com/bengarrett/retrotxtgo/cmd/internal/sauce"
+	"github.com/spf13/cobra"
+)
+
+const sauceLong = `Add, update, copy or remove the SAUCE metadata of text files and art.
+
+SAUCE is the metadata record used by the art scene and BBS software
+to describe text files and ANSI art with a title, author, group, date
+and comments. The record is placed at the end of the file after the
+DOS end-of-file marker, so it is hidden when the text is displayed.
+
+The text fields use Code Page 437 and have fixed widths,
+the title is 35 characters, the author and group are 20 characters
+and each comment line is 64 characters.`
+
+func SauceCommand() *cobra.Command {
+	s := "Add, update or remove the SAUCE metadata of files"
+	expl := strings.Builder{}
+	example.Sauce.String(&expl)
+	return &cobra.Command{
+		Use:     "sauce",
+		GroupID: IDfile,
+		Short:   s,
+		Long:    sauceLong,
+		Example: expl.String(),
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return flag.Help(cmd)
+		},
+	}
+}
+
+func SauceSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set " + example.Filenames,
+		Short: "Create or update the SAUCE metadata of files",
+		Long: "Create or update the SAUCE metadata of files.\n\n" +
+			"Files without SAUCE metadata get a new record using the date of today,\n" +
+			"otherwise only the fields of the flags in use are changed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sauce.Set(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func SauceSetInit() *cobra.Command {
+	sc := SauceSetCommand()
+	sc.Flags().StringVarP(&flag.Sauce.Title, "title", "t", "", "title of the work, up to 35 characters")
+	sc.Flags().StringVarP(&flag.Sauce.Author, "author", "a", "", "name or handle of the author, up to 20 characters")
+	sc.Flags().StringVarP(&flag.Sauce.Group, "group", "g", "", "name of the group or company, up to 20 characters")
+	sc.Flags().StringVarP(&flag.Sauce.Date, "date", "d", "", "date of creation using the CCYYMMDD format")
+	sc.Flags().Uint16Var(&flag.Sauce.TInfo1, "tinfo1", 0,
+		"type information, such as the character width of text files")
+	sc.Flags().Uint8Var(&flag.Sauce.Flags, "flags", 0,
+		"type flags, such as 1 for iCE colors of text files")
+	sc.Flags().StringArrayVarP(&flag.Sauce.Comments, "comment", "c", []string{},
+		"comment line of up to 64 characters, repeat the flag for multiple lines\n"+
+			"use an empty value to remove the comments")
+	sc.Flags().SortFlags = false
+	return sc
+}
+
+func SauceStripCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "strip " + example.Filenames,
+		Short: "Remove the SAUCE metadata from files",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sauce.Strip(cmd.OutOrStdout(), cmd, args...)
+		},
+	}
+}
+
+func SauceCopyCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "copy SRC DST",
+		Short: "Copy the SAUCE metadata from one file to another",
+		Args:  cobra.ExactArgs(2), //nolint:mnd
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return sauce.Copy(cmd.OutOrStdout(), args[0], args[1])
+		},
+	}
+}
+
+func SauceInit() *cobra.Command {
+	sc := SauceCommand()
+	sc.AddCommand(SauceSetInit(), SauceStripCommand(), SauceCopyCommand())
+	return sc
+}
+
+func init() {
+	Cmd.AddCommand(SauceInit())
+}
@@ -21,7 +21,64 @@ converted to UTF-8 before being printed to the terminal.
 
 For the legacy 8-bit texts, the input encoding will be assumed to be 
 Code Page 437 otherwise called OEM-US. But you can change this using
-the --input flag.
+the --input flag, or use --input auto to detect the most likely encoding
+of each file.
+
+ANSI art and texts that use cursor positioning controls are drawn onto
+a virtual 80 column ANSI.SYS screen before printing, so they display
+the same on every terminal.
+
+The color codes of BBS software such as PCBoard @X codes, Renegade and
+Celerity pipe codes, Telegard, Wildcat! and WWIV are detected and shown
+as ANSI colors. Use the --bbs flag to choose the dialect or --bbs none
+to print the codes as text.
+
+The Avatar/0 and Avatar/0+ codes of FidoNet and BBS texts (.avt), such as
+the color attributes, the repeated characters and the cursor movements,
+are also detected and drawn onto the virtual screen.
+
+Binary text art in the XBin, Artworx ADF, iCE Draw IDF, BinaryText
+(.bin) and TundraDraw formats is drawn using its character and color
+cells. A BinaryText file uses its SAUCE width, or else 160 columns.
+
+Teletext and videotex pages are printed using --input teletext for the
+Ceefax and Oracle T42 packets, TTI page files and raw 40 by 25 pages,
+--input viewdata for Prestel frames, or --input videotex for Minitel
+streams. The national characters of a teletext page are chosen using
+teletext-de, teletext-sv, teletext-it, teletext-fr, teletext-es or
+teletext-cs, and the mosaic graphics need a font with the Unicode
+Symbols for Legacy Computing.
+
+Wide documents, such as the 132 column texts of old printers, can be
+fitted to a narrow terminal using --width. The lines are wrapped at the
+word boundaries while keeping the line breaks, or use --wrap soft to
+join and reflow the lines of each paragraph, or --wrap truncate to cut
+off the long lines.
+
+Long texts and art can be read in a full-screen pager using --pager,
+which scrolls and searches the text, toggles the code page on the fly,
+and shows a hex dump panel and the SAUCE metadata.
+
+The colors of the text are reduced to suit the terminal, so 24-bit true
+color art is shown using 256 or 16 colors when the terminal lacks true
+color support. The 16 standard colors otherwise use the terminal theme,
+or the true colors of a classic computer with --palette, such as VGA,
+CGA, EGA, the Amiga Workbench or the Commodore 64.
+
+Much DOS art uses the blink attribute for bright background colors,
+known as iCE colors. When the SAUCE metadata sets the non-blink flag,
+or when using --ice, the blink is replaced by the bright backgrounds.
+
+ANSI animations (ANSImations) can be played at the speed of a modem
+connection using --baud, such as --baud 2400 for 240 characters per
+second. In a terminal, the space key pauses the playback, the n key
+draws the next frame while paused and the q key stops it.
+
+Files and piped texts larger than 16 MB are converted and printed in
+chunks, so they are never held in memory. These large texts print any
+ANSI cursor controls as is, without using the virtual screen, but their
+colors still use the terminal depth, --palette and --ice. The SAUCE iCE
+colors flag is only read from large files, not from piped texts.
 
 Common Code Page documents for English texts are:
   Code Page 437 (OEM-US)
@@ -73,12 +130,18 @@ func ViewInit() *cobra.Command {
 	vc := ViewCommand()
 	f := flag.View()
 	flag.Encode(&f.Input, vc)
+	flag.BBS(&f.BBS, vc)
 	flag.Controls(&f.Controls, vc)
 	flag.SwapChars(&f.Swap, vc)
 	if err := flag.OG(&f.Original, vc); err != nil {
 		log.Fatal(err)
 	}
 	flag.Width(&f.Width, vc)
+	flag.Wrap(&f.Wrap, vc)
+	flag.Pager(&f.Pager, vc)
+	flag.Baud(&f.Baud, vc)
+	flag.Palette(&f.Palette, vc)
+	flag.ICE(&f.ICE, vc)
 	vc.Flags().SortFlags = false
 	return vc
 }
@@ -0,0 +1,187 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/bengarrett/bbs"
+	"github.com/bengarrett/retrotxtgo/ansi"
+)
+
+var ErrBBS = errors.New("bbs color code dialect is not known")
+
+// The BBS color code names that do not select a dialect.
+const (
+	AutoBBS = "auto" // AutoBBS detects the BBS color code dialect used by the text.
+	NoBBS   = "none" // NoBBS keeps any BBS color codes as text.
+)
+
+// NoDialect is the BBS color code dialect returned when no codes are interpreted.
+const NoDialect bbs.BBS = -1
+
+// Dialects returns the names of the BBS color code dialects.
+func Dialects() []string {
+	return []string{
+		AutoBBS, NoBBS, "celerity", "pcboard", "renegade", "telegard", "wildcat", "wwiv", "wwiv-heart",
+	}
+}
+
+// Dialect returns the named BBS color code dialect.
+// The auto name returns the dialect found in b, while an empty or none name returns NoDialect.
+func Dialect(name string, b ...byte) (bbs.BBS, error) {
+	switch strings.ToLower(name) {
+	case "", NoBBS:
+		return NoDialect, nil
+	case AutoBBS:
+		if d := bbs.Find(bytes.NewReader(b)); d.Valid() && d != bbs.ANSI {
+			return d, nil
+		}
+		return NoDialect, nil
+	case "celerity":
+		return bbs.Celerity, nil
+	case "pcboard", "pcb":
+		return bbs.PCBoard, nil
+	case "renegade":
+		return bbs.Renegade, nil
+	case "telegard":
+		return bbs.Telegard, nil
+	case "wildcat", "wildcat!":
+		return bbs.Wildcat, nil
+	case "wwiv", "wwiv-hash":
+		return bbs.WWIVHash, nil
+	case "wwiv-heart":
+		return bbs.WWIVHeart, nil
+	}
+	return NoDialect, fmt.Errorf("%w: %s", ErrBBS, name)
+}
+
+// BBSControls replaces the BBS color codes of the dialect set by the BBS argument
+// with functional ANSI select graphic rendition controls.
+func (c *Convert) BBSControls() *Convert {
+	if c == nil {
+		return nil
+	}
+	d, err := Dialect(c.Args.BBS, c.Input.Input...)
+	if err != nil || d == NoDialect {
+		return c
+	}
+	c.Output = BBS(d, c.Output...)
+	return c
+}
+
+// BBS translates the color codes of the BBS dialect in the runes into
+// ANSI select graphic rendition controls, using the colors of the MS-DOS text mode.
+// The PCBoard clear screen and pause controls are removed.
+func BBS(dialect bbs.BBS, r ...rune) []rune {
+	t := translator{dialect: dialect}
+	return t.translate(r...)
+}
+
+// dialectCodes are the regular expressions that match the color codes of the BBS dialects.
+// The vertical bar of the pipe codes may have been decoded as the IBM broken bar,
+// and the WWIV end-of-text control may have been replaced with a glyph or control picture.
+var dialectCodes = map[bbs.BBS]*regexp.Regexp{
+	bbs.Celerity:  regexp.MustCompile(`[|¦](k|b|g|c|r|m|y|w|d|B|G|C|R|M|Y|W|S)`),
+	bbs.PCBoard:   regexp.MustCompile(`(?i)@X([0-9A-F])([0-9A-F])`),
+	bbs.Renegade:  regexp.MustCompile(`[|¦]([01][0-9]|2[0-3])`),
+	bbs.Telegard:  regexp.MustCompile("(?i)`([0-9A-F])([0-9A-F])"),
+	bbs.Wildcat:   regexp.MustCompile(`(?i)@([0-9A-F])([0-9A-F])@`),
+	bbs.WWIVHash:  regexp.MustCompile(`[|¦]#(\d)`),
+	bbs.WWIVHeart: regexp.MustCompile("[\x03♥␃]([0-9])"),
+}
+
+// codes returns the regular expression that matches the color codes of the BBS dialect,
+// or nil for the dialects without color codes.
+func codes(dialect bbs.BBS) *regexp.Regexp {
+	return dialectCodes[dialect]
+}
+
+// translator holds the display attributes of the color codes of a BBS dialect.
+type translator struct {
+	attr       ansi.Attr
+	dialect    bbs.BBS
+	background bool // background is the Celerity mode that applies colors to the background.
+}
+
+// translate replaces the color codes in the runes, the display attributes
+// are kept so the translation can continue with the following runes.
+func (t *translator) translate(r ...rune) []rune {
+	re := codes(t.dialect)
+	if re == nil {
+		return r
+	}
+	s := string(bbs.TrimControls([]byte(string(r))...))
+	s = re.ReplaceAllStringFunc(s, func(code string) string {
+		return t.replace(re.FindStringSubmatch(code)...)
+	})
+	return []rune(s)
+}
+
+// replace returns the select graphic rendition control for the submatches of a color code.
+func (t *translator) replace(m ...string) string {
+	const renegadeBG = 16
+	switch t.dialect {
+	case bbs.PCBoard, bbs.Telegard, bbs.Wildcat:
+		bg, _ := strconv.ParseUint(m[1], 16, 8)
+		fg, _ := strconv.ParseUint(m[2], 16, 8)
+		t.attr = ansi.Attr{}
+		t.foreground(int(fg))
+		t.back(int(bg))
+	case bbs.Renegade:
+		n, _ := strconv.Atoi(m[1])
+		if n < renegadeBG {
+			t.foreground(n)
+			break
+		}
+		t.back(n - renegadeBG)
+	case bbs.WWIVHash, bbs.WWIVHeart:
+		n, _ := strconv.Atoi(m[1])
+		t.foreground(n)
+	case bbs.Celerity:
+		// the codes use the order of the MS-DOS colors, with d for the dark gray
+		n := strings.Index("kbgcrmywdBGCRMYW", m[1])
+		if n < 0 {
+			// the S code swaps between the foreground and background colors
+			t.background = !t.background
+			return ""
+		}
+		if t.background {
+			t.back(n)
+			break
+		}
+		t.foreground(n)
+	case bbs.ANSI:
+	}
+	return t.attr.SGR()
+}
+
+// foreground sets the foreground to the MS-DOS color, where the bright colors use bold.
+func (t *translator) foreground(n int) {
+	t.attr.FG = ansi.Index(dos(n))
+	t.attr.Flags &^= ansi.Bold
+	if n > dosClassic-1 {
+		t.attr.Flags |= ansi.Bold
+	}
+}
+
+// back sets the background to the MS-DOS color, where the bright colors use blink.
+func (t *translator) back(n int) {
+	t.attr.BG = ansi.Index(dos(n))
+	t.attr.Flags &^= ansi.Blink
+	if n > dosClassic-1 {
+		t.attr.Flags |= ansi.Blink
+	}
+}
+
+// dosClassic is the number of standard colors of the MS-DOS text mode.
+const dosClassic = 8
+
+// dos returns the ANSI color index of the MS-DOS text mode color n,
+// as MS-DOS swaps the blue and red, and the cyan and brown positions of the ANSI order.
+func dos(n int) uint8 {
+	return [dosClassic]uint8{0, 4, 2, 6, 1, 5, 3, 7}[n%dosClassic]
+}
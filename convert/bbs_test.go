@@ -0,0 +1,111 @@
+package convert_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/bbs"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func ExampleBBS() {
+	r := convert.BBS(bbs.PCBoard, []rune("@X1FHello@X07")...)
+	fmt.Printf("%q\n", string(r))
+	// Output: "\x1b[0;1;37;44mHello\x1b[0;37;40m"
+}
+
+func TestDialect(t *testing.T) {
+	t.Parallel()
+	for _, name := range convert.Dialects() {
+		_, err := convert.Dialect(name)
+		be.Err(t, err, nil)
+	}
+	d, err := convert.Dialect("", []byte("@X1Fhello")...)
+	be.Err(t, err, nil)
+	be.Equal(t, d, convert.NoDialect)
+	d, err = convert.Dialect("auto", []byte("@X1Fhello")...)
+	be.Err(t, err, nil)
+	be.Equal(t, d, bbs.PCBoard)
+	d, err = convert.Dialect("auto", []byte("\x1b[0mhello")...)
+	be.Err(t, err, nil)
+	be.Equal(t, d, convert.NoDialect)
+	d, err = convert.Dialect("WWIV")
+	be.Err(t, err, nil)
+	be.Equal(t, d, bbs.WWIVHash)
+	_, err = convert.Dialect("ansi")
+	be.Err(t, err, convert.ErrBBS)
+}
+
+func TestBBS(t *testing.T) {
+	t.Parallel()
+	const reset, esc = "\x1b[0;37;40m", "\x1b[0;"
+	tests := []struct {
+		name    string
+		dialect bbs.BBS
+		s       string
+		want    string
+	}{
+		{"none", convert.NoDialect, "@X1Fa", "@X1Fa"},
+		{"pcboard", bbs.PCBoard, "@X07a@x4eb", reset + "a" + esc + "1;33;41mb"},
+		{"pcboard blink", bbs.PCBoard, "@X9Aa", esc + "1;5;32;44ma"},
+		{"pcboard clear", bbs.PCBoard, "@CLS@@X07a", reset + "a"},
+		{"wildcat", bbs.Wildcat, "@07@a", reset + "a"},
+		{"telegard", bbs.Telegard, "`07a", reset + "a"},
+		{"renegade", bbs.Renegade, "|03a|20b", esc + "36ma" + esc + "36;41mb"},
+		{"renegade bar", bbs.Renegade, "¦12a", esc + "1;31ma"},
+		{"wwiv hash", bbs.WWIVHash, "|#1a", esc + "34ma"},
+		{"wwiv heart", bbs.WWIVHeart, "♥7a\x037b", esc + "37ma" + esc + "37mb"},
+		{"celerity", bbs.Celerity, "|ca|Ya", esc + "36ma" + esc + "1;33ma"},
+		{"celerity swap", bbs.Celerity, "|S|ga|S|da", esc + "42ma" + esc + "1;30;42ma"},
+		{"unknown", bbs.Celerity, "|xa", "|xa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, string(convert.BBS(tt.dialect, []rune(tt.s)...)), tt.want)
+		})
+	}
+}
+
+func TestBBSControls(t *testing.T) {
+	t.Parallel()
+	c := convert.Convert{}
+	c.Args.BBS = convert.AutoBBS
+	c.Input.Encoding = charmap.CodePage437
+	r, err := c.Text([]byte("@X0Ehello")...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "\x1b[0;1;33;40mhello")
+	c = convert.Convert{}
+	c.Input.Encoding = charmap.CodePage437
+	r, err = c.Text([]byte("@X0Ehello")...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "@X0Ehello")
+}
+
+func TestAvatarControls(t *testing.T) {
+	t.Parallel()
+	// the attribute and the repeat count use the end of file marker value
+	const s = "\x16\x01\x1ahi\x19-\x1a\x1a\x16\x01\x07hidden"
+	c := convert.Convert{}
+	c.Input.Encoding = charmap.CodePage437
+	r, err := c.Text([]byte(s)...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "\x1b[0;1;32;44mhi"+strings.Repeat("-", 26))
+	c = convert.Convert{}
+	c.Input.Encoding = charmap.CodePage037
+	r, err = c.Dump([]byte("\x16\x01\x1a")...)
+	be.Err(t, err, nil)
+	be.True(t, !strings.Contains(string(r), "\x1b"))
+}
+
+func TestMusicControls(t *testing.T) {
+	t.Parallel()
+	c := convert.Convert{}
+	c.Input.Encoding = charmap.CodePage437
+	r, err := c.Text([]byte("\x1b[MFT120 O4 L8 CDE\x0e\x1b[1mhello\x1b[Nc\x0e")...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "\x1b[1mhello")
+}
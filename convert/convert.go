@@ -6,17 +6,15 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"log"
-	"math"
 	"os"
-	"regexp"
 	"strings"
 	"unicode/utf8"
 
 	"github.com/bengarrett/retrotxtgo/byter"
-	"github.com/bengarrett/retrotxtgo/fsys"
-	"github.com/bengarrett/retrotxtgo/logs"
+	"github.com/bengarrett/retrotxtgo/micro"
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/bengarrett/retrotxtgo/videotex"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/japanese"
 	"golang.org/x/text/encoding/unicode"
@@ -30,8 +28,6 @@ var (
 	ErrEncode = errors.New("no input encoding provided")
 	ErrName   = errors.New("unknown or unsupported code page name or alias")
 	ErrOutput = errors.New("nothing to output")
-	ErrWidth  = errors.New("cannot find the number columns from using line break")
-	ErrWrap   = errors.New("wrap width must be chained to c.swap")
 )
 
 // Convert 8-bit code page text encodings or Unicode byte array text to UTF-8 runes.
@@ -50,9 +46,11 @@ type Convert struct {
 
 // Flag are the user supplied values.
 type Flag struct {
-	Controls  []string // Always use these control codes.
-	SwapChars []string // Swap out these characters with common alternatives.
-	MaxWidth  int      // Maximum text width per-line.
+	Controls  []string    // Always use these control codes.
+	SwapChars []string    // Swap out these characters with common alternatives.
+	BBS       string      // BBS color code dialect to interpret, or auto to detect it.
+	MaxWidth  int         // Maximum text width per-line.
+	Wrap      reflow.Mode // Wrap mode used to fit the text to the maximum width.
 }
 
 // ANSI transforms legacy encoded ANSI into modern UTF-8 text.
@@ -61,7 +59,7 @@ type Flag struct {
 func (c *Convert) ANSI(b ...byte) ([]rune, error) {
 	c.Input.UseBreaks = true
 	c.Args.SwapChars = nil
-	c.Input.Input = byter.TrimEOF(b)
+	c.Input.Input = byter.TrimEOF(terminalCodes(c.Input.Encoding, b...))
 	if err := c.SkipCode().Transform(); err != nil {
 		return nil, fmt.Errorf("dump transform failed: %w", err)
 	}
@@ -69,7 +67,7 @@ func (c *Convert) ANSI(b ...byte) ([]rune, error) {
 	if err != nil {
 		return nil, err
 	}
-	c.ANSIControls().wrapWidth(c.Args.MaxWidth)
+	c.ANSIControls().BBSControls().wrapWidth(c.Args.MaxWidth)
 	return c.Output, nil
 }
 
@@ -95,7 +93,7 @@ func (c *Convert) Chars(b ...byte) ([]rune, error) {
 // It ignores the DOS end of file marker.
 func (c *Convert) Dump(b ...byte) ([]rune, error) {
 	c.Input.UseBreaks = true
-	c.Input.Input = b
+	c.Input.Input = terminalCodes(c.Input.Encoding, b...)
 	if err := c.SkipCode().Transform(); err != nil {
 		return nil, fmt.Errorf("dump transform failed: %w", err)
 	}
@@ -103,7 +101,7 @@ func (c *Convert) Dump(b ...byte) ([]rune, error) {
 	if err != nil {
 		return nil, err
 	}
-	c.ANSIControls().wrapWidth(c.Args.MaxWidth)
+	c.ANSIControls().BBSControls().wrapWidth(c.Args.MaxWidth)
 	return c.Output, nil
 }
 
@@ -112,7 +110,11 @@ func (c *Convert) Dump(b ...byte) ([]rune, error) {
 // It obeys the DOS end of file marker.
 func (c *Convert) Text(b ...byte) ([]rune, error) {
 	c.Input.UseBreaks = true
-	c.Input.Input = byter.TrimEOF(b)
+	c.Input.Input = terminalCodes(c.Input.Encoding, b...)
+	// the home computer encodings and the teletext pages do not use the DOS end of file marker
+	if !isDecoded(c.Input.Encoding) {
+		c.Input.Input = byter.TrimEOF(c.Input.Input)
+	}
 	if err := c.SkipCode().Transform(); err != nil {
 		return nil, fmt.Errorf("text transform failed: %w", err)
 	}
@@ -120,7 +122,7 @@ func (c *Convert) Text(b ...byte) ([]rune, error) {
 	if err != nil {
 		return nil, err
 	}
-	c.ANSIControls().wrapWidth(c.Args.MaxWidth)
+	c.ANSIControls().BBSControls().wrapWidth(c.Args.MaxWidth)
 	return c.Output, nil
 }
 
@@ -142,8 +144,35 @@ func (c *Convert) Transform() error {
 		c.Output = buf
 		return nil
 	}
+	// the home computer encodings are decoded, as they are not compatible with ASCII,
+	// while their tables use the glyphs of the machine fonts
+	if m, ok := c.Input.Encoding.(*micro.Encoding); ok {
+		d := m.NewDecoder()
+		if c.Input.Table {
+			d = m.Glyphs().NewDecoder()
+		}
+		b, err := d.Bytes(c.Input.Input)
+		if err != nil {
+			return fmt.Errorf("convert transform: %w", err)
+		}
+		buf := getRuneBuffer()
+		buf = append(buf[:0], bytes.Runes(b)...)
+		c.Output = buf
+		return nil
+	}
+	// the teletext and videotex pages are decoded into text with ANSI colors
+	if v, ok := c.Input.Encoding.(*videotex.Encoding); ok {
+		b, err := v.NewDecoder().Bytes(c.Input.Input)
+		if err != nil {
+			return fmt.Errorf("convert transform: %w", err)
+		}
+		buf := getRuneBuffer()
+		buf = append(buf[:0], bytes.Runes(b)...)
+		c.Output = buf
+		return nil
+	}
 	// use the input bytes if they are already valid UTF-8 runes
-	if utf8.Valid(c.Input.Input) {
+	if utf8.Valid(c.Input.Input) && !isVariant(c.Input.Encoding) {
 		// Use pool for rune allocation
 		buf := getRuneBuffer()
 		buf = append(buf[:0], bytes.Runes(c.Input.Input)...)
@@ -230,14 +259,6 @@ func unicodeDecoder(e encoding.Encoding, b ...byte) ([]rune, error) {
 	return nil, nil
 }
 
-// replaceNL replaces newlines with single spaces.
-func replaceNL(r ...rune) []rune {
-	re := regexp.MustCompile(`\r?\n`)
-	const space = " "
-	s := re.ReplaceAllString(string(r), space)
-	return []rune(s)
-}
-
 // SkipCode marks control characters to be ignored.
 // It needs to be applied before Convert.transform().
 func (c *Convert) SkipCode() *Convert {
@@ -281,46 +302,16 @@ func (c *Convert) ignore(r rune) {
 	c.Input.Ignore = append(c.Input.Ignore, r)
 }
 
-// wrapWidth enforces a row length by inserting newline characters.
-// Any tab characters are replaced with three spaces.
+// wrapWidth fits the lines of text to the maximum display width using the wrap mode argument.
+// The paragraph breaks are kept and the lines are wrapped at the word boundaries,
+// while any ANSI escape sequences are not counted or split.
 func (c *Convert) wrapWidth(maximum int) {
-	if c == nil || maximum < 1 {
+	if c == nil || maximum < 1 || len(c.Output) == 0 {
 		return
 	}
-	// remove newlines
-	c.Output = replaceNL(c.Output...)
-	// replace tabs with three spaces
-	s := string(c.Output)
-	const threeSpaces = "   "
-	s = strings.ReplaceAll(s, "\t", threeSpaces)
-	c.Output = []rune(s)
-	cnt := len(c.Output)
-	if cnt == 0 {
-		log.Fatal(ErrWrap)
-	}
-	r := strings.NewReader(string(c.Output))
-	cols, err := fsys.Columns(r, c.Input.LineBreak)
-	if err != nil {
-		logs.FatalS(ErrWidth, err, fmt.Sprint(c.Input.LineBreak))
-	}
-	if cols <= maximum {
-		return
-	}
-	limit := math.Ceil(float64(cnt) / float64(maximum))
-	b := &bytes.Buffer{}
-	for f := float64(1); f <= limit; f++ {
-		switch f {
-		case 1:
-			fmt.Fprintf(b, "%s\n", string(c.Output[0:maximum]))
-		default:
-			i := int(f)
-			x, y := (i-1)*maximum, i*maximum
-			if y >= cnt {
-				fmt.Fprintf(b, "%s\n", string(c.Output[x:cnt]))
-				continue
-			}
-			fmt.Fprintf(b, "%s\n", string(c.Output[x:y]))
-		}
+	r := c.Output
+	if c.Input.LineBreak == [2]rune{CR, 0} {
+		r = []rune(strings.ReplaceAll(string(r), "\r", "\n"))
 	}
-	c.Output = []rune(b.String())
+	c.Output = reflow.Wrap(maximum, c.Args.Wrap, r...)
 }
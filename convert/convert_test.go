@@ -315,7 +315,9 @@ func TestConvert_wrapWidth(t *testing.T) {
 		{"empty", args{}, "", true},
 		{"no string", args{80, ""}, "", true},
 		{"string", args{80, "abcdefghi"}, "abcdefghi", false},
-		{"3 chrs", args{3, "abcdefghi"}, "abc\ndef\nghi\n", false},
+		{"3 chrs", args{3, "abcdefghi"}, "abc\ndef\nghi", false},
+		{"words", args{8, "abc def ghi"}, "abc def\nghi", false},
+		{"long word", args{4, "ab cdefgh"}, "ab\ncdef\ngh", false},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
@@ -0,0 +1,202 @@
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrPolicy     = errors.New("unmappable policy is not known")
+	ErrUnmappable = errors.New("character cannot be encoded")
+)
+
+// Policy is the handling of the characters that do not exist in the encoding.
+type Policy int
+
+const (
+	Strict   Policy = iota // Strict returns an error with the offset of the first unmappable character.
+	Replace                // Replace the unmappable characters with a question mark.
+	Translit               // Translit replaces the unmappable characters with the nearest glyph or a question mark.
+)
+
+// Policies are the names of the unmappable policies.
+func Policies() []string {
+	return []string{"error", "replace", "translit"}
+}
+
+// ParsePolicy returns the unmappable policy of the name.
+func ParsePolicy(name string) (Policy, error) {
+	switch strings.ToLower(name) {
+	case "error", "e", "strict":
+		return Strict, nil
+	case "replace", "r", "":
+		return Replace, nil
+	case "translit", "t", "transliterate":
+		return Translit, nil
+	}
+	return Strict, fmt.Errorf("%w: %s", ErrPolicy, name)
+}
+
+// Encode transforms the UTF-8 text into the legacy encoding.
+// Characters that do not exist in the encoding are handled using the unmappable policy.
+// The strict policy returns an error with the byte offset of the first unmappable character.
+func Encode(e encoding.Encoding, p Policy, b ...byte) ([]byte, error) {
+	if e == nil {
+		return nil, ErrEncode
+	}
+	if unicodeEncoding(e) {
+		if i := invalid(b...); i >= 0 {
+			return nil, fmt.Errorf("%w: invalid UTF-8 at offset %d", ErrUnmappable, i)
+		}
+		p, err := e.NewEncoder().Bytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("convert encode: %w", err)
+		}
+		return p, nil
+	}
+	enc := e.NewEncoder()
+	cache := map[rune][]byte{}
+	encode := func(r rune) []byte {
+		if c, ok := cache[r]; ok {
+			return c
+		}
+		c, err := enc.Bytes([]byte(string(r)))
+		if err != nil || r == utf8.RuneError {
+			c = nil
+		}
+		cache[r] = c
+		return c
+	}
+	question := encode('?')
+	dst := &bytes.Buffer{}
+	s := string(b)
+	for i, r := range s {
+		if c := encode(r); c != nil {
+			dst.Write(c)
+			continue
+		}
+		switch p {
+		case Strict:
+			return nil, fmt.Errorf("%w: %q at offset %d", ErrUnmappable, r, i)
+		case Replace:
+			dst.Write(question)
+		case Translit:
+			dst.Write(translit(e, encode, question, r))
+		}
+	}
+	return dst.Bytes(), nil
+}
+
+// translit returns the encoded nearest glyph of the rune, otherwise the question mark.
+func translit(e encoding.Encoding, encode func(rune) []byte, question []byte, r rune) []byte {
+	if cm, ok := e.(*charmap.Charmap); ok && cm.DecodeByte('?') == '?' {
+		if c, ok := Control(r); ok {
+			return []byte{c}
+		}
+	}
+	s := Transliterate(r)
+	if s == "" {
+		return question
+	}
+	p := []byte{}
+	for _, x := range s {
+		c := encode(x)
+		if c == nil {
+			return question
+		}
+		p = append(p, c...)
+	}
+	return p
+}
+
+// Transliterate returns the nearest ASCII or Latin-1 glyphs for the rune.
+// An empty string is returned when there is no alternative.
+func Transliterate(r rune) string {
+	m := map[rune]string{
+		'\u00a0': " ", '\u2002': " ", '\u2003': " ", '\u2009': " ", '\u200b': "",
+		'‘': "'", '’': "'", '‚': "'", '‛': "'", '′': "'",
+		'“': `"`, '”': `"`, '„': `"`, '‟': `"`, '″': `"`, '«': `"`, '»': `"`,
+		'‹': "<", '›': ">",
+		'‐': "-", '‑': "-", '‒': "-", '–': "-", '—': "-", '―': "-", '−': "-",
+		'…': "...", '•': "*", '·': ".",
+		'©': "(c)", '®': "(R)", '™': "TM", '€': "EUR", '£': "GBP", '¥': "JPY",
+		'←': "<-", '→': "->", '↔': "<->", '⇐': "<=", '⇒': "=>",
+		'×': "x", '÷': "/", '≤': "<=", '≥': ">=", '≠': "!=", '±': "+/-",
+		'½': "1/2", '¼': "1/4", '¾': "3/4",
+		'ß': "ss", 'Æ': "AE", 'æ': "ae", 'Œ': "OE", 'œ': "oe", 'Ø': "O", 'ø': "o", 'Ł': "L", 'ł': "l", 'Đ': "D", 'đ': "d",
+		'─': "-", '━': "-", '═': "=", '│': "|", '┃': "|", '║': "|",
+		'┌': "+", '┐': "+", '└': "+", '┘': "+", '├': "+", '┤': "+", '┬': "+", '┴': "+", '┼': "+",
+		'╔': "+", '╗': "+", '╚': "+", '╝': "+", '╠': "+", '╣': "+", '╦': "+", '╩': "+", '╬': "+",
+		'¦': "|", '✓': "v", '✔': "v", '✗': "x", '✘': "x",
+	}
+	if s, ok := m[r]; ok {
+		return s
+	}
+	// use the base letter of any accented letters
+	d := []rune(norm.NFD.String(string(r)))
+	if len(d) > 1 && d[0] < utf8.RuneSelf && unicode.IsLetter(d[0]) {
+		return string(d[0])
+	}
+	return ""
+}
+
+// Control returns the ASCII control code of the IBM PC glyph or Unicode control picture.
+// These glyphs are used when printing the control codes of legacy texts.
+func Control(r rune) (byte, bool) {
+	const del = 0x7f
+	if r >= SymbolNUL && r <= SymbolNUL+US {
+		return byte(r - SymbolNUL), true
+	}
+	if r == SymbolDEL || r == '⌂' {
+		return del, true
+	}
+	const glyphs = "\x00☺☻♥♦♣♠•◘○◙♂♀♪♫☼►◄↕‼¶§▬↨↑↓→←∟↔▲▼"
+	for i, x := range []rune(glyphs) {
+		if x == r && i > 0 {
+			return byte(i), true
+		}
+	}
+	return 0, false
+}
+
+// Output returns a short, lowercase name of the encoding for use in filenames.
+func Output(e encoding.Encoding) string {
+	if e == nil {
+		return ""
+	}
+	name, err := ianaindex.MIME.Name(e)
+	if err != nil || name == "" {
+		name = fmt.Sprint(e)
+	}
+	name = strings.ToLower(name)
+	return strings.NewReplacer(" ", "-", "(", "", ")", "").Replace(name)
+}
+
+func unicodeEncoding(e encoding.Encoding) bool {
+	name, err := ianaindex.MIME.Name(e)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(name, "UTF-")
+}
+
+// invalid returns the offset of the first invalid UTF-8 byte, or -1.
+func invalid(b ...byte) int {
+	for i := 0; i < len(b); {
+		r, size := utf8.DecodeRune(b[i:])
+		if r == utf8.RuneError && size <= 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
@@ -0,0 +1,95 @@
+package convert_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func ExampleEncode() {
+	b, _ := convert.Encode(charmap.CodePage437, convert.Translit, []byte("“Café” ☺ ─")...)
+	fmt.Printf("%q\n", b)
+	// Output: "\"Caf\x82\" \x01 \xc4"
+}
+
+func ExampleTransliterate() {
+	fmt.Println(convert.Transliterate('—'), convert.Transliterate('Ā'), convert.Transliterate('Ж') == "")
+	// Output: - A true
+}
+
+func TestParsePolicy(t *testing.T) {
+	t.Parallel()
+	for _, name := range convert.Policies() {
+		_, err := convert.ParsePolicy(name)
+		be.Err(t, err, nil)
+	}
+	p, err := convert.ParsePolicy("")
+	be.Err(t, err, nil)
+	be.Equal(t, p, convert.Replace)
+	_, err = convert.ParsePolicy("ignore")
+	be.Err(t, err, convert.ErrPolicy)
+}
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+	s := []byte("ab“c")
+	_, err := convert.Encode(nil, convert.Strict, s...)
+	be.Err(t, err, convert.ErrEncode)
+	_, err = convert.Encode(charmap.CodePage437, convert.Strict, s...)
+	be.Err(t, err, convert.ErrUnmappable)
+	be.True(t, err != nil && err.Error() == `character cannot be encoded: '“' at offset 2`)
+	b, err := convert.Encode(charmap.CodePage437, convert.Replace, s...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "ab?c")
+	b, err = convert.Encode(charmap.CodePage437, convert.Translit, s...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), `ab"c`)
+	// ebcdic uses its own question mark and does not use the dos control glyphs
+	b, err = convert.Encode(charmap.CodePage037, convert.Translit, []byte("A☺")...)
+	be.Err(t, err, nil)
+	be.Equal(t, b, []byte{0xc1, 0x6f})
+	b, err = convert.Encode(japanese.ShiftJIS, convert.Strict, []byte("日本")...)
+	be.Err(t, err, nil)
+	be.Equal(t, b, []byte{0x93, 0xfa, 0x96, 0x7b})
+	b, err = convert.Encode(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), convert.Strict, []byte("hi")...)
+	be.Err(t, err, nil)
+	be.Equal(t, b, []byte{'h', 0, 'i', 0})
+	_, err = convert.Encode(unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), convert.Strict, 0xff)
+	be.Err(t, err, convert.ErrUnmappable)
+	b, err = convert.Encode(charmap.CodePage437, convert.Replace, 'a', 0xff)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "a?")
+}
+
+func TestControl(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		r    rune
+		want byte
+		ok   bool
+	}{
+		{'☺', 0x01, true},
+		{'▼', 0x1f, true},
+		{'␛', 0x1b, true},
+		{'␀', 0x00, true},
+		{'⌂', 0x7f, true},
+		{'a', 0, false},
+	}
+	for _, tt := range tests {
+		c, ok := convert.Control(tt.r)
+		be.Equal(t, c, tt.want)
+		be.Equal(t, ok, tt.ok)
+	}
+}
+
+func TestOutput(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, convert.Output(charmap.CodePage437), "ibm437")
+	be.Equal(t, convert.Output(charmap.ISO8859_1), "iso-8859-1")
+	be.Equal(t, convert.Output(nil), "")
+}
@@ -11,7 +11,10 @@ import (
 
 	"github.com/bengarrett/retrotxtgo/byter"
 	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"github.com/bengarrett/retrotxtgo/micro"
 	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/bengarrett/retrotxtgo/videotex"
 	"github.com/bengarrett/retrotxtgo/xud"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
@@ -174,6 +177,22 @@ func Encoder(name string) (encoding.Encoding, error) {
 			return c, nil
 		}
 	}
+	// use the ASA ASCII, ISO-8859-11, DOS and EBCDIC code pages that are missing from the charmap package,
+	// otherwise the html index would use Windows 874 for ISO-8859-11
+	s := Shorten(name)
+	for _, n := range []string{name, s} {
+		if e := xud.CodePage(n); e != nil {
+			return e, nil
+		}
+	}
+	// use the home computer encodings
+	if e := micro.Find(name); e != nil {
+		return e, nil
+	}
+	// use the teletext and videotex page formats
+	if e := videotex.Find(name); e != nil {
+		return e, nil
+	}
 	// use iana names or alias
 	if e, err := ianaindex.IANA.Encoding(name); err == nil && e != nil {
 		return e, nil
@@ -183,14 +202,14 @@ func Encoder(name string) (encoding.Encoding, error) {
 	if e, err := htmlindex.Get(name); err == nil && e != nil {
 		return e, nil
 	}
-	s := Shorten(name)
 	a := EncodeAlias(s)
 	if a == xud.Name(xud.XUserDefinedISO11) {
-		// ISO-8859-11 uses the same characters as Windows 847
-		// except for 9 characters in rows 8 and 9.
-		// https://en.wikipedia.org/wiki/ISO/IEC_8859-11#Code_page_874_(IBM)_/_9066
 		return xud.XUserDefinedISO11, nil
 	}
+	// use the user-defined code pages
+	if e := mapping.Find(name); e != nil {
+		return e, nil
+	}
 	if e := EncodeUTF32(a); e != nil {
 		return e, nil
 	}
@@ -446,15 +465,29 @@ func (c *Convert) Swap() (*Convert, error) {
 	if c.Input.UseBreaks {
 		c.LineBreak()
 	}
+	c.pictures()
+	return c, nil
+}
+
+// pictures switches out the controls of the input encoding with their picture represenations.
+func (c *Convert) pictures() {
 	switch c.Input.Encoding {
-	case charmap.CodePage037, charmap.CodePage1047, charmap.CodePage1140:
+	case charmap.CodePage037, charmap.CodePage1047, charmap.CodePage1140,
+		xud.XUserDefined273, xud.XUserDefined277, xud.XUserDefined278, xud.XUserDefined280,
+		xud.XUserDefined284, xud.XUserDefined285, xud.XUserDefined297, xud.XUserDefined500,
+		xud.XUserDefined871, xud.XUserDefined1141, xud.XUserDefined1142, xud.XUserDefined1143,
+		xud.XUserDefined1144, xud.XUserDefined1145, xud.XUserDefined1146, xud.XUserDefined1147,
+		xud.XUserDefined1148, xud.XUserDefined1149:
 		if c.Input.Table {
 			c.RunesEBCDIC()
 		}
 		c.RunesControlsEBCDIC()
 	case charmap.CodePage437, charmap.CodePage850, charmap.CodePage852, charmap.CodePage855,
 		charmap.CodePage858, charmap.CodePage860, charmap.CodePage862, charmap.CodePage863,
-		charmap.CodePage865, charmap.CodePage866:
+		charmap.CodePage865, charmap.CodePage866,
+		xud.XUserDefined737, xud.XUserDefined775, xud.XUserDefined857, xud.XUserDefined861,
+		xud.XUserDefined864, xud.XUserDefined869, xud.XUserDefinedKamenicky, xud.XUserDefinedMazovia,
+		xud.XUserDefinedMIK:
 		c.RunesDOS()
 	case charmap.ISO8859_1, charmap.ISO8859_2, charmap.ISO8859_3, charmap.ISO8859_4, charmap.ISO8859_5,
 		charmap.ISO8859_6, charmap.ISO8859_7, charmap.ISO8859_8, charmap.ISO8859_9, charmap.ISO8859_10,
@@ -463,7 +496,8 @@ func (c *Convert) Swap() (*Convert, error) {
 		c.RunesLatin()
 	case xud.XUserDefinedISO11:
 		c.RunesLatin()
-		c.RunesXRows()
+	case xud.XUserDefined1963, xud.XUserDefined1965, xud.XUserDefined1967:
+		c.RunesControls()
 	case charmap.ISO8859_6E, charmap.ISO8859_6I, charmap.ISO8859_8E, charmap.ISO8859_8I:
 		c.RunesControls()
 		c.RunesLatin()
@@ -484,8 +518,15 @@ func (c *Convert) Swap() (*Convert, error) {
 		c.RunesControls()
 		c.RunesUTF8()
 	default:
+		switch c.Input.Encoding.(type) {
+		case *mapping.Encoding:
+			c.RunesControls()
+		case *micro.Encoding:
+			if c.Input.Table {
+				c.RunesLatin()
+			}
+		}
 	}
-	return c, nil
 }
 
 func (c *Convert) Swaps() (*Convert, error) {
@@ -844,21 +885,6 @@ func (c *Convert) RunesLatin() {
 	}
 }
 
-// RunesXRows blanks out rows 8x and 9x with spaces.
-func (c *Convert) RunesXRows() {
-	if len(c.Output) == 0 {
-		return
-	}
-	for i := range len(c.Output) {
-		switch {
-		case
-			i >= Row8 && i <= row8f,
-			i >= row9 && i <= row9f:
-			c.Output[i] = rune(SP)
-		}
-	}
-}
-
 // RunesMacintosh replaces specific Mac OS Roman characters with Unicode picture represenations.
 func (c *Convert) RunesMacintosh() {
 	const (
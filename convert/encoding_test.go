@@ -9,6 +9,9 @@ import (
 	"testing"
 
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/micro"
+	"github.com/bengarrett/retrotxtgo/videotex"
+	"github.com/bengarrett/retrotxtgo/xud"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/encoding/japanese"
@@ -247,6 +250,32 @@ func TestEncoder(t *testing.T) { //nolint:funlen
 		{"oem-1256", charmap.Windows1256, false},
 		{"oem-1257", charmap.Windows1257, false},
 		{"oem-1258", charmap.Windows1258, false},
+		{"cp737", xud.XUserDefined737, false},
+		{"IBM-775", xud.XUserDefined775, false},
+		{"oem-857", xud.XUserDefined857, false},
+		{"861", xud.XUserDefined861, false},
+		{"IBM Code Page 864", xud.XUserDefined864, false},
+		{"ibm869", xud.XUserDefined869, false},
+		{"keybcs2", xud.XUserDefinedKamenicky, false},
+		{"Mazovia", xud.XUserDefinedMazovia, false},
+		{"mik", xud.XUserDefinedMIK, false},
+		{"cp273", xud.XUserDefined273, false},
+		{"IBM-500", xud.XUserDefined500, false},
+		{"ibm01142", xud.XUserDefined1142, false},
+		{"1149", xud.XUserDefined1149, false},
+		{"ascii-63", xud.XUserDefined1963, false},
+		{"1965", xud.XUserDefined1965, false},
+		{"ansi", xud.XUserDefined1967, false},
+		{"iso-8859-11", xud.XUserDefinedISO11, false},
+		{"petscii", micro.PETSCII, false},
+		{"c64-screen", micro.ScreenCodes, false},
+		{"Atari ATASCII", micro.ATASCII, false},
+		{"spectrum", micro.ZXSpectrum, false},
+		{"CPC", micro.AmstradCPC, false},
+		{"teletext", videotex.Teletext, false},
+		{"teletext-de", videotex.Find("teletext-de"), false},
+		{"prestel", videotex.Prestel, false},
+		{"Minitel videotex", videotex.Videotex, false},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
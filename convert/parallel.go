@@ -1,10 +1,20 @@
 package convert
 
 import (
+	"bytes"
 	"runtime"
-	"sync"
+	"unicode/utf8"
 
+	"github.com/bengarrett/retrotxtgo/byter"
+	"github.com/bengarrett/retrotxtgo/xud"
+	"golang.org/x/sync/errgroup"
 	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/korean"
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
 )
 
 // Constants for parallel processing.
@@ -13,98 +23,62 @@ const (
 	maxParallelWorkers      = 4           // Maximum number of parallel workers
 	mediumDataThreshold     = 1024 * 1024 // 1MB - Use chunked conversion for data smaller than this
 	chunkedConvertChunkSize = 32768       // 32KB - Chunk size for chunked conversion
+	lineBreakSearch         = 4096        // 4KB - Distance searched back from a chunk end for a line break
 )
 
 // ParallelConvert processes large byte slices using parallel processing.
 // It automatically determines the optimal number of workers based on the
 // input size and available CPU cores.
-func (c *Convert) ParallelConvert(in, _ encoding.Encoding, b ...byte) ([]rune, error) {
-	// For small inputs, use regular conversion
-	if len(b) < smallDataThreshold { // smallDataThreshold threshold
-		c.Input.Encoding = in
-		return c.Text(b...)
-	}
-
-	// Determine optimal chunk size and number of workers
-	numWorkers := runtime.NumCPU()
-	numWorkers = min(numWorkers, maxParallelWorkers) // Limit to maxParallelWorkers workers for optimal performance
-
-	chunkSize := (len(b) + numWorkers - 1) / numWorkers
-
-	// Create worker pool
-	var wg sync.WaitGroup
-	results := make([][]rune, numWorkers)
-	errChan := make(chan error, numWorkers)
-
-	for i := range numWorkers {
-		wg.Add(1)
-		go func(workerID int) {
-			defer wg.Done()
-
-			start := workerID * chunkSize
-			end := start + chunkSize
-			if workerID == numWorkers-1 {
-				end = len(b)
-			}
-
-			if start >= end {
-				return
-			}
-
-			// Create a local copy of Convert for this goroutine to avoid data race
-			localC := *c
-			localC.Input.Encoding = in
-			runes, err := localC.Text(b[start:end]...)
-			if err != nil {
-				errChan <- err
-				return
-			}
-			results[workerID] = runes
-		}(i)
-	}
-
-	wg.Wait()
-	close(errChan)
+//
+// The result is the same as Text, the bytes are split into chunks that end with
+// a line break where possible, and never within a character or an escape control.
+// The DOS end of file marker is obeyed for the whole of the bytes.
+// The optional out encoding is the encoding to replicate, see ChunkedConvert.
+func (c *Convert) ParallelConvert(in, out encoding.Encoding, b ...byte) ([]rune, error) {
+	return c.parallel(true, in, out, b...)
+}
 
-	// Check for errors
-	if len(errChan) > 0 {
-		return nil, <-errChan
-	}
+// ParallelDump is the same as ParallelConvert, except the result is the same as Dump,
+// so it ignores the DOS end of file marker.
+func (c *Convert) ParallelDump(in, out encoding.Encoding, b ...byte) ([]rune, error) {
+	return c.parallel(false, in, out, b...)
+}
 
-	// Combine results
-	totalRunes := 0
-	for _, r := range results {
-		totalRunes += len(r)
+func (c *Convert) parallel(eof bool, in, out encoding.Encoding, b ...byte) ([]rune, error) {
+	p, err := replicate(out, b...)
+	if err != nil {
+		return nil, err
 	}
-
-	combined := make([]rune, 0, totalRunes)
-	for _, r := range results {
-		combined = append(combined, r...)
+	c.Input.Encoding = in
+	// For small inputs, use regular conversion
+	if len(p) < smallDataThreshold {
+		if eof {
+			return c.Text(p...)
+		}
+		return c.Dump(p...)
 	}
-
-	return combined, nil
+	// Determine optimal chunk size and number of workers
+	numWorkers := min(runtime.NumCPU(), maxParallelWorkers)
+	chunkSize := (len(p) + numWorkers - 1) / numWorkers
+	return c.chunks(eof, numWorkers, chunkSize, p...)
 }
 
 // ChunkedConvert processes data in fixed-size chunks for memory efficiency.
 // This is useful for very large files where memory usage is a concern.
-func (c *Convert) ChunkedConvert(in, _ encoding.Encoding, chunkSize int, b ...byte) ([]rune, error) {
+//
+// The chunks are split using the same rules as ParallelConvert.
+// When the optional out encoding is given and the bytes are not valid UTF-8,
+// the bytes are first decoded using the out encoding.
+func (c *Convert) ChunkedConvert(in, out encoding.Encoding, chunkSize int, b ...byte) ([]rune, error) {
 	if chunkSize <= 0 {
 		chunkSize = 8192 // Default 8KB chunks
 	}
-
-	var result []rune
-	for i := 0; i < len(b); i += chunkSize {
-		end := min(i+chunkSize, len(b))
-
-		c.Input.Encoding = in
-		runes, err := c.Text(b[i:end]...)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, runes...)
+	p, err := replicate(out, b...)
+	if err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	c.Input.Encoding = in
+	return c.chunks(true, 1, chunkSize, p...)
 }
 
 // OptimalConvert automatically chooses the best conversion method based on input size.
@@ -119,8 +93,12 @@ func (c *Convert) OptimalConvert(in, out encoding.Encoding, b ...byte) ([]rune,
 
 	// Small data: use regular conversion
 	if len(data) < smallDataThreshold { // smallDataThreshold
+		p, err := replicate(out, data...)
+		if err != nil {
+			return nil, err
+		}
 		c.Input.Encoding = in
-		return c.Text(data...)
+		return c.Text(p...)
 	}
 
 	// Medium data: use chunked conversion
@@ -131,3 +109,324 @@ func (c *Convert) OptimalConvert(in, out encoding.Encoding, b ...byte) ([]rune,
 	// Large data: use parallel conversion
 	return c.ParallelConvert(in, out, data...)
 }
+
+// replicate decodes b using the out encoding, unless b is nil or already valid UTF-8.
+func replicate(out encoding.Encoding, b ...byte) ([]byte, error) {
+	if out == nil || utf8.Valid(b) {
+		return b, nil
+	}
+	p, err := decode(out, b...)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(string(p)), nil
+}
+
+// chunks splits the bytes into chunks of about the size and converts them using the workers.
+// The chunks are decoded in parallel, then the line break is found using all of the text,
+// before the controls of the chunks are switched out in parallel.
+func (c *Convert) chunks(eof bool, workers, size int, b ...byte) ([]rune, error) {
+	text := c.Text
+	if !eof {
+		text = c.Dump
+	}
+	if c.Input.Encoding == nil || len(b) <= size {
+		return text(b...)
+	}
+	src := terminalCodes(c.Input.Encoding, b...)
+	if eof {
+		src = byter.TrimEOF(src)
+	}
+	// use the input bytes if they are already valid UTF-8 runes
+	utf := !isUnicode(c.Input.Encoding) && !isDecoded(c.Input.Encoding) && !isVariant(c.Input.Encoding) &&
+		utf8.Valid(src)
+	l, ok := newLayout(c.Input.Encoding, src...)
+	if utf {
+		l, ok = utf8Layout(), true
+	}
+	if !ok {
+		return text(b...)
+	}
+	offsets := l.cuts(size, src...)
+	decoded := make([][]rune, len(offsets)-1)
+	g := errgroup.Group{}
+	g.SetLimit(workers)
+	for i := range decoded {
+		g.Go(func() error {
+			e := c.Input.Encoding
+			if i > 0 {
+				e = l.rest
+			}
+			var err error
+			decoded[i], err = decodeChunk(e, utf, src[offsets[i]:offsets[i+1]]...)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	c.Input.UseBreaks = true
+	c.Input.Input = src
+	total := 0
+	for _, r := range decoded {
+		total += len(r)
+	}
+	c.Output = make([]rune, 0, total)
+	for _, r := range decoded {
+		c.Output = append(c.Output, r...)
+	}
+	if len(c.Output) == 0 {
+		return nil, ErrOutput
+	}
+	c, err := c.SkipCode().Swaps()
+	if err != nil {
+		return nil, err
+	}
+	c.LineBreak()
+	// the swaps always replace a rune with a single rune,
+	// so the chunks keep the same offsets
+	g = errgroup.Group{}
+	g.SetLimit(workers)
+	n := 0
+	for _, r := range decoded {
+		lo, hi := n, n+len(r)
+		n = hi
+		if lo == hi {
+			continue
+		}
+		g.Go(func() error {
+			x := Convert{Args: c.Args, Input: c.Input}
+			x.Output = c.Output[lo:hi]
+			x.pictures()
+			x.ANSIControls()
+			return nil
+		})
+	}
+	_ = g.Wait()
+	c.BBSControls().wrapWidth(c.Args.MaxWidth)
+	return c.Output, nil
+}
+
+// decodeChunk transforms a chunk of encoded bytes into runes using the same rules as Transform.
+func decodeChunk(e encoding.Encoding, utf bool, b ...byte) ([]rune, error) {
+	if utf {
+		return bytes.Runes(b), nil
+	}
+	if r, err := unicodeDecoder(e, b...); err != nil || len(r) > 0 {
+		return r, err
+	}
+	return decode(e, b...)
+}
+
+// layout describes where the bytes of a text encoding can be split into chunks.
+type layout struct {
+	unit   int                        // unit is the size in bytes of the code units.
+	breaks [][]byte                   // breaks are the line break controls that should end a chunk.
+	cr     []byte                     // cr is the carriage return control that can follow a line break.
+	escs   [][]byte                   // escs are the escape controls that never end a chunk.
+	start  func(b []byte, i int) bool // start reports whether a character starts at b[i].
+	rest   encoding.Encoding          // rest decodes the chunks that follow the first chunk.
+}
+
+// newLayout returns the layout of the text encoded with e.
+// It reports false for the stateful and unknown encodings that cannot be split.
+func newLayout(e encoding.Encoding, b ...byte) (layout, bool) {
+	if l, ok := unicodeLayout(e, b...); ok {
+		return l, true
+	}
+	ascii := layout{
+		unit:   1,
+		breaks: [][]byte{{LF}},
+		cr:     []byte{CR},
+		escs:   [][]byte{{ESC}},
+		start:  func([]byte, int) bool { return true },
+		rest:   e,
+	}
+	switch e {
+	case japanese.ShiftJIS, japanese.EUCJP, korean.EUCKR,
+		simplifiedchinese.GBK, simplifiedchinese.GB18030, traditionalchinese.Big5:
+		// the multi-byte characters never use bytes below the digits,
+		// so a character always follows these bytes
+		const digit0 = 0x30
+		ascii.start = func(b []byte, i int) bool {
+			return b[i-1] < digit0
+		}
+		return ascii, true
+	}
+	switch cm := e.(type) {
+	case byteDecoder:
+		// the EBCDIC code pages use a different byte for the space
+		const space, nel, lf, esc = 0x40, 0x15, 0x25, 0x27
+		if cm.DecodeByte(space) == ' ' {
+			ascii.breaks = [][]byte{{nel}, {lf}}
+			ascii.escs = [][]byte{{esc}}
+		}
+		return ascii, true
+	case *xud.Encoding:
+		// the wrapped encoding decides the layout, such as the EBCDIC code pages
+		if l, ok := newLayout(cm.Encoding, b...); ok {
+			l.rest = e
+			return l, true
+		}
+		return ascii, true
+	}
+	return layout{}, false
+}
+
+// byteDecoder is implemented by the 8-bit encodings of the charmap and mapping packages.
+type byteDecoder interface {
+	DecodeByte(b byte) rune
+}
+
+// utf8Layout returns the layout of UTF-8 text.
+func utf8Layout() layout {
+	return layout{
+		unit:   1,
+		breaks: [][]byte{{LF}},
+		cr:     []byte{CR},
+		escs:   [][]byte{{ESC}, []byte(string(rune(LeftwardsArrow))), []byte(string(rune(SymbolESC)))},
+		start: func(b []byte, i int) bool {
+			return utf8.RuneStart(b[i])
+		},
+		rest: unicode.UTF8,
+	}
+}
+
+// unicodeLayout returns the layout of the UTF-8, UTF-16 or UTF-32 text encoded with e.
+// Any byte order mark at the start of b decides the byte order of the text.
+func unicodeLayout(e encoding.Encoding, b ...byte) (layout, bool) {
+	if e == unicode.UTF8 || e == unicode.UTF8BOM {
+		return utf8Layout(), true
+	}
+	for _, big := range []bool{true, false} {
+		u16, u32 := unicode.LittleEndian, utf32.LittleEndian
+		if big {
+			u16, u32 = unicode.BigEndian, utf32.BigEndian
+		}
+		for _, bom := range []unicode.BOMPolicy{unicode.IgnoreBOM, unicode.UseBOM, unicode.ExpectBOM} {
+			if e == unicode.UTF16(u16, bom) {
+				if bom != unicode.IgnoreBOM {
+					big = order(big, []byte{0xfe, 0xff}, []byte{0xff, 0xfe}, b...)
+				}
+				return wideLayout(2, big), true //nolint:mnd
+			}
+		}
+		for _, bom := range []utf32.BOMPolicy{utf32.IgnoreBOM, utf32.UseBOM, utf32.ExpectBOM} {
+			if e == utf32.UTF32(u32, bom) {
+				if bom != utf32.IgnoreBOM {
+					big = order(big, []byte{0, 0, 0xfe, 0xff}, []byte{0xff, 0xfe, 0, 0}, b...)
+				}
+				return wideLayout(4, big), true //nolint:mnd
+			}
+		}
+	}
+	return layout{}, false
+}
+
+// order returns the byte order set by the byte order mark at the start of b,
+// otherwise the big endian byte order is returned as is.
+func order(big bool, bigBOM, littleBOM []byte, b ...byte) bool {
+	switch {
+	case bytes.HasPrefix(b, bigBOM):
+		return true
+	case bytes.HasPrefix(b, littleBOM):
+		return false
+	}
+	return big
+}
+
+// wideLayout returns the layout of the UTF-16 or UTF-32 text with the code unit size.
+func wideLayout(size int, big bool) layout {
+	unit := func(r rune) []byte {
+		p := make([]byte, size)
+		for i := range size {
+			shift := 8 * i //nolint:mnd
+			if big {
+				shift = 8 * (size - 1 - i) //nolint:mnd
+			}
+			p[i] = byte(r >> shift)
+		}
+		return p
+	}
+	l := layout{
+		unit:   size,
+		breaks: [][]byte{unit(LF)},
+		cr:     unit(CR),
+		escs:   [][]byte{unit(ESC)},
+		start:  func([]byte, int) bool { return true },
+		rest:   utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM),
+	}
+	if big {
+		l.rest = utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM)
+	}
+	if size == 4 { //nolint:mnd
+		return l
+	}
+	l.rest = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
+	if big {
+		l.rest = unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM)
+	}
+	// never split the surrogate pairs
+	const lowSurrogate, mask = 0xdc, 0xfc
+	l.start = func(b []byte, i int) bool {
+		if i+1 >= len(b) {
+			return true
+		}
+		hi := b[i+1]
+		if big {
+			hi = b[i]
+		}
+		return hi&mask != lowSurrogate
+	}
+	return l
+}
+
+// cuts returns the offsets that split b into chunks of about the size,
+// the offsets include the start and the end of b.
+func (l layout) cuts(size int, b ...byte) []int {
+	size = max(size-size%l.unit, l.unit)
+	offsets := []int{0}
+	lo := 0
+	for hi := lo + size; hi < len(b); hi += size {
+		if cut := l.cut(lo, hi, b...); cut > lo {
+			offsets = append(offsets, cut)
+			lo = cut
+			hi = cut
+		}
+	}
+	return append(offsets, len(b))
+}
+
+// cut returns the offset that ends the chunk of b that starts at lo and is not longer than hi.
+// The chunk ends with a line break where possible, otherwise it never ends
+// within a character or an escape control. The lo offset is returned when no cut is found.
+func (l layout) cut(lo, hi int, b ...byte) int {
+	// prefer to end the chunk with a line break
+	for i := hi - l.unit; i > lo && i >= hi-lineBreakSearch; i -= l.unit {
+		for _, nl := range l.breaks {
+			if !bytes.HasPrefix(b[i:], nl) {
+				continue
+			}
+			cut := i + len(nl)
+			if bytes.HasPrefix(b[cut:], l.cr) {
+				cut += len(l.cr)
+			}
+			return cut
+		}
+	}
+	for i := hi; i > lo; i -= l.unit {
+		if !l.start(b, i) {
+			continue
+		}
+		// never split an escape control from the rest of its sequence
+		for j := i - l.unit; j > lo && j >= i-streamSeqLimit; j -= l.unit {
+			for _, esc := range l.escs {
+				if bytes.HasPrefix(b[j:], esc) {
+					return j
+				}
+			}
+		}
+		return i
+	}
+	return lo
+}
@@ -5,11 +5,20 @@ package convert_test
 
 import (
 	"bytes"
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"github.com/bengarrett/retrotxtgo/xud"
 	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/traditionalchinese"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/encoding/unicode/utf32"
 )
 
 // Test ParallelConvert with different sizes.
@@ -158,3 +167,147 @@ func BenchmarkOptimalConvert(b *testing.B) {
 		})
 	}
 }
+
+// encode returns the text encoded using e.
+func encode(t *testing.T, e encoding.Encoding, s string) []byte {
+	t.Helper()
+	b, err := e.NewEncoder().Bytes([]byte(s))
+	be.Err(t, err, nil)
+	return b
+}
+
+// userDefined returns a user-defined code page that maps the ASCII and 0x80 bytes.
+func userDefined(t *testing.T) encoding.Encoding {
+	t.Helper()
+	var sb strings.Builder
+	for i := range 0x80 {
+		fmt.Fprintf(&sb, "0x%02X\t0x%04X\n", i, i)
+	}
+	sb.WriteString("0x80\t0x00C7\n")
+	e, err := mapping.Parse("cp861.txt", strings.NewReader(sb.String()))
+	be.Err(t, err, nil)
+	return e
+}
+
+// Test the chunked and parallel conversions return the same text as Text and Dump.
+func TestChunkBoundaries(t *testing.T) {
+	t.Parallel()
+	const n = 1000
+	u16 := unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM)
+	u32 := utf32.UTF32(utf32.BigEndian, utf32.IgnoreBOM)
+	tests := []struct {
+		name string
+		enc  encoding.Encoding
+		b    []byte
+	}{
+		{"cp437 lines", charmap.CodePage437, bytes.Repeat([]byte("\x1b[1;31mhello\x1b[0m \x01\r\n"), n)},
+		{"cp437 no breaks", charmap.CodePage437, bytes.Repeat([]byte("\x1b[1;31mhello\x1b[0m \x01 "), n)},
+		{"cp437 eof", charmap.CodePage437, append(bytes.Repeat([]byte("hello\r\n"), n), "\x1aworld"...)},
+		{"cp037", charmap.CodePage037, bytes.Repeat([]byte("\xc8\x85\x93\x93\x96\x40\x27\x15"), n)},
+		{"shift-jis", japanese.ShiftJIS, bytes.Repeat(encode(t, japanese.ShiftJIS, "日本語のテキスト ABC "), n)},
+		{"big5", traditionalchinese.Big5, bytes.Repeat(encode(t, traditionalchinese.Big5, "中文字 ABC "), n)},
+		{"utf8", unicode.UTF8, bytes.Repeat([]byte("←[0mhello 😄 ␛[1m"), n)},
+		{"utf16", u16, encode(t, u16, strings.Repeat("\x1b[0mhello 😄 ", n))},
+		{"utf32", u32, encode(t, u32, strings.Repeat("\x1b[0mhello 😄\n", n))},
+		{"cp273", xud.XUserDefined273, bytes.Repeat([]byte("\xc7\x99\xd0\xa1\x85\x40\x27\x15"), n)},
+		{"cp864", xud.XUserDefined864, bytes.Repeat([]byte("\x1b[1m100%\xb1 \x01\r\n"), n)},
+		{"user-defined", userDefined(t), bytes.Repeat([]byte("\x1b[1mhello\x80 \x01\r\n"), n)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := convert.Convert{}
+			c.Input.Encoding = tt.enc
+			text, err := c.Text(tt.b...)
+			be.Err(t, err, nil)
+			c = convert.Convert{}
+			c.Input.Encoding = tt.enc
+			dump, err := c.Dump(tt.b...)
+			be.Err(t, err, nil)
+			for _, size := range []int{97, 1024} {
+				c = convert.Convert{}
+				r, err := c.ChunkedConvert(tt.enc, nil, size, tt.b...)
+				be.Err(t, err, nil)
+				be.Equal(t, string(r), string(text))
+			}
+			c = convert.Convert{}
+			r, err := c.ParallelConvert(tt.enc, nil, tt.b...)
+			be.Err(t, err, nil)
+			be.Equal(t, string(r), string(text))
+			c = convert.Convert{}
+			r, err = c.ParallelDump(tt.enc, nil, tt.b...)
+			be.Err(t, err, nil)
+			be.Equal(t, string(r), string(dump))
+		})
+	}
+}
+
+// Test the out encoding is used to decode the bytes before the conversion.
+func TestChunkedConvertOut(t *testing.T) {
+	t.Parallel()
+	b := bytes.Repeat([]byte("caf\xe9\r\n"), 1000)
+	c := convert.Convert{}
+	r, err := c.ChunkedConvert(charmap.CodePage437, charmap.ISO8859_1, 100, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r[:6]), "café\r\n")
+	c = convert.Convert{}
+	r, err = c.ParallelConvert(charmap.CodePage437, charmap.ISO8859_1, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r[:6]), "café\r\n")
+}
+
+// Test the Avatar codes are translated in every chunk of a large text.
+func TestChunkedConvertAvatar(t *testing.T) {
+	t.Parallel()
+	b := bytes.Repeat([]byte("\x16\x01\x1ahello\x19-\x05 world\r\n"), 1000)
+	c := convert.Convert{}
+	r, err := c.ChunkedConvert(charmap.CodePage437, nil, 1024, b...)
+	be.Err(t, err, nil)
+	s := string(r)
+	be.True(t, !strings.ContainsRune(s, 0x16))
+	be.True(t, !strings.ContainsRune(s, 0x19))
+	be.Equal(t, strings.Count(s, "-----"), 1000)
+	be.Equal(t, strings.Count(s, "hello"), 1000)
+}
+
+// Test the ANSI music is removed from every chunk of a large text.
+func TestChunkedConvertMusic(t *testing.T) {
+	t.Parallel()
+	b := bytes.Repeat([]byte("\x1b[0mhello\x1b[MFT120L8cdefgab\x0e world\r\n"), 1000)
+	c := convert.Convert{}
+	r, err := c.ChunkedConvert(charmap.CodePage437, nil, 1024, b...)
+	be.Err(t, err, nil)
+	s := string(r)
+	be.True(t, !strings.Contains(s, "MFT120"))
+	be.True(t, !strings.Contains(s, "cdefgab"))
+	be.Equal(t, strings.Count(s, "hello world"), 1000)
+}
+
+// Test the user-defined code pages use control pictures.
+func TestUserDefined(t *testing.T) {
+	t.Parallel()
+	c := convert.Convert{}
+	c.Input.Encoding = userDefined(t)
+	r, err := c.Dump([]byte("\x01hello\x80\x81\n")...)
+	be.Err(t, err, nil)
+	be.Equal(t, string(r), "␁helloÇ�\n")
+}
+
+// Test the large 7-bit ASA X3.4 and code page 864 texts are decoded, even though they are valid UTF-8.
+func TestChunkedConvertX34(t *testing.T) {
+	t.Parallel()
+	b := bytes.Repeat([]byte("HELLO | @\r\n"), 1000)
+	c := convert.Convert{}
+	r, err := c.ChunkedConvert(xud.XUserDefined1965, nil, 1024, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, strings.Count(string(r), "HELLO ¬ `"), 1000)
+	// code page 864 uses the Arabic percent sign
+	b = bytes.Repeat([]byte("100%\r\n"), 1000)
+	r, err = c.ChunkedConvert(xud.XUserDefined864, nil, 1024, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, strings.Count(string(r), "100٪"), 1000)
+	c = convert.Convert{}
+	r, err = c.ParallelDump(xud.XUserDefined864, nil, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, strings.Count(string(r), "100٪"), 1000)
+}
@@ -0,0 +1,301 @@
+package convert
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/avatar"
+	"github.com/bengarrett/retrotxtgo/byter"
+	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/micro"
+	"github.com/bengarrett/retrotxtgo/music"
+	"github.com/bengarrett/retrotxtgo/videotex"
+	"github.com/bengarrett/retrotxtgo/xud"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Constants for the stream conversion.
+const (
+	streamChunkSize = 64 * 1024  // 64KB - Size of the text read and converted at a time
+	streamLineLimit = 256 * 1024 // 256KB - Split lines longer than this without a line break
+	streamSeqLimit  = 32         // Maximum length of a control sequence kept together when splitting
+)
+
+// Reader converts a legacy encoded text stream into UTF-8 text.
+//
+// The text is read and converted in chunks that end with a line break,
+// using the same control picture substitutions, end of file and ANSI control handling
+// as the Text and Dump methods, so the whole text is never held in memory.
+// The line break and the BBS color code dialect are found using the start of the text.
+// The MaxWidth argument is ignored.
+type Reader struct {
+	args   Flag
+	enc    encoding.Encoding
+	src    io.Reader   // src returns the decoded UTF-8 text.
+	ignore []rune      // ignore are the control runes to skip.
+	lb     [2]rune     // lb is the line break of the text.
+	bbs    *translator // bbs translates the BBS color codes or is nil.
+	buf    []byte      // buf is the decoded text that waits for a line break.
+	out    bytes.Buffer
+	chunks int
+	err    error
+}
+
+// NewReader returns a reader that converts the text read from r using the e encoding.
+// The end of file marker is obeyed when the args controls contain "eof".
+func NewReader(r io.Reader, e encoding.Encoding, args Flag) *Reader {
+	sr := &Reader{args: args, enc: e}
+	if r == nil || e == nil {
+		sr.err = ErrEncode
+		return sr
+	}
+	br := bufio.NewReaderSize(r, streamChunkSize)
+	head, _ := br.Peek(streamChunkSize) // a short or failed peek is returned by the first read
+	ascii := asciiControls(e)
+	eof := slices.Contains(args.Controls, "eof") && !isDecoded(e)
+	if ascii || eof {
+		// the parameters of the avatar codes can use the end of file marker value
+		r = br
+		if ascii {
+			r = transform.NewReader(r, music.NewStripper())
+		}
+		if ascii && avatar.Contains(head...) {
+			r = transform.NewReader(r, avatar.NewTranslator())
+		}
+		if eof {
+			r = byter.EOFReader(r)
+		}
+		br = bufio.NewReaderSize(r, streamChunkSize)
+		head, _ = br.Peek(streamChunkSize)
+	}
+	// the same decoding rules are used as Transform,
+	// except valid UTF-8 is only checked using the text that follows the first non-ASCII byte
+	switch {
+	case isUnicode(e) || isDecoded(e) || isVariant(e):
+		sr.src = transform.NewReader(br, e.NewDecoder())
+	case asciiText(e):
+		sr.src = &lateReader{br: br, e: e}
+	case !utf8.Valid(fullRunes(head)):
+		sr.src = transform.NewReader(br, e.NewDecoder())
+	default:
+		sr.src = br
+	}
+	x := Convert{Args: args}
+	sr.ignore = x.SkipCode().Input.Ignore
+	if d, err := Dialect(args.BBS, head...); err == nil && d != NoDialect {
+		sr.bbs = &translator{dialect: d}
+	}
+	return sr
+}
+
+// Read reads the converted UTF-8 text into p.
+func (r *Reader) Read(p []byte) (int, error) {
+	for r.out.Len() == 0 && r.err == nil {
+		r.err = r.fill()
+	}
+	if r.out.Len() > 0 {
+		return r.out.Read(p) //nolint:wrapcheck
+	}
+	return 0, r.err
+}
+
+// fill reads and converts the next chunk of text.
+func (r *Reader) fill() error {
+	n := len(r.buf)
+	r.buf = slices.Grow(r.buf, streamChunkSize)[:n+streamChunkSize]
+	m, err := io.ReadFull(r.src, r.buf[n:])
+	r.buf = r.buf[:n+m]
+	atEOF := errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	if err != nil && !atEOF {
+		return fmt.Errorf("convert reader: %w", err)
+	}
+	cut := len(r.buf)
+	if !atEOF {
+		cut = split(streamLineLimit, r.buf...)
+	}
+	if cut > 0 {
+		if err := r.convert(r.buf[:cut]...); err != nil {
+			return err
+		}
+		r.buf = r.buf[:copy(r.buf, r.buf[cut:])]
+	}
+	if atEOF && len(r.buf) == 0 {
+		return io.EOF
+	}
+	return nil
+}
+
+// convert switches out the controls of the decoded text and writes it to the output.
+func (r *Reader) convert(b ...byte) error {
+	c := Convert{Args: r.args}
+	c.Input.Encoding = r.enc
+	c.Input.Ignore = r.ignore
+	c.Input.UseBreaks = true
+	c.Output = bytes.Runes(b)
+	if r.chunks == 0 {
+		r.lb = fsys.LineBreaks(true, c.Output...)
+	}
+	c.Input.LineBreak = r.lb
+	if _, err := c.Swaps(); err != nil {
+		return fmt.Errorf("convert reader: %w", err)
+	}
+	if r.chunks == 0 {
+		// only warn once about any unsupported swap characters
+		r.args.SwapChars = slices.DeleteFunc(slices.Clone(r.args.SwapChars), func(s string) bool {
+			return newReplacer(s) == nil
+		})
+	}
+	c.pictures()
+	c.ANSIControls()
+	if r.bbs != nil {
+		c.Output = r.bbs.translate(c.Output...)
+	}
+	r.out.WriteString(string(c.Output))
+	r.chunks++
+	return nil
+}
+
+// split returns the length of the text in b that ends with a line break.
+// Text without a line break is only split once it is longer than the limit,
+// but never within a rune or a control sequence.
+func split(limit int, b ...byte) int {
+	if i := bytes.LastIndexByte(b, LF); i >= 0 {
+		return i + 1
+	}
+	// a carriage return at the end of the text could be followed by a line feed
+	if i := bytes.LastIndexByte(b, CR); i >= 0 && i < len(b)-1 {
+		return i + 1
+	}
+	const nel = "\u0085"
+	if i := bytes.LastIndex(b, []byte(nel)); i >= 0 {
+		return i + len(nel)
+	}
+	if len(b) < limit {
+		return 0
+	}
+	cut := len(fullRunes(b))
+	start := max(0, cut-streamSeqLimit)
+	const introducers = "\x1b←␛"
+	if i := bytes.LastIndexAny(b[start:cut], introducers); i >= 0 && start+i > 0 {
+		cut = start + i
+	}
+	return cut
+}
+
+// fullRunes returns b without any incomplete rune at the end.
+func fullRunes(b []byte) []byte {
+	for i := len(b) - 1; i >= 0 && i >= len(b)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(b[i]) {
+			continue
+		}
+		if !utf8.FullRune(b[i:]) {
+			return b[:i]
+		}
+		break
+	}
+	return b
+}
+
+// lateReader reads the ASCII text from br as is, until the first non-ASCII byte is found.
+// The text that follows is decoded using the e encoding, unless it is valid UTF-8.
+// This allows the legacy characters to be found after the start of a long ASCII text.
+type lateReader struct {
+	br  *bufio.Reader
+	e   encoding.Encoding
+	src io.Reader // src is the reader of the text after the first non-ASCII byte.
+}
+
+// Read reads the ASCII text or the text of src into p.
+func (l *lateReader) Read(p []byte) (int, error) {
+	if l.src != nil {
+		return l.src.Read(p) //nolint:wrapcheck
+	}
+	b, err := l.br.Peek(l.br.Size())
+	i := slices.IndexFunc(b, func(c byte) bool { return c >= utf8.RuneSelf })
+	switch {
+	case i == 0:
+		l.src = l.br
+		if !utf8.Valid(fullRunes(b)) {
+			l.src = transform.NewReader(l.br, l.e.NewDecoder())
+		}
+		return l.src.Read(p) //nolint:wrapcheck
+	case i > 0:
+		b = b[:i]
+	case len(b) == 0:
+		return 0, err //nolint:wrapcheck
+	}
+	n := copy(p, b)
+	_, _ = l.br.Discard(n)
+	return n, nil
+}
+
+// asciiText reports whether e encodes the printable ASCII characters using their ASCII values,
+// so the ASCII text is the same when it is decoded.
+func asciiText(e encoding.Encoding) bool {
+	if e == nil || isDecoded(e) {
+		return false
+	}
+	printable := make([]byte, 0, 0x7f-0x20)
+	for c := byte(0x20); c < 0x7f; c++ {
+		printable = append(printable, c)
+	}
+	b, err := e.NewEncoder().Bytes(printable)
+	return err == nil && bytes.Equal(b, printable)
+}
+
+// isUnicode reports whether e is one of the Unicode encodings that Transform always decodes.
+func isUnicode(e encoding.Encoding) bool {
+	const space = 0x20
+	r, err := unicodeDecoder(e, space, space, space, space)
+	return err == nil && len(r) > 0
+}
+
+// asciiControls reports whether e encodes the Avatar and ANSI music controls using their ASCII values.
+func asciiControls(e encoding.Encoding) bool {
+	if e == nil || isDecoded(e) {
+		return false
+	}
+	const controls = "\x0c\x0e\x16\x19\x1b"
+	s, err := e.NewEncoder().String(controls)
+	return err == nil && s == controls
+}
+
+// terminalCodes returns b with any ANSI music sequences removed and
+// any Avatar codes replaced by ANSI escape sequences.
+// The codes are replaced before the DOS end of file marker is obeyed,
+// as the parameters of the Avatar codes can use the marker value.
+func terminalCodes(e encoding.Encoding, b ...byte) []byte {
+	if !asciiControls(e) {
+		return b
+	}
+	if music.Contains(b...) {
+		b = music.Strip(b...)
+	}
+	if avatar.Contains(b...) {
+		b = avatar.Translate(b...)
+	}
+	return b
+}
+
+// isVariant reports whether e is an early ASA X3.4 variant of ASCII or IBM Code Page 864,
+// where the 7-bit texts are valid UTF-8 but some characters differ, so the text is always decoded.
+// Code page 864 uses the Arabic percent sign in place of the ASCII percent sign.
+func isVariant(e encoding.Encoding) bool {
+	return e == xud.XUserDefined1963 || e == xud.XUserDefined1965 || e == xud.XUserDefined864
+}
+
+// isDecoded reports whether e is a home computer encoding or a teletext or videotex page format,
+// these are not compatible with ASCII so the text is always decoded.
+func isDecoded(e encoding.Encoding) bool {
+	switch e.(type) {
+	case *micro.Encoding, *videotex.Encoding:
+		return true
+	}
+	return false
+}
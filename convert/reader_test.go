@@ -0,0 +1,101 @@
+package convert_test
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/xud"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/unicode"
+)
+
+func ExampleNewReader() {
+	r := convert.NewReader(strings.NewReader("\x01 Hello\x1aworld"), charmap.CodePage437,
+		convert.Flag{Controls: []string{"eof"}})
+	_, _ = io.Copy(os.Stdout, r)
+	// Output: ☺ Hello
+}
+
+func TestNewReader(t *testing.T) {
+	t.Parallel()
+	_, err := io.ReadAll(convert.NewReader(strings.NewReader("hello"), nil, convert.Flag{}))
+	be.Err(t, err, convert.ErrEncode)
+	b, err := io.ReadAll(convert.NewReader(strings.NewReader(""), charmap.CodePage437, convert.Flag{}))
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "")
+	b, err = io.ReadAll(convert.NewReader(strings.NewReader("100%"), xud.XUserDefined864, convert.Flag{}))
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "100٪")
+}
+
+// large returns the line repeated to create a text larger than the reader chunks.
+func large(line string) []byte {
+	const size = 300 * 1024
+	return []byte(strings.Repeat(line, size/len(line)+1))
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+	utf16, _ := unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM).NewEncoder().Bytes(
+		large("\x1b[0mhello 😄 world\r\n"))
+	tests := []struct {
+		name string
+		enc  encoding.Encoding
+		args convert.Flag
+		b    []byte
+	}{
+		{"cp437", charmap.CodePage437, convert.Flag{}, large("\x01\x02 hello\x7f world\r\n")},
+		{"cp437 ansi", charmap.CodePage437, convert.Flag{}, large("\x1b[1;31mhello\x1b[0m\r\n")},
+		{"cp437 no breaks", charmap.CodePage437, convert.Flag{}, large("\x1b[0mhello world ")},
+		{"cp437 eof", charmap.CodePage437, convert.Flag{Controls: []string{"eof"}},
+			append(large("hello\n"), []byte("\x1aworld")...)},
+		{"cp437 tab", charmap.CodePage437, convert.Flag{Controls: []string{"tab"}}, large("hello\tworld\n")},
+		{"cp437 swap", charmap.CodePage437, convert.Flag{SwapChars: []string{"pipe"}}, large("hello ¦ world\n")},
+		{"cp437 bbs", charmap.CodePage437, convert.Flag{BBS: convert.AutoBBS}, large("@X1Fhello@X07 world\n")},
+		{"cp437 avatar", charmap.CodePage437, convert.Flag{Controls: []string{"eof"}},
+			large("\x16\x01\x1ahello\x19-\x1a\x16\x08\x01\x01 world\r\n")},
+		{"cp437 music", charmap.CodePage437, convert.Flag{},
+			large("\x1b[0mhello\x1b[MFT120L8cdefgab\x0e world\r\n\x1b[2M")},
+		{"cp037", charmap.CodePage037, convert.Flag{}, large("\xc8\x85\x93\x93\x96\x40\x15")},
+		{"cp437 late", charmap.CodePage437, convert.Flag{}, append(large("hello world\r\n"), "\xc9\xcd\xbb box"...)},
+		{"cp437 late utf8", charmap.CodePage437, convert.Flag{}, append(large("hello world\r\n"), "café ╔═╗"...)},
+		{"latin1", charmap.ISO8859_1, convert.Flag{}, large("caf\xe9\x85 world\r")},
+		{"utf8", unicode.UTF8, convert.Flag{}, large("hello 😄 world\n")},
+		{"utf16", unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), convert.Flag{}, utf16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			c := convert.Convert{Args: tt.args}
+			c.Input.Encoding = tt.enc
+			want, err := c.Dump(tt.b...)
+			if len(tt.args.Controls) > 0 && tt.args.Controls[0] == "eof" {
+				c = convert.Convert{Args: tt.args}
+				c.Input.Encoding = tt.enc
+				want, err = c.Text(tt.b...)
+			}
+			be.Err(t, err, nil)
+			r := convert.NewReader(iotest.HalfReader(strings.NewReader(string(tt.b))), tt.enc, tt.args)
+			got, err := io.ReadAll(r)
+			be.Err(t, err, nil)
+			be.Equal(t, len(got), len(string(want)))
+			be.True(t, string(got) == string(want))
+		})
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	p := large("\x01\x02 hello\x7f world\r\n")
+	for b.Loop() {
+		r := convert.NewReader(strings.NewReader(string(p)), charmap.CodePage437, convert.Flag{})
+		if _, err := io.Copy(io.Discard, r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
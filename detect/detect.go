@@ -0,0 +1,485 @@
+// Package detect guesses the character encoding of legacy text using byte statistics.
+//
+// Each candidate encoding from the table package decodes the text, and the decoded
+// characters are scored on how plausible they are in context. Letters should
+// form words of a single script, box-drawing characters should group together,
+// and undefined or control characters should be rare. Multi-byte encodings
+// are scored on the validity of their byte sequences instead.
+package detect
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/table"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/ianaindex"
+	uni "golang.org/x/text/encoding/unicode"
+)
+
+// Sample is the maximum number of bytes that are scored.
+const Sample = 64 * 1024
+
+// Guess is a candidate character encoding.
+type Guess struct {
+	Encoding   encoding.Encoding // Encoding is the candidate character encoding.
+	Confidence float64           // Confidence of the match, between 0 and 1.
+}
+
+// String returns the name of the encoding and the confidence as a percentage.
+func (g Guess) String() string {
+	const percent = 100
+	if g.Encoding == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s (%.0f%%)", g.Encoding, g.Confidence*percent)
+}
+
+// Likely returns the most likely character encoding of the text.
+// CP437 is returned when b is empty or no encoding can decode the text.
+func Likely(b ...byte) encoding.Encoding {
+	g := Encodings(b...)
+	if len(g) == 0 {
+		return charmap.CodePage437
+	}
+	return g[0].Encoding
+}
+
+// Encodings returns the candidate character encodings of the text ranked by confidence.
+// Encodings that cannot decode the text are not included.
+// Encodings with the same confidence are ranked with the common legacy encodings first.
+func Encodings(b ...byte) []Guess {
+	if len(b) == 0 {
+		return nil
+	}
+	if len(b) > Sample {
+		b = b[:Sample]
+	}
+	candidates := Candidates()
+	guesses := make([]Guess, 0, len(candidates))
+	for _, e := range candidates {
+		const precision = 1000
+		c := math.Round(Score(e, b...)*precision) / precision
+		if c <= 0 {
+			continue
+		}
+		guesses = append(guesses, Guess{Encoding: e, Confidence: c})
+	}
+	slices.SortStableFunc(guesses, func(a, b Guess) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	return guesses
+}
+
+// Candidates returns the encodings used for detection in the order of preference.
+// The common legacy encodings are listed first, followed by the other encodings
+// from the table package, except for duplicates and the user-defined encoding.
+func Candidates() []encoding.Encoding {
+	common := []encoding.Encoding{
+		charmap.CodePage437,
+		uni.UTF8,
+		charmap.Windows1252,
+		charmap.ISO8859_1,
+		charmap.CodePage850,
+		charmap.ISO8859_15,
+		charmap.CodePage865,
+	}
+	e := slices.Clone(common)
+	for _, x := range table.Charmaps() {
+		if slices.Contains(common, x) {
+			continue
+		}
+		name := mime(x)
+		if name == "" || strings.HasSuffix(name, "-E") || strings.HasSuffix(name, "-I") {
+			continue
+		}
+		e = append(e, x)
+	}
+	return e
+}
+
+// Score returns the confidence, between 0 and 1, that the text uses the encoding.
+func Score(e encoding.Encoding, b ...byte) float64 {
+	if len(b) == 0 || e == nil {
+		return 0
+	}
+	switch name := mime(e); name {
+	case "UTF-8":
+		return scoreUTF8(b...)
+	case "UTF-16", "UTF-16BE", "UTF-16LE", "UTF-32", "UTF-32BE", "UTF-32LE":
+		return scoreUnicode(name, b...)
+	case "Shift_JIS":
+		return ShiftJIS(b...)
+	case "Big5":
+		return Big5(b...)
+	}
+	cm, ok := e.(*charmap.Charmap)
+	if !ok {
+		return 0
+	}
+	return SingleByte(cm, b...)
+}
+
+// SingleByte returns the confidence that the text uses the single-byte character map.
+// The bytes from 0x80 have a greater weight, as these are where the code pages differ.
+func SingleByte(cm *charmap.Charmap, b ...byte) float64 {
+	const high, weight = 0x80, 4
+	if cm == nil || len(b) == 0 {
+		return 0
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = cm.DecodeByte(c)
+	}
+	sum, total := 0.0, 0.0
+	for i := range r {
+		w := 1.0
+		if b[i] >= high {
+			w = weight
+		}
+		sum += w * plausible(at(r, i-1), r[i], at(r, i+1))
+		total += w
+	}
+	return sum / total * Signature(EBCDIC(cm), b...)
+}
+
+// EBCDIC reports whether the character map is an IBM mainframe EBCDIC code page,
+// where the space character is 0x40 instead of 0x20.
+func EBCDIC(cm *charmap.Charmap) bool {
+	const space = 0x40
+	return cm != nil && cm.DecodeByte(space) == ' '
+}
+
+// Signature returns the ratio of the space and newline bytes that match the EBCDIC or ASCII family.
+// It returns 1 when the text has no spaces or newlines.
+func Signature(ebcdic bool, b ...byte) float64 {
+	const (
+		asciiSP, asciiLF             = 0x20, 0x0a
+		ebcdicSP, ebcdicNL, ebcdicLF = 0x40, 0x15, 0x25
+	)
+	ascii := bytes.Count(b, []byte{asciiSP}) + bytes.Count(b, []byte{asciiLF})
+	ebc := bytes.Count(b, []byte{ebcdicSP}) + bytes.Count(b, []byte{ebcdicNL}) + bytes.Count(b, []byte{ebcdicLF})
+	if ascii+ebc == 0 {
+		return 1
+	}
+	if ebcdic {
+		return float64(ebc) / float64(ascii+ebc)
+	}
+	return float64(ascii) / float64(ascii+ebc)
+}
+
+// ShiftJIS returns the confidence that the text uses the Japanese Shift JIS encoding,
+// based on the validity of the lead and trail bytes of the double-byte characters.
+func ShiftJIS(b ...byte) float64 {
+	lead := func(c byte) bool { return (c >= 0x81 && c <= 0x9f) || (c >= 0xe0 && c <= 0xfc) }
+	trail := func(c byte) bool { return c >= 0x40 && c <= 0xfc && c != 0x7f }
+	kana := func(c byte) bool { return c >= 0xa1 && c <= 0xdf }
+	return doubleByte(lead, trail, kana, b...)
+}
+
+// Big5 returns the confidence that the text uses the Traditional Chinese Big5 encoding,
+// based on the validity of the lead and trail bytes of the double-byte characters.
+// Only the lead bytes of the standard Big5 character set are valid, not the vendor extensions.
+func Big5(b ...byte) float64 {
+	lead := func(c byte) bool { return c >= 0xa1 && c <= 0xf9 }
+	trail := func(c byte) bool { return (c >= 0x40 && c <= 0x7e) || (c >= 0xa1 && c <= 0xfe) }
+	none := func(byte) bool { return false }
+	return doubleByte(lead, trail, none, b...)
+}
+
+// doubleByte scores the text using the lead and trail byte ranges of a double-byte encoding.
+// Single-byte characters are scored as ASCII, while the single kana bytes are only half as likely.
+// Double-byte characters are unlikely to be found next to ASCII letters,
+// and any invalid byte sequences greatly reduce the score, as these should never be found.
+func doubleByte(lead, trail, kana func(byte) bool, b ...byte) float64 {
+	const high, half, penalty = 0x80, 0.5, 8
+	sum, pairs, invalid := 0.0, 0, 0
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c < high:
+			sum += plausible(rune(atByte(b, i-1)), rune(c), rune(atByte(b, i+1)))
+		case kana(c):
+			sum += half
+		case lead(c) && i+1 < len(b) && trail(b[i+1]):
+			sum += 2 * pair(atByte(b, i-1), atByte(b, i+2))
+			pairs++
+			i++
+		default:
+			invalid++
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	valid := float64(pairs) / float64(pairs+invalid)
+	return sum / float64(len(b)) * math.Pow(valid, penalty)
+}
+
+// pair returns how likely a double-byte character is to be found between the two bytes.
+func pair(prev, next byte) float64 {
+	const oneLetter, twoLetters = 0.6, 0.2
+	letter := func(c byte) bool { return c < utf8.RuneSelf && unicode.IsLetter(rune(c)) }
+	switch {
+	case letter(prev) && letter(next):
+		return twoLetters
+	case letter(prev) || letter(next):
+		return oneLetter
+	}
+	return 1
+}
+
+// scoreUTF8 returns the confidence that the text uses UTF-8.
+// Valid multi-byte sequences are unlikely to happen by chance in a legacy encoding,
+// while text that is only ASCII is slightly less likely than the legacy code pages.
+func scoreUTF8(b ...byte) float64 {
+	const asciiOnly = 0.99
+	if !utf8.Valid(b) {
+		return 0
+	}
+	if bytes.HasPrefix(b, []byte{0xef, 0xbb, 0xbf}) || utf8.RuneCount(b) < len(b) {
+		return 1
+	}
+	r := []rune(string(b))
+	sum := 0.0
+	for i := range r {
+		sum += plausible(at(r, i-1), r[i], at(r, i+1))
+	}
+	return sum / float64(len(r)) * asciiOnly
+}
+
+// scoreUnicode returns the confidence that the text uses the named UTF-16 or UTF-32 encoding.
+// A byte order mark is a certain match, otherwise the text is scored on the pattern of
+// zero bytes that are found in ASCII characters.
+func scoreUnicode(name string, b ...byte) float64 {
+	boms := map[string][][]byte{
+		"UTF-16":   {{0xfe, 0xff}, {0xff, 0xfe}},
+		"UTF-16BE": {{0xfe, 0xff}},
+		"UTF-16LE": {{0xff, 0xfe}},
+		"UTF-32":   {{0x00, 0x00, 0xfe, 0xff}, {0xff, 0xfe, 0x00, 0x00}},
+		"UTF-32BE": {{0x00, 0x00, 0xfe, 0xff}},
+		"UTF-32LE": {{0xff, 0xfe, 0x00, 0x00}},
+	}
+	utf32 := strings.HasPrefix(name, "UTF-32")
+	for _, bom := range boms[name] {
+		if !bytes.HasPrefix(b, bom) {
+			continue
+		}
+		// the UTF-16 little-endian mark is also the start of the UTF-32 mark
+		if !utf32 && bytes.HasPrefix(b, boms["UTF-32LE"][0]) {
+			return 0
+		}
+		return 1
+	}
+	switch name {
+	case "UTF-16BE":
+		return zeros(2, 1, b...)
+	case "UTF-16LE":
+		return zeros(2, 0, b...)
+	case "UTF-32BE":
+		return zeros(4, 3, b...)
+	case "UTF-32LE":
+		return zeros(4, 0, b...)
+	}
+	return 0
+}
+
+// zeros returns the ratio of code units of the size that are an ASCII character,
+// where every byte of the unit is zero except for the byte at the ascii index.
+func zeros(size, ascii int, b ...byte) float64 {
+	const high = 0x80
+	if len(b) < size || len(b)%size != 0 {
+		return 0
+	}
+	n := 0
+	for i := 0; i+size <= len(b); i += size {
+		unit := b[i : i+size]
+		ok := unit[ascii] != 0 && unit[ascii] < high
+		for j, c := range unit {
+			if j != ascii && c != 0 {
+				ok = false
+			}
+		}
+		if ok {
+			n++
+		}
+	}
+	return float64(n) / float64(len(b)/size)
+}
+
+// plausible returns how likely the rune is to be found in text, between 0 and 1,
+// using the previous and the next runes for context.
+func plausible(prev, r, next rune) float64 {
+	switch {
+	case r == utf8.RuneError, r >= 0x80 && r <= 0x9f:
+		return 0 // undefined characters and C1 controls
+	case r < ' ', r == 0x7f:
+		return control(r)
+	case r < utf8.RuneSelf:
+		return 1
+	case unicode.IsLetter(r):
+		return letter(prev, r, next)
+	case unicode.Is(unicode.Mn, r):
+		return mark(prev)
+	case unicode.IsSpace(r):
+		return 1
+	case Box(r):
+		return box(prev, r, next)
+	}
+	return symbol(prev, next)
+}
+
+// mark returns how likely the combining mark is to be found in text.
+// Marks are only used with the non-Latin letters of the legacy code pages, such as Hebrew and Thai.
+func mark(prev rune) float64 {
+	const unlikely = 0.1
+	if prev >= utf8.RuneSelf && unicode.IsLetter(prev) && script(prev) != unicode.Latin {
+		return 1
+	}
+	return unlikely
+}
+
+// control returns how likely the C0 control is to be found in text.
+// The common text and ANSI controls are always likely.
+func control(r rune) float64 {
+	const unlikely = 0.3
+	switch r {
+	case '\t', '\n', '\r', '\f', 0x1a, 0x1b:
+		return 1
+	}
+	return unlikely
+}
+
+// letter returns how likely the non-ASCII letter is to be found in text.
+// Letters within a word should use the same script and not flip between cases,
+// while repeated letters and words of only capital letters are less likely.
+// Accented Latin letters are usually found next to ASCII letters,
+// unlike the letters of the other scripts which are all non-ASCII.
+func letter(prev, r, next rune) float64 {
+	const (
+		unlikely = 0.1
+		mixed    = 0.3
+		capitals = 0.8
+		isolated = 0.6
+	)
+	if !unicode.IsLetter(prev) {
+		if unicode.IsLetter(next) {
+			return 1
+		}
+		return isolated
+	}
+	latin := script(r) == unicode.Latin
+	switch {
+	case prev == r:
+		return unlikely
+	case latin && prev >= utf8.RuneSelf:
+		return mixed
+	case script(prev) != script(r) && prev >= utf8.RuneSelf:
+		return unlikely
+	case script(prev) != script(r):
+		return mixed
+	case unicode.IsUpper(r) && unicode.IsLower(prev):
+		return mixed
+	case unicode.IsUpper(r) && unicode.IsUpper(prev):
+		return capitals
+	}
+	return 1
+}
+
+// box returns how likely the box-drawing, block or shade character is to be found in text.
+// These characters are usually grouped together or separated by spaces,
+// the neighboring line-drawing characters should join together,
+// and they are rarely found next to non-ASCII letters.
+func box(prev, r, next rune) float64 {
+	const unlikely = 0.3
+	letter := func(r rune) bool { return r >= utf8.RuneSelf && unicode.IsLetter(r) }
+	if letter(prev) || letter(next) {
+		return unlikely
+	}
+	if !Joins(prev, r) || !Joins(r, next) {
+		return unlikely
+	}
+	return 1
+}
+
+// Joins reports whether the horizontal lines of the two neighboring box-drawing characters join together.
+// Any other characters always join.
+func Joins(left, right rune) bool {
+	const (
+		toLeft  = "┤╡╢╖╕╣╗╝╜╛┐┴┬─┼╩╦═╬╧╨╤╥╫╪┘"
+		toRight = "┴┬├─┼╞╟╚╔╩╦╠═╬╧╨╤╥╙╘╒╓╫╪┌└"
+		lines   = "│║" + toLeft + toRight
+	)
+	if !strings.ContainsRune(lines, left) || !strings.ContainsRune(lines, right) {
+		return true
+	}
+	return strings.ContainsRune(toRight, left) == strings.ContainsRune(toLeft, right)
+}
+
+// symbol returns how likely the non-ASCII punctuation, symbol or number is to be found in text.
+// These characters are unlikely to be found within a word or next to a non-ASCII letter.
+func symbol(prev, next rune) float64 {
+	const (
+		inWord     = 0.2
+		nearLetter = 0.5
+	)
+	letter := func(r rune) bool { return r >= utf8.RuneSelf && unicode.IsLetter(r) }
+	switch {
+	case unicode.IsLetter(prev) && unicode.IsLetter(next):
+		return inWord
+	case letter(prev) || letter(next):
+		return nearLetter
+	}
+	return 1
+}
+
+// Box reports whether the rune is a box-drawing, block element or a geometric shape character.
+func Box(r rune) bool {
+	const first, last = 0x2500, 0x25ff
+	return r >= first && r <= last
+}
+
+// script returns the writing system of the letter, or nil for any other script.
+func script(r rune) *unicode.RangeTable {
+	for _, t := range []*unicode.RangeTable{
+		unicode.Latin, unicode.Greek, unicode.Cyrillic, unicode.Hebrew,
+		unicode.Arabic, unicode.Thai, unicode.Han, unicode.Hiragana, unicode.Katakana,
+	} {
+		if unicode.Is(t, r) {
+			return t
+		}
+	}
+	return nil
+}
+
+// at returns the rune at index i or zero when i is out of range.
+func at(r []rune, i int) rune {
+	if i < 0 || i >= len(r) {
+		return 0
+	}
+	return r[i]
+}
+
+// atByte returns the byte at index i or zero when i is out of range.
+func atByte(b []byte, i int) byte {
+	if i < 0 || i >= len(b) {
+		return 0
+	}
+	return b[i]
+}
+
+// mime returns the MIME name of the encoding, or an empty string when it has no name.
+func mime(e encoding.Encoding) string {
+	name, err := ianaindex.MIME.Name(e)
+	if err != nil {
+		return ""
+	}
+	return name
+}
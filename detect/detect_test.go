@@ -0,0 +1,141 @@
+package detect_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/detect"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	uni "golang.org/x/text/encoding/unicode"
+)
+
+const (
+	russian = "Привет! Это простой текст на русском языке, " +
+		"написанный для проверки определения кодировки."
+	german = "Grüße aus München! Die Bäckerei öffnet früh, " +
+		"und das Frühstück ist köstlich."
+	japan = "こんにちは、世界。これは日本語のテキストです。"
+	boxes = "╔══════════╗\r\n║ RetroTxt ║\r\n╚══════════╝\r\n" +
+		"┌──┬──┐\r\n│░░│▓▓│\r\n└──┴──┘\r\n"
+)
+
+func encode(t *testing.T, e encoding.Encoding, s string) []byte {
+	t.Helper()
+	b, err := e.NewEncoder().Bytes([]byte(s))
+	be.Err(t, err, nil)
+	return b
+}
+
+func ExampleLikely() {
+	b, _ := charmap.KOI8R.NewEncoder().Bytes([]byte("Съешь же ещё этих мягких французских булок, да выпей чаю."))
+	fmt.Println(detect.Likely(b...))
+	// Output: KOI8-R
+}
+
+func ExampleEncodings() {
+	g := detect.Encodings([]byte("Hello world")...)
+	fmt.Println(g[0])
+	// Output: IBM Code Page 437 (100%)
+}
+
+func TestLikely(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		e    encoding.Encoding
+		s    string
+	}{
+		{"cp866", charmap.CodePage866, russian},
+		{"win1251", charmap.Windows1251, russian},
+		{"koi8r", charmap.KOI8R, russian},
+		{"iso5", charmap.ISO8859_5, russian},
+		{"win1252", charmap.Windows1252, german},
+		{"cp437", charmap.CodePage437, german},
+		{"boxes", charmap.CodePage437, boxes},
+		{"ebcdic", charmap.CodePage037, "HELLO WORLD, THIS IS A MAINFRAME.\n"},
+		{"shiftjis", japanese.ShiftJIS, japan},
+		{"utf8", uni.UTF8, german},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := encode(t, tt.e, tt.s)
+			be.Equal(t, fmt.Sprint(detect.Likely(b...)), fmt.Sprint(tt.e))
+		})
+	}
+}
+
+func TestLikelyUnicode(t *testing.T) {
+	t.Parallel()
+	le := uni.UTF16(uni.LittleEndian, uni.IgnoreBOM)
+	b := encode(t, le, "Hello world")
+	be.Equal(t, fmt.Sprint(detect.Likely(b...)), fmt.Sprint(le))
+	b = append([]byte{0xff, 0xfe}, b...)
+	g := detect.Encodings(b...)
+	be.Equal(t, g[0].Confidence, 1.0)
+	be.True(t, strings.HasPrefix(g[0].Encoding.(fmt.Stringer).String(), "UTF-16"))
+}
+
+func TestEncodings(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(detect.Encodings()), 0)
+	be.True(t, detect.Likely() == charmap.CodePage437)
+	g := detect.Encodings([]byte(strings.Repeat("A", detect.Sample*2))...)
+	be.True(t, len(g) > 1)
+	for i := 1; i < len(g); i++ {
+		be.True(t, g[i-1].Confidence >= g[i].Confidence)
+	}
+	be.Equal(t, detect.Guess{}.String(), "")
+}
+
+func TestCandidates(t *testing.T) {
+	t.Parallel()
+	c := detect.Candidates()
+	be.True(t, c[0] == charmap.CodePage437)
+	for _, e := range c {
+		be.True(t, e != charmap.XUserDefined)
+	}
+}
+
+func TestEBCDIC(t *testing.T) {
+	t.Parallel()
+	be.True(t, detect.EBCDIC(charmap.CodePage037))
+	be.True(t, !detect.EBCDIC(charmap.CodePage437))
+	b := encode(t, charmap.CodePage037, "SOME TEXT\n")
+	be.Equal(t, detect.Signature(true, b...), 1.0)
+	be.Equal(t, detect.Signature(false, b...), 0.0)
+	be.Equal(t, detect.Signature(false, []byte("some text\n")...), 1.0)
+}
+
+func TestShiftJIS(t *testing.T) {
+	t.Parallel()
+	b := encode(t, japanese.ShiftJIS, japan)
+	be.True(t, detect.ShiftJIS(b...) > detect.Big5(b...))
+	be.Equal(t, detect.ShiftJIS([]byte("plain ascii")...), 0.0)
+	be.Equal(t, detect.ShiftJIS(0x81), 0.0)
+}
+
+func TestJoins(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		left, right rune
+		want        bool
+	}{
+		{'─', '─', true},
+		{'┌', '┐', true},
+		{'│', '│', true},
+		{'─', '│', false},
+		{'│', '─', false},
+		{'┐', '┌', true},
+		{'┐', '─', false},
+		{'a', '─', true},
+		{'░', '│', true},
+	}
+	for _, tt := range tests {
+		be.Equal(t, detect.Joins(tt.left, tt.right), tt.want)
+	}
+}
@@ -38,6 +38,11 @@ The commands are:
 	tables      Display the characters of every code page table in use
 	info        Information on a text file
 	view        Print a text file to the terminal using standard output
+	export      Save text files and art as HTML documents
+	render      Save text files and art as PNG images
+	convert     Convert text files to UTF-8 and save them
+	encode      Encode UTF-8 text files into a legacy code page
+	sauce       Add, update or remove the SAUCE metadata of files
 	dump        Dump the hex data of files to the terminal
 	example     List the included sample text files available for use with the info and view commands
 
@@ -59,6 +64,34 @@ To display a text file to the terminal by supplying the source encoding:
 
 	retrotxt view [filenames] --input iso-8859-1
 
+To read a long text file or ANSI art in a full-screen pager:
+
+	retrotxt view [filenames] --pager
+
+To display a text file using the color codes of a BBS dialect:
+
+	retrotxt view [filenames] --bbs pcboard
+
+To save a text file or ANSI art as a HTML document:
+
+	retrotxt export [filenames]
+
+To save a text file or ANSI art as a PNG image:
+
+	retrotxt render [filenames]
+
+To convert a legacy text file to a UTF-8 text file:
+
+	retrotxt convert [filenames]
+
+To encode a UTF-8 text file back into a legacy code page:
+
+	retrotxt encode [filenames] --output cp437
+
+To add or update the SAUCE metadata of a file:
+
+	retrotxt sauce set [filenames] --title "My art" --author me
+
 To list the sample text files:
 
 	retrotxt example
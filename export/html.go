@@ -0,0 +1,208 @@
+// Package export writes text and text art to standalone documents for use on the web.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/meta"
+)
+
+var ErrTmpl = errors.New("could not execute the html template")
+
+// Meta is the document metadata, these are usually taken from the SAUCE record.
+type Meta struct {
+	Title   string // Title of the document.
+	Author  string // Author is the nickname or handle of the creator.
+	Group   string // Group is the group or company of the author.
+	Comment string // Comment is the description of the document.
+	ICE     bool   // ICE uses the blink attribute for bright background colors.
+}
+
+// HTML writes the rows of cells as a self-contained HTML document.
+// The display attributes of the cells are written as CSS classes,
+// except for the 256 and 24-bit colors which are written as inline styles.
+func HTML(w io.Writer, m Meta, rows ...[]ansi.Cell) error {
+	if w == nil {
+		w = io.Discard
+	}
+	t, err := template.New("html").Parse(htmlTmpl)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrTmpl, err)
+	}
+	data := struct {
+		Meta
+		Generator string
+		Style     template.CSS
+		Pre       template.HTML
+	}{
+		Meta:      m,
+		Generator: meta.Name,
+		Style:     template.CSS(Style()),              //nolint:gosec
+		Pre:       template.HTML(Pre(m.ICE, rows...)), //nolint:gosec
+	}
+	if err := t.Execute(w, data); err != nil {
+		return fmt.Errorf("%w: %w", ErrTmpl, err)
+	}
+	return nil
+}
+
+const htmlTmpl = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="generator" content="{{.Generator}}">
+{{- if .Author}}
+<meta name="author" content="{{.Author}}">{{end}}
+{{- if .Group}}
+<meta name="group" content="{{.Group}}">{{end}}
+{{- if .Comment}}
+<meta name="description" content="{{.Comment}}">{{end}}
+<title>{{.Title}}</title>
+<style>
+{{.Style}}</style>
+</head>
+<body>
+<pre class="ansi">{{.Pre}}</pre>
+</body>
+</html>
+`
+
+// Style returns the CSS stylesheet of the VGA palette and the display attribute classes.
+func Style() string {
+	sb := &strings.Builder{}
+	p := ansi.VGA()
+	fmt.Fprintf(sb, "body { background-color: %s; }\n", hexColor(p[0]))
+	fmt.Fprintf(sb, "pre.ansi { display: inline-block; margin: 0; color: %s; background-color: %s;"+
+		" font-family: monospace; line-height: 1; }\n", hexColor(p[7]), hexColor(p[0]))
+	for i, c := range p {
+		fmt.Fprintf(sb, ".f%d { color: %s; }\n", i, hexColor(c))
+	}
+	for i, c := range p {
+		fmt.Fprintf(sb, ".b%d { background-color: %s; }\n", i, hexColor(c))
+	}
+	sb.WriteString(".bo { font-weight: bold; }\n")
+	sb.WriteString(".fa { opacity: 0.5; }\n")
+	sb.WriteString(".it { font-style: italic; }\n")
+	sb.WriteString(".un { text-decoration: underline; }\n")
+	sb.WriteString(".st { text-decoration: line-through; }\n")
+	sb.WriteString(".un.st { text-decoration: underline line-through; }\n")
+	sb.WriteString(".co { visibility: hidden; }\n")
+	sb.WriteString(".bl { animation: blink 1s step-end infinite; }\n")
+	sb.WriteString("@keyframes blink { 50% { color: transparent; } }\n")
+	return sb.String()
+}
+
+// Pre returns the rows of cells as HTML escaped text that uses span elements for the display attributes.
+// Trailing blank cells on each row are removed.
+func Pre(ice bool, rows ...[]ansi.Cell) string {
+	sb := &strings.Builder{}
+	for y, row := range rows {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		last := len(row) - 1
+		for last >= 0 && row[last].Blank() {
+			last--
+		}
+		open := ""
+		for _, c := range row[:last+1] {
+			if tag := span(ice, c.Attr); tag != open {
+				if open != "" {
+					sb.WriteString("</span>")
+				}
+				sb.WriteString(tag)
+				open = tag
+			}
+			r := c.Rune
+			if r == 0 {
+				r = ' '
+			}
+			sb.WriteString(html.EscapeString(string(r)))
+		}
+		if open != "" {
+			sb.WriteString("</span>")
+		}
+	}
+	return sb.String()
+}
+
+// span returns the opening span element for the display attributes.
+// An empty string is returned for the default attributes.
+func span(ice bool, a ansi.Attr) string {
+	const lightGray, black = 7, 0
+	fg, bg := a.Colors(ice)
+	classes, styles := []string{}, []string{}
+	if c, style := colorClass("f", "color", fg); style != "" {
+		styles = append(styles, style)
+	} else if c != "" && fg != ansi.Index(lightGray) {
+		classes = append(classes, c)
+	}
+	if c, style := colorClass("b", "background-color", bg); style != "" {
+		styles = append(styles, style)
+	} else if c != "" && bg != ansi.Index(black) {
+		classes = append(classes, c)
+	}
+	flags := []struct {
+		f     ansi.Flags
+		class string
+	}{
+		{ansi.Faint, "fa"}, {ansi.Italic, "it"}, {ansi.Underline, "un"},
+		{ansi.Strike, "st"}, {ansi.Conceal, "co"},
+	}
+	for _, x := range flags {
+		if a.Has(x.f) {
+			classes = append(classes, x.class)
+		}
+	}
+	// bold only brightens the default and the eight standard colors
+	const classic = 8
+	brightened := a.FG.Mode == ansi.Default || (a.FG.Mode == ansi.Indexed && a.FG.Index < classic)
+	if a.Has(ansi.Bold) && !brightened {
+		classes = append(classes, "bo")
+	}
+	if a.Has(ansi.Blink) && !ice {
+		classes = append(classes, "bl")
+	}
+	if len(classes) == 0 && len(styles) == 0 {
+		return ""
+	}
+	s := "<span"
+	if len(classes) > 0 {
+		s += ` class="` + strings.Join(classes, " ") + `"`
+	}
+	if len(styles) > 0 {
+		s += ` style="` + strings.Join(styles, " ") + `"`
+	}
+	return s + ">"
+}
+
+// colorClass returns either the CSS class name of a palette color,
+// or the inline CSS style of any other color.
+func colorClass(prefix, property string, c ansi.Color) (string, string) {
+	switch c.Mode {
+	case ansi.Indexed:
+		if int(c.Index) < len(ansi.Palette{}) {
+			return prefix + strconv.Itoa(int(c.Index)), ""
+		}
+		return "", property + ": " + hexColor(ansi.Xterm(c.Index)) + ";"
+	case ansi.RGB:
+		rgba, _ := ansi.VGA().RGBA(c)
+		return "", property + ": " + hexColor(rgba) + ";"
+	case ansi.Default:
+	}
+	return "", ""
+}
+
+// hexColor returns the color as a CSS hexadecimal value.
+func hexColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,70 @@
+package export_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/export"
+	"github.com/nalgeon/be"
+)
+
+func rows(s string) [][]ansi.Cell {
+	scr := ansi.New(0)
+	scr.Runes([]rune(s)...)
+	return scr.Rows()
+}
+
+func ExamplePre() {
+	fmt.Println(export.Pre(false, rows("\x1b[1;31mHi\x1b[0m <you>")...))
+	// Output: <span class="f9">Hi</span> &lt;you&gt;
+}
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+	sb := &strings.Builder{}
+	m := export.Meta{Title: "A <title>", Author: "Ben", Group: "RetroTxt", Comment: "a comment"}
+	err := export.HTML(sb, m, rows("hello")...)
+	be.Err(t, err, nil)
+	s := sb.String()
+	be.True(t, strings.HasPrefix(s, "<!DOCTYPE html>"))
+	be.True(t, strings.Contains(s, "<title>A &lt;title&gt;</title>"))
+	be.True(t, strings.Contains(s, `<meta name="author" content="Ben">`))
+	be.True(t, strings.Contains(s, `<meta name="group" content="RetroTxt">`))
+	be.True(t, strings.Contains(s, `<meta name="description" content="a comment">`))
+	be.True(t, strings.Contains(s, `<pre class="ansi">hello</pre>`))
+	be.True(t, strings.Contains(s, ".f15 { color: #ffffff; }"))
+	sb.Reset()
+	err = export.HTML(sb, export.Meta{}, nil...)
+	be.Err(t, err, nil)
+	be.True(t, !strings.Contains(sb.String(), `name="author"`))
+}
+
+func TestPre(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		ice  bool
+		s    string
+		want string
+	}{
+		{"empty", false, "", ""},
+		{"text", false, "a\r\nb", "a\nb"},
+		{"colors", false, "\x1b[32;44mx", `<span class="f2 b4">x</span>`},
+		{"default", false, "\x1b[37;40mx", `x`},
+		{"blink", false, "\x1b[5;41mx", `<span class="b1 bl">x</span>`},
+		{"ice", true, "\x1b[5;41mx", `<span class="b9">x</span>`},
+		{"inverse", false, "\x1b[7mx", `<span class="f0 b7">x</span>`},
+		{"xterm", false, "\x1b[38;5;196mx", `<span style="color: #ff0000;">x</span>`},
+		{"rgb bold", false, "\x1b[1;48;2;1;2;3mx", `<span class="f15" style="background-color: #010203;">x</span>`},
+		{"rgb fg bold", false, "\x1b[1;38;2;1;2;3mx", `<span class="bo" style="color: #010203;">x</span>`},
+		{"trailing", false, "x\x1b[41m  \x1b[0m  ", `x<span class="b1">  </span>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, export.Pre(tt.ice, rows(tt.s)...), tt.want)
+		})
+	}
+}
@@ -13,6 +13,10 @@ import (
 	"github.com/bengarrett/retrotxtgo/nl"
 )
 
+// StreamSize is the size in bytes of the files and piped data that are
+// read and converted in chunks, instead of being read into memory all at once.
+const StreamSize = 16 * 1024 * 1024
+
 // IsPipe reports whether Stdin (standard input) is piped from another command.
 func IsPipe() (bool, error) {
 	// source: https://dev.to/napicella/linux-pipes-in-golang-2e8j
@@ -23,6 +27,12 @@ func IsPipe() (bool, error) {
 	return fi.Mode()&os.ModeCharDevice == 0, nil
 }
 
+// Large reports whether the named file is larger than the StreamSize.
+func Large(name string) bool {
+	st, err := os.Stat(name)
+	return err == nil && st.Mode().IsRegular() && st.Size() > StreamSize
+}
+
 // Read opens and returns the content of the named file.
 func Read(name string) ([]byte, error) {
 	return ReadAllBytes(name)
@@ -85,7 +95,7 @@ func ReadChunk(name string, chars int) ([]byte, error) {
 	return buf, nil
 }
 
-// ReadColumns counts the number of characters used per line in the named file.
+// ReadColumns returns the display width of the widest line in the named file.
 func ReadColumns(name string) (int, error) {
 	return readLineBreaks(name, true)
 }
@@ -175,7 +185,8 @@ func ReadLineBreaks(name string) ([2]rune, error) {
 		return z, fmt.Errorf("fsys read line breaks: %w", err)
 	}
 	defer file.Close()
-	b, err := io.ReadAll(file)
+	// large files only use the start of the file to guess the line break
+	b, err := io.ReadAll(io.LimitReader(file, StreamSize))
 	if err != nil {
 		return z, fmt.Errorf("read line breaks could not read the file: %q: %w", name, err)
 	}
@@ -185,8 +196,27 @@ func ReadLineBreaks(name string) ([2]rune, error) {
 // ReadPipe reads data piped by the operating system's STDIN.
 // If no data is detected the program will exit.
 func ReadPipe() ([]byte, error) {
+	return readPipe(os.Stdin)
+}
+
+// ReadLargePipe reads data piped by the operating system's STDIN, the same as ReadPipe.
+// But when the data is larger than the StreamSize, only the start of the data is returned
+// together with a reader that streams all of the data.
+func ReadLargePipe() ([]byte, io.Reader, error) {
+	head, err := io.ReadAll(io.LimitReader(os.Stdin, StreamSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("read pipe could not read stdin: %w", err)
+	}
+	if len(head) > StreamSize {
+		return head, io.MultiReader(bytes.NewReader(head), os.Stdin), nil
+	}
+	b, err := readPipe(bytes.NewReader(head))
+	return b, nil, err
+}
+
+func readPipe(r io.Reader) ([]byte, error) {
 	b := []byte{}
-	scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
 		b = append(b, scanner.Bytes()...)
 		b = append(b, []byte("\n")...)
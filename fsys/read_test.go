@@ -3,6 +3,7 @@ package fsys_test
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/bengarrett/retrotxtgo/fsys"
@@ -39,7 +40,7 @@ func TestReadColumns(t *testing.T) {
 		{"", -1, true},
 		{tmp0, 11, false},
 		{tmp1, 5, false},
-		{tmp2, 18, false},
+		{tmp2, 12, false},
 		{tmp3, 5, false},
 		{tmp4, 5, false},
 		{tmp5, 0, false},
@@ -280,3 +281,30 @@ func TestReadPipe(t *testing.T) { //nolint:paralleltest
 		}
 	})
 }
+
+func TestReadLargePipe(t *testing.T) { //nolint:paralleltest
+	// do not run in parallel as it uses os.Stdin
+	r, err := mock.Input("hello\r\nworld")
+	be.Err(t, err, nil)
+	stdin := os.Stdin
+	defer func() {
+		os.Stdin = stdin
+	}()
+	os.Stdin = r
+	b, large, err := fsys.ReadLargePipe()
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "hello\nworld\n")
+	be.True(t, large == nil)
+}
+
+func TestLarge(t *testing.T) {
+	t.Parallel()
+	be.True(t, !fsys.Large(""))
+	be.True(t, !fsys.Large(os.TempDir()))
+	name := filepath.Join(t.TempDir(), "large.txt")
+	f, err := os.Create(name)
+	be.Err(t, err, nil)
+	be.Err(t, f.Truncate(fsys.StreamSize+1), nil)
+	be.Err(t, f.Close(), nil)
+	be.True(t, fsys.Large(name))
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"math"
 	"reflect"
 	"runtime"
 	"sort"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/bengarrett/retrotxtgo/byter"
 	"github.com/bengarrett/retrotxtgo/nl"
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/transform"
 )
@@ -50,7 +52,7 @@ func NEL() [2]rune {
 	return [2]rune{nl.NEL}
 }
 
-// Columns counts the number of characters used per line in the reader interface.
+// Columns returns the display width of the widest line in the reader interface.
 func Columns(r io.Reader, lb [2]rune) (int, error) {
 	if r == nil {
 		return 0, ErrReader
@@ -59,31 +61,45 @@ func Columns(r io.Reader, lb [2]rune) (int, error) {
 		return 0, ErrLB
 	}
 	sep := byter.LineBreak(lb)
-	p, width := make([]byte, bufio.MaxScanTokenSize), 0
-	for {
-		size, err := r.Read(p)
-		if err != nil && err != io.EOF {
-			return -1, fmt.Errorf("columns could not read buffer: %w", err)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, bufio.MaxScanTokenSize), math.MaxInt32)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		if i := bytes.Index(data, sep); i >= 0 {
+			return i + len(sep), data[:i], nil
 		}
-		pos := 0
-		for pos < size {
-			i := bytes.Index(p[pos:], sep)
-			if i == -1 {
-				width = size
-				break
-			}
-			pos += i + len(sep)
-			if i > width {
-				width = i
-			}
-		}
-		if err == io.EOF {
-			break
+		if atEOF && len(data) > 0 {
+			return len(data), data, nil
 		}
+		return 0, nil, nil
+	})
+	width := 0
+	for scanner.Scan() {
+		width = max(width, DisplayWidth(scanner.Bytes()...))
+	}
+	if err := scanner.Err(); err != nil {
+		return -1, fmt.Errorf("columns could not read buffer: %w", err)
 	}
 	return width, nil
 }
 
+// DisplayWidth returns the number of terminal columns used to display a line of text.
+// The UTF-8 text uses the display width of each character, where East Asian wide characters
+// use two columns and the combining marks none. Otherwise, the legacy 8-bit and Shift-JIS
+// double-byte encodings use a column per byte. The ANSI escape sequences are not counted.
+func DisplayWidth(b ...byte) int {
+	if utf8.Valid(b) {
+		return reflow.Width([]rune(string(b))...)
+	}
+	r := make([]rune, len(b))
+	for i, c := range b {
+		r[i] = rune(c)
+		if c >= utf8.RuneSelf {
+			r[i] = utf8.RuneError
+		}
+	}
+	return reflow.Width(r...)
+}
+
 // Controls counts the number of ANSI escape controls in the reader interface.
 func Controls(r io.Reader) (int, error) {
 	if r == nil {
@@ -90,7 +90,13 @@ func TestColumns(t *testing.T) {
 	}{
 		{"empty", args{}, 0, true},
 		{"4 chars", args{strings.NewReader("abcd\n"), fsys.LF()}, 4, false},
-		{"4 runes", args{bytes.NewReader([]byte("😁😋😃🤫\n")), fsys.LF()}, 16, false},
+		{"4 wide runes", args{bytes.NewReader([]byte("😁😋😃🤫\n")), fsys.LF()}, 8, false},
+		{"full-width", args{strings.NewReader("ab\nＡＢＣ\n"), fsys.LF()}, 6, false},
+		{"combining", args{strings.NewReader("cafe\u0301\n"), fsys.LF()}, 4, false},
+		{"ansi", args{strings.NewReader("\x1b[1;31mabc\x1b[0m\n"), fsys.LF()}, 3, false},
+		{"shift-jis", args{bytes.NewReader([]byte{0x82, 0xa0, 0x82, 0xa2, 'a', '\n'}), fsys.LF()}, 5, false},
+		{"no line break", args{strings.NewReader("ab\nabcdef"), fsys.LF()}, 6, false},
+		{"crlf", args{strings.NewReader("abc\r\nab\r\n"), fsys.CRLF()}, 3, false},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
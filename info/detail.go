@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"hash/crc32"
 	"hash/crc64"
@@ -21,8 +22,13 @@ import (
 	"unicode/utf8"
 
 	"github.com/bengarrett/bbs"
+	"github.com/bengarrett/retrotxtgo/avatar"
+	"github.com/bengarrett/retrotxtgo/bintext"
+	"github.com/bengarrett/retrotxtgo/detect"
 	"github.com/bengarrett/retrotxtgo/fsys"
+	"github.com/bengarrett/retrotxtgo/music"
 	"github.com/bengarrett/retrotxtgo/nl"
+	"github.com/bengarrett/retrotxtgo/videotex"
 	"github.com/bengarrett/sauce"
 	"github.com/bengarrett/sauce/humanize"
 	"github.com/charmbracelet/lipgloss"
@@ -34,22 +40,23 @@ import (
 
 // Detail is the exported file details.
 type Detail struct {
-	XMLName    xml.Name     `json:"-"          xml:"file"`
-	Name       string       `json:"filename"   xml:"name"`          // Name is the file name.
-	Unicode    string       `json:"unicode"    xml:"unicode,attr"`  // Unicode is the file encoding if in Unicode.
-	LineBreak  nl.LineBreak `json:"lineBreak"  xml:"line_break"`    // LineBreak is the line break used in the file.
-	Count      Stats        `json:"counts"     xml:"counts"`        // Count is the file content statistics.
-	Size       Sizes        `json:"size"       xml:"size"`          // Size is the file size in multiples.
-	Lines      int          `json:"lines"      xml:"lines"`         // Lines is the number of lines in the file.
-	Width      int          `json:"width"      xml:"width"`         // Width is the number of characters per line in the file, this may be inaccurate.
-	Modified   ModDates     `json:"modified"   xml:"last_modified"` // Modified is the last modified date of the file.
-	Sums       Checksums    `json:"checksums"  xml:"checksums"`     // Sums are the checksums of the file.
-	Mime       Content      `json:"mime"       xml:"mime"`          // Mime is the file content metadata.
-	Slug       string       `json:"slug"       xml:"id,attr"`       // Slug is the file name slugified.
-	Sauce      sauce.Record `json:"sauce"      xml:"sauce"`         // Sauce is the SAUCE metadata.
-	ZipComment string       `json:"zipComment" xml:"zip_comment"`   // ZipComment is the zip file comment.
-	UTF8       bool         `json:"-"          xml:"-"`             // UTF8 is true if the file is UTF-8 encoded.
-	LegacySums bool         `json:"-"          xml:"-"`             // LegacySums is true if the user requests legacy checksums.
+	XMLName    xml.Name     `json:"-"              xml:"file"`
+	Name       string       `json:"filename"       xml:"name"`            // Name is the file name.
+	Unicode    string       `json:"unicode"        xml:"unicode,attr"`    // Unicode is the file encoding if in Unicode.
+	Likely     string       `json:"likelyEncoding" xml:"likely_encoding"` // Likely is the detected character encoding and its confidence.
+	LineBreak  nl.LineBreak `json:"lineBreak"      xml:"line_break"`      // LineBreak is the line break used in the file.
+	Count      Stats        `json:"counts"         xml:"counts"`          // Count is the file content statistics.
+	Size       Sizes        `json:"size"           xml:"size"`            // Size is the file size in multiples.
+	Lines      int          `json:"lines"          xml:"lines"`           // Lines is the number of lines in the file.
+	Width      int          `json:"width"          xml:"width"`           // Width is the display width of the widest line in the file, this may be inaccurate.
+	Modified   ModDates     `json:"modified"       xml:"last_modified"`   // Modified is the last modified date of the file.
+	Sums       Checksums    `json:"checksums"      xml:"checksums"`       // Sums are the checksums of the file.
+	Mime       Content      `json:"mime"           xml:"mime"`            // Mime is the file content metadata.
+	Slug       string       `json:"slug"           xml:"id,attr"`         // Slug is the file name slugified.
+	Sauce      sauce.Record `json:"sauce"          xml:"sauce"`           // Sauce is the SAUCE metadata.
+	ZipComment string       `json:"zipComment"     xml:"zip_comment"`     // ZipComment is the zip file comment.
+	UTF8       bool         `json:"-"              xml:"-"`               // UTF8 is true if the file is UTF-8 encoded.
+	LegacySums bool         `json:"-"              xml:"-"`               // LegacySums is true if the user requests legacy checksums.
 	sauceIndex int          // sauceIndex is the index of the SAUCE record in the file.
 }
 
@@ -84,9 +91,11 @@ type Sizes struct {
 
 // Stats are the text file content statistics and counts.
 type Stats struct {
-	Chars    int `json:"characters"   xml:"characters"`    // Chars is the number of characters in the file.
-	Controls int `json:"ansiControls" xml:"ansi_controls"` // Controls is the number of ANSI escape controls in the file.
-	Words    int `json:"words"        xml:"words"`         // Words is the number of words in the file, this may be inaccurate.
+	Chars    int `json:"characters"     xml:"characters"`      // Chars is the number of characters in the file.
+	Controls int `json:"ansiControls"   xml:"ansi_controls"`   // Controls is the number of ANSI escape controls in the file.
+	Avatar   int `json:"avatarControls" xml:"avatar_controls"` // Avatar is the number of Avatar controls in the file.
+	Music    int `json:"musicBlocks"    xml:"music_blocks"`    // Music is the number of ANSI music sequences in the file.
+	Words    int `json:"words"          xml:"words"`           // Words is the number of words in the file, this may be inaccurate.
 }
 
 // Format of the output text.
@@ -103,11 +112,14 @@ const (
 const (
 	uc8         = "UTF-8"
 	ans         = "ANSI controls"
+	avt         = "Avatar controls"
+	mus         = "ANSI music blocks"
 	chars       = "characters"
 	cmmt        = "comment"
 	c32         = "CRC32"
 	c64ecma     = "CRC64 ECMA"
 	desc        = "description"
+	likely      = "likely encoding"
 	linebr      = "line break"
 	lines       = "lines"
 	interp      = "interpretation"
@@ -196,6 +208,10 @@ func (d *Detail) MimeUnknown() {
 	if d.Mime.Commt != "unknown" {
 		return
 	}
+	if d.Count.Avatar > 0 {
+		d.Mime.Commt = "Text document with Avatar controls"
+		return
+	}
 	if d.Count.Controls > 0 {
 		d.Mime.Commt = "Text document with ANSI controls"
 		return
@@ -215,7 +231,7 @@ func (d *Detail) MimeUnknown() {
 
 // Parse the file and the raw data content.
 func (d *Detail) Parse(name string, data ...byte) error {
-	routines := 5
+	routines := 6
 	if d.LegacySums {
 		routines += 3
 	}
@@ -264,6 +280,12 @@ func (d *Detail) Parse(name string, data ...byte) error {
 		d.UTF8 = utf8.Valid(data)
 		d.Unicode = unicode(d.UTF8, data...)
 	}()
+	go func() {
+		defer wg.Done()
+		if g := detect.Encodings(data...); len(g) > 0 {
+			d.Likely = g[0].String()
+		}
+	}()
 	wg.Wait()
 	return nil
 }
@@ -303,8 +325,50 @@ func sauceDate(s string) string {
 	return humanize.DMY.Format(t.UTC())
 }
 
+// ICE reports whether the SAUCE metadata requests the non-blink mode,
+// where the blink attribute selects the bright background colors, otherwise known as iCE colors.
+func (d *Detail) ICE() bool {
+	const nonBlink = 1
+	return uint8(d.Sauce.Info.Flags.Decimal)&nonBlink == nonBlink
+}
+
+// Columns returns the SAUCE character width of a text file, or zero when the width is not set.
+func (d *Detail) Columns() int {
+	const character = 1
+	if uint8(d.Sauce.Data.Type) != character {
+		return 0
+	}
+	return int(d.Sauce.Info.Info1.Value)
+}
+
+// LetterSpacing returns the SAUCE font letter-spacing in pixels, either 8 or 9,
+// or zero when the letter-spacing is not set.
+func (d *Detail) LetterSpacing() int {
+	const mask, shift = 0b0110, 1
+	const eight, nine = 1, 2
+	switch uint8(d.Sauce.Info.Flags.Decimal) & mask >> shift {
+	case eight:
+		return 8 //nolint:mnd
+	case nine:
+		return 9 //nolint:mnd
+	}
+	return 0
+}
+
+// LegacyAspect reports whether the SAUCE metadata requests the image be stretched
+// to the display aspect ratio of a legacy CRT monitor, instead of using square pixels.
+func (d *Detail) LegacyAspect() bool {
+	const mask, shift = 0b11000, 3
+	const legacy = 1
+	return uint8(d.Sauce.Info.Flags.Decimal)&mask>>shift == legacy
+}
+
 // Read and parse the named file and content.
+// Files larger than the fsys.StreamSize are parsed without reading them into memory.
 func (d *Detail) Read(name string) error {
+	if fsys.Large(name) {
+		return d.readLarge(name)
+	}
 	// Read file content
 	p, err := fsys.ReadAllBytes(name)
 	if err != nil {
@@ -313,6 +377,129 @@ func (d *Detail) Read(name string) error {
 	return d.Parse(name, p...)
 }
 
+// readLarge parses the named large file in chunks.
+// The MIME type, BBS color codes and likely encoding are found using the start of the file,
+// while the SAUCE metadata is read from the end of the file.
+func (d *Detail) readLarge(name string) error {
+	const n = "info detail read large"
+	f, err := os.Open(name)
+	if err != nil {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	const headSize = 64 * 1024
+	head := make([]byte, headSize)
+	i, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	head = head[:i]
+	// the sauce record and the maximum number of comment lines
+	const tailSize = 128 + 5 + 255*64
+	offset := max(0, stat.Size()-tailSize)
+	tail := make([]byte, stat.Size()-offset)
+	if _, err := f.ReadAt(tail, offset); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	if i := sauce.Index(tail); i >= 0 {
+		d.sauceIndex = int(offset) + i
+		if d.sauceIndex > 0 {
+			d.Sauce = sauce.Decode(tail)
+		}
+	}
+	d.mime(name, head...)
+	d.input(0, stat)
+	if g := detect.Encodings(head...); len(g) > 0 {
+		d.Likely = g[0].String()
+	}
+	// the checksums, runes, UTF-8 validation and the Avatar and music counts use all of the file
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	sha := sha256.New()
+	crc32sum, crc64sum, md5sum := crc32.NewIEEE(), crc64.New(crc64.MakeTable(crc64.ECMA)), md5.New()
+	u := utf8Writer{valid: true}
+	avatars, tunes := avatar.Counter{}, music.Counter{}
+	writers := []io.Writer{sha, &u, &avatars, &tunes}
+	if d.LegacySums {
+		writers = append(writers, crc32sum, crc64sum, md5sum)
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return fmt.Errorf("%s: %w", n, err)
+	}
+	u.flush()
+	d.Sums.SHA256 = hex.EncodeToString(sha.Sum(nil))
+	if d.LegacySums {
+		d.Sums.CRC32 = strconv.FormatUint(uint64(crc32sum.Sum32()), 16)
+		d.Sums.CRC64 = strconv.FormatUint(crc64sum.Sum64(), 16)
+		d.Sums.MD5 = hex.EncodeToString(md5sum.Sum(nil))
+	}
+	d.UTF8 = u.valid
+	d.Unicode = unicode(d.UTF8, head...)
+	if ValidText(d.Mime.Type) {
+		d.Count.Chars = u.runes
+		d.Count.Avatar = avatars.Count()
+		d.Count.Music = tunes.Count()
+	}
+	return nil
+}
+
+// utf8Writer counts the runes written to it and reports whether they are valid UTF-8.
+type utf8Writer struct {
+	carry []byte // carry is an incomplete rune split between writes.
+	runes int
+	valid bool
+}
+
+func (u *utf8Writer) Write(p []byte) (int, error) {
+	n := len(p)
+	if len(u.carry) > 0 {
+		// decode the carried runes with the start of p
+		b := append(u.carry, p[:min(len(p), utf8.UTFMax)]...)
+		i := 0
+		for i < len(u.carry) {
+			if !utf8.FullRune(b[i:]) {
+				u.carry = append(u.carry[:0:0], b[i:]...)
+				return n, nil
+			}
+			r, size := utf8.DecodeRune(b[i:])
+			u.runes++
+			u.valid = u.valid && (r != utf8.RuneError || size > 1)
+			i += size
+		}
+		p = p[i-len(u.carry):]
+		u.carry = u.carry[:0]
+	}
+	full := len(p)
+	for i := len(p) - 1; i >= 0 && i >= len(p)-utf8.UTFMax; i-- {
+		if !utf8.RuneStart(p[i]) {
+			continue
+		}
+		if !utf8.FullRune(p[i:]) {
+			full = i
+		}
+		break
+	}
+	u.count(p[:full]...)
+	u.carry = append(u.carry, p[full:]...)
+	return n, nil
+}
+
+// flush counts any incomplete rune at the end of the writes.
+func (u *utf8Writer) flush() {
+	u.count(u.carry...)
+	u.carry = nil
+}
+
+func (u *utf8Writer) count(b ...byte) {
+	u.runes += utf8.RuneCount(b)
+	u.valid = u.valid && utf8.Valid(b)
+}
+
 // ValidText reports whether the MIME content-type value is valid for text files.
 func ValidText(mime string) bool {
 	s := strings.Split(mime, "/")
@@ -479,9 +666,9 @@ func (d *Detail) marshalAsTree(w io.Writer, useColors bool) { //nolint:cyclop,fu
 		}
 
 		switch x.k {
-		case "slug", "filename", "filetype", "Unicode", linebr:
+		case "slug", "filename", "filetype", "Unicode", likely, linebr:
 			basicInfo = append(basicInfo, x)
-		case chars, words, "size", lines, width, ans:
+		case chars, words, "size", lines, width, ans, avt, mus:
 			contentStats = append(contentStats, x)
 		case "modified", "media mime type":
 			fileMeta = append(fileMeta, x)
@@ -567,7 +754,7 @@ func (d *Detail) marshalled() []struct{ k, v string } {
 		noBreakSpace     = "\u00A0"
 		symbolForNewline = "\u2424"
 		// baseFieldCount represents the number of base fields in the detail structure
-		baseFieldCount = 30
+		baseFieldCount = 32
 	)
 	p := message.NewPrinter(lang())
 	// Preallocate slice with capacity for all fields plus potential SAUCE comments
@@ -579,9 +766,12 @@ func (d *Detail) marshalled() []struct{ k, v string } {
 		struct{ k, v string }{k: "filename", v: d.Name},
 		struct{ k, v string }{k: "filetype", v: d.Mime.Commt},
 		struct{ k, v string }{k: "Unicode", v: d.Unicode},
+		struct{ k, v string }{k: likely, v: d.Likely},
 		struct{ k, v string }{k: linebr, v: fsys.LineBreak(d.LineBreak.Decimal, true)},
 		struct{ k, v string }{k: chars, v: p.Sprint(d.Count.Chars)},
 		struct{ k, v string }{k: ans, v: p.Sprint(d.Count.Controls)},
+		struct{ k, v string }{k: avt, v: p.Sprint(d.Count.Avatar)},
+		struct{ k, v string }{k: mus, v: p.Sprint(d.Count.Music)},
 		struct{ k, v string }{k: words, v: p.Sprint(d.Count.Words)},
 		struct{ k, v string }{k: "size", v: d.Size.Decimal},
 		struct{ k, v string }{k: lines, v: p.Sprint(d.Lines)},
@@ -628,18 +818,29 @@ func (d *Detail) mime(name string, data ...byte) {
 	d.Mime.Sub = mm.Subtype
 	d.Mime.Type = fmt.Sprintf("%s/%s", mm.Media, mm.Subtype)
 	d.Mime.Commt = mm.Comment
+	codes := false
 	if d.Mime.Commt == "plain text document" {
 		reader := bytes.NewReader(data)
 		if s := bbs.Find(reader).Name(); s != "" {
 			d.Mime.Commt += fmt.Sprintf(" with %s BBS color codes", s)
+			codes = true
 		}
 	}
+	// the BBS color codes comment is kept, as a text with color codes is not a teletext page
+	if f := videotex.Detect(name, data...); f != videotex.Unknown && !codes {
+		d.Mime.Commt = f.String()
+	}
+	if f := bintext.Detect(name, data...); f != bintext.Unknown {
+		d.Mime.Commt = f.String()
+	}
 	if ValidText(d.Mime.Type) {
 		var err error
 		b := bytes.NewBuffer(data)
 		if d.Count.Chars, err = fsys.Runes(b); err != nil {
 			fmt.Fprintf(os.Stdout, "mine sniffer failure, %s\n", err)
 		}
+		d.Count.Avatar = avatar.Count(data...)
+		d.Count.Music = music.Count(data...)
 		return
 	}
 	if d.Mime.Type == zipType {
@@ -670,13 +871,21 @@ func (d *Detail) skip(x struct{ k, v string }) bool {
 func (d *Detail) validate(x struct{ k, v string }) bool {
 	if !ValidText(d.Mime.Type) {
 		switch x.k {
-		case uc8, linebr, chars, ans, words, lines, width:
+		case uc8, likely, linebr, chars, ans, avt, mus, words, lines, width:
 			return false
 		}
 	} else if x.k == ans {
 		if d.Count.Controls == 0 {
 			return false
 		}
+	} else if x.k == avt {
+		if d.Count.Avatar == 0 {
+			return false
+		}
+	} else if x.k == mus {
+		if d.Count.Music == 0 {
+			return false
+		}
 	}
 	if x.k == desc && x.v == "" {
 		return false
@@ -5,10 +5,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
 
+	"github.com/bengarrett/retrotxtgo/fsys"
 	"github.com/bengarrett/retrotxtgo/info"
 	"github.com/bengarrett/retrotxtgo/internal/mock"
 )
@@ -27,7 +29,7 @@ func ExampleDetail_Marshal() {
 	b := bytes.Buffer{}
 	_ = d.Marshal(&b, info.JSON)
 	fmt.Printf("%d bytes, is json = %t", b.Len(), json.Valid(b.Bytes()))
-	// Output: 2130 bytes, is json = true
+	// Output: 2211 bytes, is json = true
 }
 
 func TestValidText(t *testing.T) {
@@ -117,6 +119,60 @@ func TestParse(t *testing.T) {
 	})
 }
 
+func TestParse_format(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"page.tti", "PN,10000\r\nOL,1,Hello\r\n", "Teletext TTI page"},
+		{"page.vdt", "\x0c\x1f\x41\x41Bonjour", "Minitel videotex stream"},
+		{"frame", "\x0c\x1bAred\x1bBgreen\x1bCyellow", "Prestel viewdata frame"},
+		{"text", "hello", "plain text document"},
+		{"art.xb", "XBIN\x1a\x01\x00\x01\x00\x10\x00A\x07", "XBin text art"},
+		{"art.bin", "A\x07B\x07", "BinaryText art"},
+		{"raw", strings.Repeat("\x01"+strings.Repeat("a", 39), 25), "Teletext page"},
+		{"readme", strings.Repeat("\x80"+strings.Repeat("a", 39), 25)[:999] + "\x1a", "plain text document"},
+		{"pcboard", strings.Repeat("\x80@X1Fhello"+strings.Repeat("a", 30), 25), "plain text document with PCBoard BBS color codes"},
+	}
+	for _, tt := range tests {
+		var d info.Detail
+		if err := d.Parse(tt.name, []byte(tt.data)...); err != nil {
+			t.Errorf("Parse() error = %v", err)
+		}
+		if d.Mime.Commt != tt.want {
+			t.Errorf("Parse() %s = %q, want %q", tt.name, d.Mime.Commt, tt.want)
+		}
+	}
+}
+
+func TestParse_avatar(t *testing.T) {
+	t.Parallel()
+	var d info.Detail
+	if err := d.Parse("", []byte("\x0c\x16\x01\x1fHello\x19!\x03\x16\x08\x02\x01world")...); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+	if d.Count.Avatar != 3 {
+		t.Errorf("Parse() avatar controls = %d, want 3", d.Count.Avatar)
+	}
+	d.MimeUnknown()
+	if want := "Text document with Avatar controls"; d.Mime.Commt != want {
+		t.Errorf("MimeUnknown() = %q, want %q", d.Mime.Commt, want)
+	}
+}
+
+func TestParse_music(t *testing.T) {
+	t.Parallel()
+	var d info.Detail
+	if err := d.Parse("", []byte("\x1b[MFT120L8cde\x0eHello\x1b[Ncde\x0e")...); err != nil {
+		t.Errorf("Parse() error = %v", err)
+	}
+	if d.Count.Music != 2 {
+		t.Errorf("Parse() music blocks = %d, want 2", d.Count.Music)
+	}
+}
+
 func TestMarshal_json(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -159,3 +215,28 @@ func TestMarshal_text(t *testing.T) {
 		t.Errorf("Marshal() text = %v, want %v", got, want)
 	}
 }
+
+func TestRead_large(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "large.txt")
+	line := "hello world \xc9\xcd\xbb ╔═╗ @X1Fcolor \x16\x01\x07avatar \x1b[MCDE\x0e\r\n"
+	b := []byte(strings.Repeat(line, fsys.StreamSize/len(line)+1))
+	b = append(b, "\x1aSAUCE00"...)
+	if err := os.WriteFile(name, b, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := info.Detail{LegacySums: true}
+	if err := want.Parse(name, b...); err != nil {
+		t.Fatal(err)
+	}
+	got := info.Detail{LegacySums: true}
+	if err := got.Read(name); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Read() = %+v, want %+v", got, want)
+	}
+	if n := len(b) / len(line); got.Count.Avatar != n || got.Count.Music != n {
+		t.Errorf("Read() counts = %d avatar and %d music, want %d", got.Count.Avatar, got.Count.Music, n)
+	}
+}
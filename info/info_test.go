@@ -32,7 +32,7 @@ func ExampleMarshal() {
 	s := strings.Builder{}
 	_ = info.Marshal(&s, "testdata/example.txt", true, info.JSON)
 	fmt.Printf("%d bytes and json? %t", len(s.String()), json.Valid([]byte(s.String())))
-	// Output: 2345 bytes and json? true
+	// Output: 2437 bytes and json? true
 }
 
 func ExampleStream() {
@@ -0,0 +1,259 @@
+// Package mapping provides user-defined, 8-bit character encodings
+// loaded from Unicode mapping files.
+//
+// The files are kept in the codepages sub-directory of the user's configuration directory.
+// Two file formats are supported, the Unicode.org mapping tables that use a .txt extension,
+// and the ICU character mapping tables that use a .ucm extension.
+//
+// Either format can use comments to set a formal name, aliases and a natural language.
+//
+//	# Name: CP1116 Estonian
+//	# Alias: estonian, ibm1116
+//	# Language: Estonian
+package mapping
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/meta"
+	gap "github.com/muesli/go-app-paths"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Dir is the configuration sub-directory name that contains the mapping files.
+const Dir = "codepages"
+
+var (
+	ErrDuplicate = errors.New("code page name is already in use")
+	ErrEmpty     = errors.New("mapping contains no characters")
+	ErrFormat    = errors.New("mapping line is malformed")
+	ErrMultiByte = errors.New("multi-byte mappings are not supported")
+)
+
+// Encoding is an 8-bit character encoding that implements the Encoding interface,
+// it uses a table to map each byte to a rune.
+type Encoding struct {
+	Name     string   // Name is the formal name of the character encoding.
+	Value    string   // Value is the short name of the character encoding, taken from the filename.
+	Aliases  []string // Aliases are the optional, informal names of the character encoding.
+	Language string   // Language is the optional, natural language use of the character encoding.
+
+	decode [256]rune
+	encode map[rune]byte
+}
+
+// New returns an encoding that uses the decode table to map each byte to a rune.
+// Bytes that are not mapped should use the Unicode replacement character.
+func New(name, value string, decode [256]rune) *Encoding {
+	e := &Encoding{
+		Name:   name,
+		Value:  value,
+		decode: decode,
+		encode: make(map[rune]byte, len(decode)),
+	}
+	for i, r := range decode {
+		if r == utf8.RuneError {
+			continue
+		}
+		if _, ok := e.encode[r]; !ok {
+			e.encode[r] = byte(i)
+		}
+	}
+	return e
+}
+
+// String returns the formal name of the encoding.
+func (e *Encoding) String() string {
+	return e.Name
+}
+
+// DecodeByte returns the rune of the byte,
+// or the Unicode replacement character if the byte is not mapped.
+func (e *Encoding) DecodeByte(b byte) rune {
+	return e.decode[b]
+}
+
+// EncodeRune returns the byte of the rune,
+// and reports whether the rune is mapped by the encoding.
+func (e *Encoding) EncodeRune(r rune) (byte, bool) {
+	b, ok := e.encode[r]
+	return b, ok
+}
+
+// NewDecoder returns a decoder that converts the encoded bytes into UTF-8.
+func (e *Encoding) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: decoder{e}}
+}
+
+// NewEncoder returns an encoder that converts UTF-8 text into the encoded bytes.
+// Runes that are not mapped by the encoding return an error.
+func (e *Encoding) NewEncoder() *encoding.Encoder {
+	return &encoding.Encoder{Transformer: encoder{e}}
+}
+
+// Match reports whether the name matches the formal name, the named value
+// or any of the aliases of the encoding. The comparison is case-insensitive.
+func (e *Encoding) Match(name string) bool {
+	if strings.EqualFold(name, e.Name) || strings.EqualFold(name, e.Value) {
+		return true
+	}
+	return slices.ContainsFunc(e.Aliases, func(a string) bool {
+		return strings.EqualFold(name, a)
+	})
+}
+
+type decoder struct{ e *Encoding }
+
+func (d decoder) Reset() {}
+
+func (d decoder) Transform(dst, src []byte, _ bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for _, b := range src {
+		r := d.e.decode[b]
+		if nDst+utf8.RuneLen(r) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += utf8.EncodeRune(dst[nDst:], r)
+		nSrc++
+	}
+	return nDst, nSrc, nil
+}
+
+type encoder struct{ e *Encoding }
+
+func (e encoder) Reset() {}
+
+func (e encoder) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for nSrc < len(src) {
+		if nDst >= len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		r, size := utf8.DecodeRune(src[nSrc:])
+		if r == utf8.RuneError && !atEOF && !utf8.FullRune(src[nSrc:]) {
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		b, ok := e.e.encode[r]
+		if !ok || (r == utf8.RuneError && size == 1) {
+			return nDst, nSrc, repertoireError(encoding.ASCIISub)
+		}
+		dst[nDst] = b
+		nDst++
+		nSrc += size
+	}
+	return nDst, nSrc, nil
+}
+
+// repertoireError is returned for runes that are not mapped by the encoding,
+// its replacement byte is used by the encoding.ReplaceUnsupported encoder.
+type repertoireError byte
+
+func (r repertoireError) Error() string {
+	return "encoding: rune not supported by encoding."
+}
+
+func (r repertoireError) Replacement() byte {
+	return byte(r)
+}
+
+// Path returns the directory that contains the user-defined mapping files.
+func Path() (string, error) {
+	p, err := gap.NewScope(gap.User, meta.Dir).ConfigPath(Dir)
+	if err != nil {
+		return "", fmt.Errorf("mapping path: %w", err)
+	}
+	return p, nil
+}
+
+// loaded caches the encodings loaded from the configuration directory.
+var loaded = sync.OnceValues(func() ([]*Encoding, error) {
+	dir, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	return Load(dir)
+})
+
+// All returns the user-defined encodings found in the configuration directory.
+// Mapping files that could not be loaded are skipped, use Errors to list them.
+func All() []*Encoding {
+	e, _ := loaded()
+	return e
+}
+
+// Errors returns the problems with any mapping files in the configuration directory.
+func Errors() error {
+	_, err := loaded()
+	return err
+}
+
+// Find returns the user-defined encoding that matches the name, or nil if there is no match.
+func Find(name string) *Encoding {
+	for _, e := range All() {
+		if e.Match(name) {
+			return e
+		}
+	}
+	return nil
+}
+
+// Load returns the encodings of the mapping files in the directory.
+// A directory that does not exist is not an error.
+// The returned error joins the problems with any files that could not be loaded.
+func Load(dir string) ([]*Encoding, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("mapping load: %w", err)
+	}
+	var (
+		all  []*Encoding
+		errs []error
+	)
+	for _, entry := range entries {
+		if entry.IsDir() || !Supported(entry.Name()) {
+			continue
+		}
+		name := filepath.Join(dir, entry.Name())
+		e, err := Open(name)
+		if err == nil && slices.ContainsFunc(all, func(x *Encoding) bool { return x.Value == e.Value }) {
+			err = fmt.Errorf("%q: %w", e.Value, ErrDuplicate)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		all = append(all, e)
+	}
+	return all, errors.Join(errs...)
+}
+
+// Supported reports whether the named file uses a supported mapping file extension.
+func Supported(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".txt", ".ucm":
+		return true
+	}
+	return false
+}
+
+// Open parses the named mapping file.
+func Open(name string) (*Encoding, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("mapping open: %w", err)
+	}
+	defer f.Close()
+	return Parse(filepath.Base(name), f)
+}
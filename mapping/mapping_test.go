@@ -0,0 +1,158 @@
+package mapping_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding"
+)
+
+const text = `#
+#    Name:     cp861_DOSIcelandic to Unicode table
+#    Alias:    icelandic, ibm861
+#    Language: Icelandic
+#
+0x41	0x0041	#LATIN CAPITAL LETTER A
+0x80	0x00c7	#LATIN CAPITAL LETTER C WITH CEDILLA
+0x8b	0x00d0	#LATIN CAPITAL LETTER ETH
+0x8c	0x00f0	#LATIN SMALL LETTER ETH
+0x81		#UNDEFINED
+`
+
+const ucm = `# ICU character mapping table
+# Language: Icelandic
+<code_set_name>               "ibm-861_P100-1995"
+<mb_cur_max>                  1
+<mb_cur_min>                  1
+<subchar>                     \x7F
+
+CHARMAP
+<U0041>  \x41 |0
+<U00C7>  \x80 |0
+<U00D0>  \x8B |0
+<U00F0>  \x8C |0
+<U0110>  \x8B |1
+<U001A>  \x7F |2
+<U2302>  \x7F |3
+END CHARMAP
+`
+
+func ExampleParse() {
+	e, _ := mapping.Parse("cp861.txt", strings.NewReader(text))
+	fmt.Println(e, e.Value, e.Aliases)
+	s, _ := e.NewDecoder().String("\x80\x8b\x8c")
+	fmt.Println(s)
+	// Output: cp861_DOSIcelandic cp861 [icelandic ibm861]
+	// ÇÐð
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		filename string
+		s        string
+		wantName string
+		wantErr  error
+	}{
+		{"text", "cp861.txt", text, "cp861_DOSIcelandic", nil},
+		{"ucm", "ibm-861.ucm", ucm, "ibm-861_P100-1995", nil},
+		{"no name", "custom.TXT", "0x41\t0x0041\n", "custom", nil},
+		{"empty", "empty.txt", "# nothing here\n", "", mapping.ErrEmpty},
+		{"malformed", "bad.txt", "41\t0x0041\n", "", mapping.ErrFormat},
+		{"multi-byte text", "dbcs.txt", "0x8140\t0x3000\n", "", mapping.ErrMultiByte},
+		{"combined text", "comb.txt", "0x41\t0x0041+0x0301\n", "", mapping.ErrMultiByte},
+		{"multi-byte ucm", "dbcs.ucm", "<mb_cur_max> 2\n", "", mapping.ErrMultiByte},
+		{"multi-byte line", "dbcs.ucm", "CHARMAP\n<U3000> \\x81\\x40 |0\nEND CHARMAP\n", "", mapping.ErrMultiByte},
+		{"precision", "bad.ucm", "CHARMAP\n<U0041> \\x41 |9\nEND CHARMAP\n", "", mapping.ErrFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e, err := mapping.Parse(tt.filename, strings.NewReader(tt.s))
+			be.Err(t, err, tt.wantErr)
+			if tt.wantErr != nil {
+				return
+			}
+			be.Equal(t, e.Name, tt.wantName)
+		})
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+	for _, tt := range []struct{ filename, s string }{{"cp861.txt", text}, {"ibm-861.ucm", ucm}} {
+		e, err := mapping.Parse(tt.filename, strings.NewReader(tt.s))
+		be.Err(t, err, nil)
+		be.Equal(t, e.Language, "Icelandic")
+		be.Equal(t, e.DecodeByte(0x81), '�')
+		s, err := e.NewDecoder().String("A\x80\x81")
+		be.Err(t, err, nil)
+		be.Equal(t, s, "AÇ�")
+		b, err := e.NewEncoder().String("AÇÐð")
+		be.Err(t, err, nil)
+		be.Equal(t, b, "A\x80\x8b\x8c")
+		_, err = e.NewEncoder().String("A€")
+		be.True(t, err != nil)
+		b, err = encoding.ReplaceUnsupported(e.NewEncoder()).String("A€")
+		be.Err(t, err, nil)
+		be.Equal(t, b, "A\x1a")
+	}
+	// the ICU fallbacks only map in one direction
+	e, err := mapping.Parse("ibm-861.ucm", strings.NewReader(ucm))
+	be.Err(t, err, nil)
+	b, err := e.NewEncoder().String("Đ")
+	be.Err(t, err, nil)
+	be.Equal(t, b, "\x8b")
+	s, err := e.NewDecoder().String("\x8b\x7f")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "Ð⌂")
+	_, ok := e.EncodeRune('⌂')
+	be.True(t, !ok)
+}
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+	e, err := mapping.Parse("cp861.txt", strings.NewReader(text))
+	be.Err(t, err, nil)
+	be.True(t, e.Match("CP861"))
+	be.True(t, e.Match("cp861_dosicelandic"))
+	be.True(t, e.Match("Icelandic"))
+	be.True(t, !e.Match("cp437"))
+}
+
+func TestLoad(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	files := map[string]string{
+		"cp861.txt":   text,
+		"ibm-861.ucm": ucm,
+		"readme.md":   "not a mapping file",
+		"broken.txt":  "0x8140\t0x3000\n",
+		"CP861.TXT":   text,
+	}
+	for name, s := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(s), 0o600)
+		be.Err(t, err, nil)
+	}
+	all, err := mapping.Load(dir)
+	be.Err(t, err, mapping.ErrMultiByte)
+	be.Err(t, err, mapping.ErrDuplicate)
+	be.Equal(t, len(all), 2)
+	all, err = mapping.Load(filepath.Join(dir, "missing"))
+	be.Err(t, err, nil)
+	be.Equal(t, len(all), 0)
+}
+
+func TestSupported(t *testing.T) {
+	t.Parallel()
+	be.True(t, mapping.Supported("CP861.TXT"))
+	be.True(t, mapping.Supported("ibm-861.ucm"))
+	be.True(t, !mapping.Supported("cp861"))
+	be.True(t, !mapping.Supported("cp861.md"))
+}
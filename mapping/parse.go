@@ -0,0 +1,230 @@
+package mapping
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// precision values of the ICU mapping lines.
+const (
+	roundtrip       = 0 // roundtrip maps both the byte and the rune.
+	fallback        = 1 // fallback only maps the rune to the byte.
+	subchar         = 2 // subchar maps the rune to the substitution character.
+	reverseFallback = 3 // reverseFallback only maps the byte to the rune.
+)
+
+// parser holds the state of the parsed mapping file.
+type parser struct {
+	e       *Encoding
+	named   bool      // named reports whether a name comment was used.
+	defined [256]bool // defined are the bytes with a mapped rune.
+}
+
+// Parse reads the mapping file from r, the filename is used for the named value
+// and its extension selects the file format.
+func Parse(filename string, r io.Reader) (*Encoding, error) {
+	ext := filepath.Ext(filename)
+	base := strings.TrimSuffix(filepath.Base(filename), ext)
+	p := parser{e: &Encoding{
+		Name:   base,
+		Value:  strings.ToLower(base),
+		encode: map[rune]byte{},
+	}}
+	for i := range p.e.decode {
+		p.e.decode[i] = utf8.RuneError
+	}
+	ucm := strings.EqualFold(ext, ".ucm")
+	charmap := false
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if s, ok := strings.CutPrefix(line, "#"); ok {
+			p.comment(s)
+			continue
+		}
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		if line == "" {
+			continue
+		}
+		var err error
+		switch {
+		case !ucm:
+			err = p.text(line)
+		case strings.EqualFold(line, "CHARMAP"):
+			charmap = true
+		case strings.EqualFold(line, "END CHARMAP"):
+			charmap = false
+		case charmap:
+			err = p.ucm(line)
+		default:
+			err = p.header(line)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("mapping parse: %w", err)
+	}
+	if len(p.e.encode) == 0 {
+		return nil, ErrEmpty
+	}
+	return p.e, nil
+}
+
+// comment uses the name, alias and language comments of the mapping file.
+func (p *parser) comment(s string) {
+	key, val, ok := strings.Cut(s, ":")
+	if !ok {
+		return
+	}
+	val = strings.TrimSpace(val)
+	if val == "" {
+		return
+	}
+	switch strings.ToLower(strings.TrimSpace(key)) {
+	case "name":
+		// the Unicode.org mapping files describe the table
+		const suffix = " to Unicode table"
+		if len(val) > len(suffix) && strings.EqualFold(val[len(val)-len(suffix):], suffix) {
+			val = val[:len(val)-len(suffix)]
+		}
+		p.e.Name = val
+		p.named = true
+	case "alias", "aliases":
+		for a := range strings.SplitSeq(val, ",") {
+			if a = strings.TrimSpace(a); a != "" {
+				p.e.Aliases = append(p.e.Aliases, a)
+			}
+		}
+	case "language":
+		p.e.Language = val
+	}
+}
+
+// text parses a Unicode.org mapping line, "0xNN<TAB>0xUUUU".
+// A line without a Unicode value is an undefined byte.
+func (p *parser) text(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) < 2 { //nolint:mnd
+		_, err := code(fields[0])
+		return err
+	}
+	b, err := code(fields[0])
+	if err != nil {
+		return err
+	}
+	if strings.Contains(fields[1], "+") {
+		return fmt.Errorf("%s: %w", fields[1], ErrMultiByte)
+	}
+	r, err := point(fields[1], "0x")
+	if err != nil {
+		return err
+	}
+	p.set(b, r, roundtrip)
+	return nil
+}
+
+// header parses an ICU header line, "<key> value".
+func (p *parser) header(line string) error {
+	key, val, _ := strings.Cut(line, ">")
+	val = strings.Trim(strings.TrimSpace(val), `"`)
+	switch strings.ToLower(strings.TrimPrefix(key, "<")) {
+	case "code_set_name":
+		if !p.named && val != "" {
+			p.e.Name = val
+		}
+	case "mb_cur_max":
+		if val != "1" {
+			return fmt.Errorf("mb_cur_max %s: %w", val, ErrMultiByte)
+		}
+	}
+	return nil
+}
+
+// ucm parses an ICU mapping line, "<UXXXX> \xNN |0".
+func (p *parser) ucm(line string) error {
+	fields := strings.Fields(line)
+	if len(fields) < 2 { //nolint:mnd
+		return fmt.Errorf("%s: %w", line, ErrFormat)
+	}
+	u := fields[0]
+	if strings.Count(u, "<") != 1 || !strings.HasPrefix(u, "<U") || !strings.HasSuffix(u, ">") {
+		return fmt.Errorf("%s: %w", u, ErrMultiByte)
+	}
+	r, err := point(u[2:len(u)-1], "")
+	if err != nil {
+		return err
+	}
+	bs := fields[1]
+	if strings.Count(bs, `\x`) != 1 {
+		return fmt.Errorf("%s: %w", bs, ErrMultiByte)
+	}
+	b, err := code("0x" + strings.TrimPrefix(bs, `\x`))
+	if err != nil {
+		return err
+	}
+	precision := roundtrip
+	if len(fields) > 2 { //nolint:mnd
+		precision, err = strconv.Atoi(strings.TrimPrefix(fields[2], "|"))
+		if err != nil || precision < roundtrip || precision > reverseFallback {
+			return fmt.Errorf("%s: %w", fields[2], ErrFormat)
+		}
+	}
+	p.set(b, r, precision)
+	return nil
+}
+
+// set maps the byte and the rune using the precision,
+// the first mapping of a byte or a rune is always kept.
+func (p *parser) set(b byte, r rune, precision int) {
+	if precision == subchar {
+		return
+	}
+	if precision != fallback && !p.defined[b] {
+		p.e.decode[b] = r
+		p.defined[b] = true
+	}
+	if precision == reverseFallback {
+		return
+	}
+	if _, ok := p.e.encode[r]; !ok {
+		p.e.encode[r] = b
+	}
+}
+
+// code parses a hexadecimal byte value, "0xNN".
+func code(s string) (byte, error) {
+	x, ok := strings.CutPrefix(strings.ToLower(s), "0x")
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrFormat)
+	}
+	i, err := strconv.ParseUint(x, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", s, ErrFormat)
+	}
+	if i > 0xff { //nolint:mnd
+		return 0, fmt.Errorf("%s: %w", s, ErrMultiByte)
+	}
+	return byte(i), nil
+}
+
+// point parses a hexadecimal Unicode code point that uses the prefix.
+func point(s, prefix string) (rune, error) {
+	x, ok := strings.CutPrefix(strings.ToLower(s), prefix)
+	if !ok {
+		return 0, fmt.Errorf("%s: %w", s, ErrFormat)
+	}
+	i, err := strconv.ParseUint(x, 16, 32)
+	if err != nil || !utf8.ValidRune(rune(i)) {
+		return 0, fmt.Errorf("%s: %w", s, ErrFormat)
+	}
+	return rune(i), nil
+}
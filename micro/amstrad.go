@@ -0,0 +1,86 @@
+package micro
+
+import (
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/mapping"
+)
+
+// AmstradCPC is the Amstrad CPC computer encoding.
+var AmstradCPC = &Encoding{
+	Name: "Amstrad CPC", Value: "amstrad-cpc", Alias: "cpc",
+	sets: [2]*mapping.Encoding{mapping.New("Amstrad CPC", "amstrad-cpc", cpc())},
+	text: amstrad,
+}
+
+// amstradParams are the number of parameters used by the CPC control codes.
+var amstradParams = [0x20]int{
+	0x01: 1, // print the next byte as a character
+	0x04: 1, // set the screen mode
+	0x05: 1, // print the next byte at the graphics cursor
+	0x0e: 1, // set the paper ink
+	0x0f: 1, // set the pen ink
+	0x16: 1, // switch the transparent mode
+	0x17: 1, // set the graphics ink mode
+	0x19: 9, // define a character
+	0x1a: 4, // set the text window
+	0x1c: 3, // set the colors of an ink
+	0x1d: 2, // set the border colors
+	0x1f: 2, // move the cursor to a column and row
+}
+
+// cpc returns the Amstrad CPC character set.
+// The control codes keep their C0 values, while the graphics of 0xC0 to 0xDF, 0xEF
+// and 0xFC to 0xFF have no Unicode equivalents and are not mapped.
+func cpc() [256]rune {
+	t := blank()
+	ascii(&t, 0x00, 0x7f)
+	t[0x5e] = '↑'
+	copy(t[0x80:], quadrants[:])
+	// the line pieces of 0x91 to 0x9F use bit 0 for up, bit 1 right, bit 2 down and bit 3 left
+	copy(t[0x90:], []rune{'·', '╵', '╶', '└', '╷', '│', '┌', '├', '╴', '┘', '─', '┴', '┐', '┤', '┬', '┼'})
+	copy(t[0xa0:], []rune{'^', '´', '¨', '£', '©', '¶', '§', '‘', '¼', '½', '¾', '±', '÷', '¬', '¿', '¡'})
+	copy(t[0xb0:], []rune{'α', 'β', 'γ', 'δ', 'ε', 'θ', 'λ', 'μ', 'π', 'σ', 'φ', 'ψ', 'χ', 'ω', 'Σ', 'Ω'})
+	copy(t[0xe0:], []rune{'☺', '☹', '♣', '♦', '♥', '♠', '○', '●', '□', '■', '♂', '♀', '♩', '♪', '☼'})
+	copy(t[0xf0:], []rune{'↑', '↓', '←', '→', '▲', '▼', '▶', '◀', '🯅', '🯆', '🯇', '🯈'})
+	return t
+}
+
+// amstrad returns the text of the Amstrad CPC byte.
+func amstrad(e *Encoding, s *state, b byte) string {
+	if s.want > 0 {
+		if !s.param(b) {
+			return ""
+		}
+		switch s.control {
+		case 0x01:
+			return e.glyph(s, s.params[0])
+		case 0x1f:
+			s.column = int(s.params[0]) - 1
+			return locate(int(s.params[1]), int(s.params[0]))
+		}
+		return ""
+	}
+	if b >= 0x20 {
+		return e.glyph(s, b)
+	}
+	if n := amstradParams[b]; n > 0 {
+		return s.wait(b, n)
+	}
+	switch b {
+	case '\t', '\r':
+		return string(rune(b))
+	case '\n':
+		return s.newline()
+	case 0x08:
+		return left
+	case 0x0b:
+		return up
+	case 0x0c:
+		return erase + home
+	case 0x18:
+		return s.reverse(!s.attr.Has(ansi.Inverse))
+	case 0x1e:
+		return home
+	}
+	return ""
+}
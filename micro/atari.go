@@ -0,0 +1,109 @@
+package micro
+
+import (
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	// ATASCII is the Atari 8-bit computer encoding.
+	ATASCII = &Encoding{
+		Name: "Atari ATASCII", Value: "atascii", Alias: "atari",
+		sets: [2]*mapping.Encoding{mapping.New("Atari ATASCII", "atascii", atascii())},
+		text: atari,
+	}
+	// AtariST is the Atari ST computer encoding.
+	AtariST = &Encoding{
+		Name: "Atari ST", Value: "atarist", Alias: "atari-st",
+		sets: [2]*mapping.Encoding{mapping.New("Atari ST", "atarist", atariST())},
+		text: plain,
+	}
+)
+
+// eol is the ATASCII end of line code.
+const eol = 0x9b
+
+// atascii returns the ATASCII character set.
+// The codes 0x80 to 0xFF are the inverse video characters of 0x00 to 0x7F.
+func atascii() [256]rune {
+	var t [256]rune
+	low := [32]rune{
+		'♥', '├', '🮇', '┘', '┤', '┐', '╱', '╲', '◢', '▗', '◣', '▝', '▘', '🮂', '▂', '▖',
+		'♣', '┌', '─', '┼', '●', '▄', '▎', '┬', '┴', '▌', '└', '␛', '↑', '↓', '←', '→',
+	}
+	copy(t[:], low[:])
+	ascii(&t, 0x20, 0x7f)
+	letters(&t, 0x61, 'a')
+	t[0x60], t[0x7b], t[0x7d], t[0x7e], t[0x7f] = '♦', '♠', '↰', '◀', '▶'
+	for i := range 0x80 {
+		t[0x80+i] = t[i]
+	}
+	t[eol] = '\n'
+	return t
+}
+
+// atari returns the text of the ATASCII byte.
+func atari(e *Encoding, s *state, b byte) string {
+	const inverse = 0x80
+	if s.literal {
+		s.literal = false
+		return s.reverse(b >= inverse) + e.glyph(s, b)
+	}
+	switch b {
+	case 0x1b:
+		// escape prints the next control code as a character
+		s.literal = true
+		return ""
+	case 0x1c:
+		return up
+	case 0x1d:
+		return down
+	case 0x1e, 0x7e:
+		return left
+	case 0x1f:
+		return right
+	case 0x7d:
+		return erase + home
+	case 0x7f:
+		return "\t"
+	case eol:
+		return s.newline()
+	case 0x9c:
+		return "\x1b[M"
+	case 0x9d:
+		return "\x1b[L"
+	case 0x9e, 0x9f, 0xfd, 0xfe, 0xff:
+		// tab stops, the bell and the insert and delete characters
+		return ""
+	}
+	return s.reverse(b >= inverse) + e.glyph(s, b)
+}
+
+// atariST returns the Atari ST character set.
+// The control codes keep their C0 values, the Hebrew letters use the code points 0xC2 to 0xDC.
+func atariST() [256]rune {
+	var t [256]rune
+	ascii(&t, 0x00, 0x7e)
+	t[0x7f] = '⌂'
+	for i := 0x80; i <= 0xaf; i++ {
+		t[i] = charmap.CodePage437.DecodeByte(byte(i))
+	}
+	t[0x9e], t[0x9f] = 'ß', 'ƒ'
+	high := []rune{
+		'ã', 'õ', 'Ø', 'ø', 'œ', 'Œ', 'À', 'Ã', 'Õ', '¨', '´', '†', '¶', '©', '®', '™',
+		'ĳ', 'Ĳ', 'א', 'ב', 'ג', 'ד', 'ה', 'ו', 'ז', 'ח', 'ט', 'י', 'כ', 'ל', 'מ', 'נ',
+		'ס', 'ע', 'פ', 'צ', 'ק', 'ר', 'ש', 'ת', 'ן', 'ך', 'ם', 'ף', 'ץ', '§', '∧', '∞',
+		'α', 'β', 'Γ', 'π', 'Σ', 'σ', 'µ', 'τ', 'Φ', 'Θ', 'Ω', 'δ', '∮', 'ϕ', '∈', '∩',
+		'≡', '±', '≥', '≤', '⌠', '⌡', '÷', '≈', '°', '∙', '·', '√', 'ⁿ', '²', '³', '¯',
+	}
+	copy(t[0xb0:], high)
+	return t
+}
+
+// plain returns the character of the byte, the control codes are kept as-is.
+func plain(e *Encoding, s *state, b byte) string {
+	if b == '\n' {
+		return s.newline()
+	}
+	return e.glyph(s, b)
+}
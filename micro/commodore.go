@@ -0,0 +1,207 @@
+package micro
+
+import (
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/mapping"
+)
+
+// screenColumns is the number of characters per line of the Commodore 64 screen.
+const screenColumns = 40
+
+var (
+	// PETSCII is the Commodore 64 PETSCII encoding using the unshifted, uppercase and graphics character set.
+	PETSCII = &Encoding{
+		Name: "Commodore PETSCII", Value: "petscii", Alias: "c64",
+		sets: [2]*mapping.Encoding{
+			mapping.New("Commodore PETSCII", "petscii", petscii(false)),
+			mapping.New("Commodore PETSCII shifted", "petscii-sh", petscii(true)),
+		},
+		text: commodore,
+	}
+	// PETSCIIShifted is the Commodore 64 PETSCII encoding using the shifted, lower and uppercase character set.
+	PETSCIIShifted = &Encoding{
+		Name: "Commodore PETSCII shifted", Value: "petscii-sh", Alias: "c64-shifted",
+		sets:    PETSCII.sets,
+		shifted: true,
+		text:    commodore,
+	}
+	// ScreenCodes are the Commodore 64 screen codes stored in the video memory,
+	// using the unshifted, uppercase and graphics character set.
+	ScreenCodes = &Encoding{
+		Name: "Commodore screen codes", Value: "screencode", Alias: "c64-screen",
+		sets: [2]*mapping.Encoding{
+			mapping.New("Commodore screen codes", "screencode", screen(false)),
+			mapping.New("Commodore screen shifted", "screen-sh", screen(true)),
+		},
+		text: video,
+	}
+	// ScreenCodesShifted are the Commodore 64 screen codes stored in the video memory,
+	// using the shifted, lower and uppercase character set.
+	ScreenCodesShifted = &Encoding{
+		Name: "Commodore screen shifted", Value: "screen-sh", Alias: "c64-scr-sh",
+		sets:    ScreenCodes.sets,
+		shifted: true,
+		text:    video,
+	}
+)
+
+// graphics are the PETSCII characters 0xC0 to 0xDF of the unshifted set,
+// which are also used by 0x60 to 0x7F.
+var graphics = [32]rune{
+	'─', '♠', '🭲', '🭸', '🭷', '🭶', '🭺', '🭱', '🭴', '╮', '╰', '╯', '🭼', '╲', '╱', '🭽',
+	'🭾', '●', '🭻', '♥', '🭰', '╭', '╳', '○', '♣', '🭵', '♦', '┼', '🮌', '│', 'π', '◥',
+}
+
+// blocks are the PETSCII characters 0xA0 to 0xBF of the unshifted set,
+// which are also used by 0xE0 to 0xFE.
+var blocks = [32]rune{
+	' ', '▌', '▄', '▔', '▁', '▏', '▒', '▕', '🮏', '◤', '🮇', '├', '▗', '└', '┐', '▂',
+	'┌', '┴', '┬', '┤', '▎', '▍', '🮈', '🮂', '🮃', '▃', '🭿', '▖', '▝', '┘', '▘', '▚',
+}
+
+// shiftGraphics returns the graphic characters replaced by the uppercase letters of the shifted set.
+func shiftGraphics() [32]rune {
+	g := graphics
+	for i := 1; i <= 26; i++ {
+		g[i] = 'A' + rune(i-1)
+	}
+	g[30], g[31] = '🮖', '🮘'
+	return g
+}
+
+// shiftBlocks returns the block characters of the shifted set.
+func shiftBlocks() [32]rune {
+	b := blocks
+	b[0x09], b[0x1a] = '🮙', '✓'
+	return b
+}
+
+// letters sets the 26 letters of the alphabet from the first byte.
+func letters(t *[256]rune, first int, a rune) {
+	for i := range 26 {
+		t[first+i] = a + rune(i)
+	}
+}
+
+// petscii returns the PETSCII character set.
+// The control codes keep their C0 and C1 values, except the return codes which are line feeds
+// and the unused line feed code.
+func petscii(shifted bool) [256]rune {
+	var t [256]rune
+	ascii(&t, 0x00, 0x5f)
+	ascii(&t, 0x80, 0x9f)
+	t[0x0a], t[0x0d], t[0x8d] = utf8.RuneError, '\n', '\n'
+	t[0x5c], t[0x5e], t[0x5f] = '£', '↑', '←'
+	g, b := graphics, blocks
+	if shifted {
+		g, b = shiftGraphics(), shiftBlocks()
+		letters(&t, 0x41, 'a')
+	}
+	for i := range 32 {
+		t[0x60+i] = g[i]
+		t[0xa0+i] = b[i]
+		t[0xc0+i] = g[i]
+		t[0xe0+i] = b[i]
+	}
+	t[0xff] = 'π'
+	if shifted {
+		t[0xff] = '🮖'
+	}
+	return t
+}
+
+// screen returns the character set of the screen codes.
+// The codes 0x80 to 0xFF are the reverse video characters of 0x00 to 0x7F.
+func screen(shifted bool) [256]rune {
+	var t [256]rune
+	ascii(&t, 0x20, 0x3f)
+	t[0x00] = '@'
+	letters(&t, 0x01, 'A')
+	t[0x1b], t[0x1c], t[0x1d], t[0x1e], t[0x1f] = '[', '£', ']', '↑', '←'
+	g, b := graphics, blocks
+	if shifted {
+		g, b = shiftGraphics(), shiftBlocks()
+		letters(&t, 0x01, 'a')
+	}
+	for i := range 32 {
+		t[0x40+i] = g[i]
+		t[0x60+i] = b[i]
+	}
+	for i := range 0x80 {
+		t[0x80+i] = t[i]
+	}
+	return t
+}
+
+// palette are the Commodore 64 colors selected by the PETSCII control codes.
+var palette = map[byte]ansi.Color{
+	0x05: ansi.TrueColor(0xff, 0xff, 0xff), // white
+	0x1c: ansi.TrueColor(0x88, 0x00, 0x00), // red
+	0x1e: ansi.TrueColor(0x00, 0xcc, 0x55), // green
+	0x1f: ansi.TrueColor(0x00, 0x00, 0xaa), // blue
+	0x81: ansi.TrueColor(0xdd, 0x88, 0x55), // orange
+	0x90: ansi.TrueColor(0x00, 0x00, 0x00), // black
+	0x95: ansi.TrueColor(0x66, 0x44, 0x00), // brown
+	0x96: ansi.TrueColor(0xff, 0x77, 0x77), // light red
+	0x97: ansi.TrueColor(0x33, 0x33, 0x33), // dark grey
+	0x98: ansi.TrueColor(0x77, 0x77, 0x77), // grey
+	0x99: ansi.TrueColor(0xaa, 0xff, 0x66), // light green
+	0x9a: ansi.TrueColor(0x00, 0x88, 0xff), // light blue
+	0x9b: ansi.TrueColor(0xbb, 0xbb, 0xbb), // light grey
+	0x9c: ansi.TrueColor(0xcc, 0x44, 0xcc), // purple
+	0x9e: ansi.TrueColor(0xee, 0xee, 0x77), // yellow
+	0x9f: ansi.TrueColor(0xaa, 0xff, 0xee), // cyan
+}
+
+// commodore returns the text of the PETSCII byte.
+func commodore(e *Encoding, s *state, b byte) string {
+	switch b {
+	case 0x0d, 0x8d:
+		// both return codes also switch off the reverse video
+		return s.reverse(false) + s.newline()
+	case 0x0e:
+		s.shifted = true
+		return ""
+	case 0x8e:
+		s.shifted = false
+		return ""
+	case 0x12:
+		return s.reverse(true)
+	case 0x92:
+		return s.reverse(false)
+	case 0x11:
+		return down
+	case 0x91:
+		return up
+	case 0x1d:
+		return right
+	case 0x9d:
+		return left
+	case 0x13:
+		return home
+	case 0x93:
+		return erase + home
+	}
+	if c, ok := palette[b]; ok {
+		a := s.attr
+		a.FG = c
+		return s.set(a)
+	}
+	if b < 0x20 || (b >= 0x80 && b < 0xa0) {
+		return ""
+	}
+	return e.glyph(s, b)
+}
+
+// video returns the text of the screen code byte,
+// with a line break after every row of the screen.
+func video(e *Encoding, s *state, b byte) string {
+	const reversed = 0x80
+	text := s.reverse(b >= reversed) + e.glyph(s, b)
+	if s.column >= screenColumns {
+		text += s.reverse(false) + s.newline()
+	}
+	return text
+}
@@ -0,0 +1,227 @@
+// Package micro provides the character encodings of the 1980s home microcomputers.
+//
+// These are the Commodore 64 PETSCII, in both its unshifted and shifted character sets
+// and as the screen codes used by the video memory, the Atari 8-bit ATASCII,
+// the Atari ST, the Sinclair ZX Spectrum and the Amstrad CPC.
+//
+// None of these encodings are compatible with ASCII for every printable character.
+// The mosaic and graphic characters of the machine fonts use the Unicode
+// Symbols for Legacy Computing block, so a font that supports the block is needed to view them.
+//
+// The decoders interpret the machine control codes. The colors and the reverse video codes
+// become ANSI select graphic rendition (SGR) sequences, the cursor movements become
+// ANSI cursor sequences, the ZX Spectrum BASIC tokens become keywords and the remaining
+// control codes are dropped. The encoders use the glyphs of the machine fonts.
+package micro
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// ANSI escape sequences for the cursor movements of the machine control codes.
+const (
+	up    = "\x1b[A"  // up moves the cursor up a line.
+	down  = "\x1b[B"  // down moves the cursor down a line.
+	right = "\x1b[C"  // right moves the cursor forward a column.
+	left  = "\x1b[D"  // left moves the cursor back a column.
+	home  = "\x1b[H"  // home moves the cursor to the top left of the screen.
+	erase = "\x1b[2J" // erase erases the screen.
+)
+
+// maxParams is the largest number of parameters used by a control code.
+const maxParams = 9
+
+// Encoding is the character encoding of a home computer that implements the Encoding interface.
+type Encoding struct {
+	Name  string // Name is the formal name of the character encoding.
+	Value string // Value is the short name of the character encoding.
+	Alias string // Alias is an informal name of the character encoding.
+
+	sets    [2]*mapping.Encoding // sets are the unshifted and shifted character sets.
+	shifted bool                 // shifted reports whether the shifted set is used at the start.
+	text    func(e *Encoding, s *state, b byte) string
+}
+
+// state is the machine state kept by the decoder.
+type state struct {
+	attr    ansi.Attr       // attr are the display attributes of the text.
+	shifted bool            // shifted reports whether the shifted character set is used.
+	column  int             // column is the number of characters on the line.
+	control byte            // control is the code waiting for its parameters.
+	params  [maxParams]byte // params are the parameters read for the control code.
+	want    int             // want is the number of parameters used by the control code.
+	n       int             // n is the number of parameters read.
+	literal bool            // literal prints the next byte as a character.
+}
+
+// String returns the formal name of the encoding.
+func (e *Encoding) String() string {
+	return e.Name
+}
+
+// Glyphs returns the character set of the machine font, which maps each byte to a rune.
+// The control codes are not interpreted, so it suits the character tables.
+func (e *Encoding) Glyphs() *mapping.Encoding {
+	if e.shifted {
+		return e.sets[1]
+	}
+	return e.sets[0]
+}
+
+// NewDecoder returns a decoder that converts the encoded bytes into UTF-8,
+// while interpreting the control codes of the machine.
+func (e *Encoding) NewDecoder() *encoding.Decoder {
+	d := &decoder{e: e}
+	d.Reset()
+	return &encoding.Decoder{Transformer: d}
+}
+
+// NewEncoder returns an encoder that converts UTF-8 text into the glyphs of the machine font.
+// Runes that are not mapped by the encoding return an error.
+func (e *Encoding) NewEncoder() *encoding.Encoder {
+	return e.Glyphs().NewEncoder()
+}
+
+// Match reports whether the name matches the formal name, the named value
+// or the alias of the encoding. The comparison is case-insensitive.
+func (e *Encoding) Match(name string) bool {
+	return strings.EqualFold(name, e.Name) ||
+		strings.EqualFold(name, e.Value) ||
+		strings.EqualFold(name, e.Alias)
+}
+
+// glyph returns the rune of the byte using the current character set.
+func (e *Encoding) glyph(s *state, b byte) string {
+	set := e.sets[0]
+	if s.shifted && e.sets[1] != nil {
+		set = e.sets[1]
+	}
+	s.column++
+	return string(set.DecodeByte(b))
+}
+
+// All returns the home computer encodings.
+func All() []*Encoding {
+	return []*Encoding{
+		PETSCII, PETSCIIShifted, ScreenCodes, ScreenCodesShifted,
+		ATASCII, AtariST, ZXSpectrum, AmstradCPC,
+	}
+}
+
+// Find returns the home computer encoding that matches the name, or nil if there is no match.
+func Find(name string) *Encoding {
+	for _, e := range All() {
+		if e.Match(name) {
+			return e
+		}
+	}
+	return nil
+}
+
+// wait sets the control code to wait for the number of parameters.
+func (s *state) wait(control byte, n int) string {
+	s.control, s.want, s.n = control, n, 0
+	return ""
+}
+
+// param stores the parameter byte and reports whether all the parameters have been read.
+func (s *state) param(b byte) bool {
+	s.params[s.n] = b
+	s.n++
+	if s.n < s.want {
+		return false
+	}
+	s.want = 0
+	return true
+}
+
+// newline returns a line break and resets the column.
+func (s *state) newline() string {
+	s.column = 0
+	return "\n"
+}
+
+// set returns the SGR sequence for the attribute, or nothing if the attribute is unchanged.
+func (s *state) set(a ansi.Attr) string {
+	if s.attr == a {
+		return ""
+	}
+	s.attr = a
+	return a.SGR()
+}
+
+// reverse returns the SGR sequence to switch the reverse video on or off.
+func (s *state) reverse(on bool) string {
+	a := s.attr
+	a.Flags &^= ansi.Inverse
+	if on {
+		a.Flags |= ansi.Inverse
+	}
+	return s.set(a)
+}
+
+// locate returns the ANSI sequence to move the cursor to the one-based row and column.
+func locate(row, column int) string {
+	return "\x1b[" + strconv.Itoa(row) + ";" + strconv.Itoa(column) + "H"
+}
+
+// decoder is the transformer that decodes the bytes of a home computer encoding.
+type decoder struct {
+	e *Encoding
+	s state
+}
+
+func (d *decoder) Reset() {
+	d.s = state{shifted: d.e.shifted}
+}
+
+func (d *decoder) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for _, b := range src {
+		// the state is only kept once the text fits the destination
+		next := d.s
+		s := d.e.text(d.e, &next, b)
+		if nDst+len(s) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], s)
+		nSrc++
+		d.s = next
+	}
+	if atEOF && d.s.attr != (ansi.Attr{}) {
+		reset := ansi.Attr{}.SGR()
+		if nDst+len(reset) > len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		nDst += copy(dst[nDst:], reset)
+		d.s.attr = ansi.Attr{}
+	}
+	return nDst, nSrc, nil
+}
+
+// blank returns a character set with every byte unmapped.
+func blank() [256]rune {
+	var t [256]rune
+	for i := range t {
+		t[i] = utf8.RuneError
+	}
+	return t
+}
+
+// ascii maps the range of bytes in the character set to the same ASCII or Unicode values.
+func ascii(t *[256]rune, first, last int) {
+	for i := first; i <= last; i++ {
+		t[i] = rune(i)
+	}
+}
+
+// quadrants are the block characters indexed by the filled quarters,
+// 1 is the top left, 2 is the top right, 4 is the bottom left and 8 is the bottom right.
+var quadrants = [16]rune{' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█'}
@@ -0,0 +1,118 @@
+package micro_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/micro"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/transform"
+)
+
+func ExampleFind() {
+	e := micro.Find("c64")
+	fmt.Println(e, e.Value)
+	s, _ := e.NewDecoder().String("HELLO \xd3\xc1")
+	fmt.Println(s)
+	// Output: Commodore PETSCII petscii
+	// HELLO ♥♠
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+	const reset = "\x1b[0m"
+	tests := []struct {
+		name string
+		e    *micro.Encoding
+		s    string
+		want string
+	}{
+		{"petscii", micro.PETSCII, "A\\\xa6\xdf\xff", "A£▒◥π"},
+		{"petscii color", micro.PETSCII, "\x1cRED\x05", "\x1b[0;38;2;136;0;0mRED\x1b[0;38;2;255;255;255m" + reset},
+		{"petscii reverse", micro.PETSCII, "\x12ON\rOFF", "\x1b[0;7mON\x1b[0m\nOFF"},
+		{"petscii shift", micro.PETSCII, "AB\x0eAb\x8eA", "ABaBA"},
+		{"petscii cursor", micro.PETSCII, "\x93\x11\x1dA", "\x1b[2J\x1b[H\x1b[B\x1b[CA"},
+		{"petscii shifted", micro.PETSCIIShifted, "Hello\xc1\xfa", "hELLOA✓"},
+		{"screen codes", micro.ScreenCodes, "\x08\x09\x20\x81", "HI \x1b[0;7mA" + reset},
+		{"screen shifted", micro.ScreenCodesShifted, "\x08\x49", "hI"},
+		{"screen row", micro.ScreenCodes, strings.Repeat("\x01", 41), strings.Repeat("A", 40) + "\nA"},
+		{"atascii", micro.ATASCII, "\x00\x60\x7bAa\x9b", "♥♦♠Aa\n"},
+		{"atascii inverse", micro.ATASCII, "\xc1A", "\x1b[0;7mA\x1b[0mA"},
+		{"atascii escape", micro.ATASCII, "\x1c\x1b\x1c", "\x1b[A↑"},
+		{"atari st", micro.AtariST, "\x9e\xc2\xe0\x7f", "ßאα⌂"},
+		{"spectrum", micro.ZXSpectrum, "\x5e\x60\x7f\x83\x90", "↑£©▀A"},
+		{"spectrum tokens", micro.ZXSpectrum, "\xf5\"HI\"\xc8\xa5", " PRINT \"HI\">=RND"},
+		{"spectrum ink", micro.ZXSpectrum, "\x10\x02R\x14\x01I\x10\x08", "\x1b[0;31mR\x1b[0;7;31mI" + reset},
+		{"spectrum at", micro.ZXSpectrum, "\x16\x01\x02A\x17\x05\x00B", "\x1b[2;3HA\x1b[6GB"},
+		{"amstrad cpc", micro.AmstradCPC, "\x81\x8f\x96\xa3\xe4", "▘█┌£♥"},
+		{"amstrad controls", micro.AmstradCPC, "\x0f\x02A\x1f\x05\x03B\x01\x07\r\n", "A\x1b[3;5HB\a\r\n"},
+		{"amstrad inverse", micro.AmstradCPC, "\x18A\x18B", "\x1b[0;7mA\x1b[0mB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := tt.e.NewDecoder().String(tt.s)
+			be.Err(t, err, nil)
+			be.Equal(t, s, tt.want)
+		})
+	}
+}
+
+func TestShortDst(t *testing.T) {
+	t.Parallel()
+	// a small destination must not lose the escape sequences or the machine state
+	const s = "\x1cRED\x12REV\x0eShift\r"
+	want, err := micro.PETSCII.NewDecoder().String(s)
+	be.Err(t, err, nil)
+	r := transform.NewReader(strings.NewReader(s), micro.PETSCII.NewDecoder())
+	var b strings.Builder
+	p := make([]byte, 20)
+	for {
+		n, err := r.Read(p)
+		b.Write(p[:n])
+		if err != nil {
+			break
+		}
+	}
+	be.Equal(t, b.String(), want)
+}
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		e    *micro.Encoding
+		s    string
+		want string
+	}{
+		{micro.PETSCII, "HELLO ♥\n", "HELLO \x73\x0d"},
+		{micro.PETSCIIShifted, "Hello", "\x68ELLO"},
+		{micro.ScreenCodes, "HI", "\x08\x09"},
+		{micro.ATASCII, "Hi♥\n", "Hi\x00\x9b"},
+		{micro.AtariST, "Straße", "Stra\x9ee"},
+		{micro.ZXSpectrum, "£1\n", "\x601\x0d"},
+		{micro.AmstradCPC, "π£", "\xb8\xa3"},
+	}
+	for _, tt := range tests {
+		b, err := tt.e.NewEncoder().String(tt.s)
+		be.Err(t, err, nil)
+		be.Equal(t, b, tt.want)
+	}
+	_, err := micro.ZXSpectrum.NewEncoder().String("€")
+	be.True(t, err != nil)
+}
+
+func TestGlyphs(t *testing.T) {
+	t.Parallel()
+	for _, e := range micro.All() {
+		g := e.Glyphs()
+		be.Equal(t, g.DecodeByte('0'), '0')
+		be.Equal(t, micro.Find(e.Value), e)
+		be.Equal(t, micro.Find(strings.ToUpper(e.Alias)), e)
+	}
+	// the tables ignore the control codes and the reverse video
+	be.Equal(t, micro.PETSCII.Glyphs().DecodeByte(0x1c), '\x1c')
+	be.Equal(t, micro.ScreenCodes.Glyphs().DecodeByte(0x81), 'A')
+	be.Equal(t, micro.ZXSpectrum.Glyphs().DecodeByte(0xf5), '�')
+	be.Equal(t, micro.Find("cp437") == nil, true)
+}
@@ -0,0 +1,140 @@
+package micro
+
+import (
+	"strconv"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/mapping"
+)
+
+// ZXSpectrum is the Sinclair ZX Spectrum computer encoding.
+var ZXSpectrum = &Encoding{
+	Name: "Sinclair ZX Spectrum", Value: "zx-spectrum", Alias: "spectrum",
+	sets: [2]*mapping.Encoding{mapping.New("Sinclair ZX Spectrum", "zx-spectrum", spectrum())},
+	text: sinclair,
+}
+
+// Spectrum control codes that use parameters.
+const (
+	ink     = 0x10 // ink sets the foreground color.
+	paper   = 0x11 // paper sets the background color.
+	flash   = 0x12 // flash switches the flashing characters.
+	bright  = 0x13 // bright switches the bright colors.
+	inverse = 0x14 // inverse switches the inverse video.
+	over    = 0x15 // over switches the overprinting.
+	at      = 0x16 // at moves the cursor to a row and column.
+	tab     = 0x17 // tab moves the cursor to a column.
+)
+
+// firstToken is the first of the Spectrum BASIC keyword tokens.
+const firstToken = 0xa5
+
+// tokens are the Spectrum BASIC keywords of the codes 0xA5 to 0xFF.
+var tokens = [0x100 - firstToken]string{
+	"RND", "INKEY$", "PI", "FN ", "POINT ", "SCREEN$ ", "ATTR ", "AT ", "TAB ",
+	"VAL$ ", "CODE ", "VAL ", "LEN ", "SIN ", "COS ", "TAN ", "ASN ", "ACS ",
+	"ATN ", "LN ", "EXP ", "INT ", "SQR ", "SGN ", "ABS ", "PEEK ", "IN ",
+	"USR ", "STR$ ", "CHR$ ", "NOT ", "BIN ", " OR ", " AND ", "<=", ">=",
+	"<>", " LINE ", " THEN ", " TO ", " STEP ", " DEF FN ", " CAT ", " FORMAT ", " MOVE ",
+	" ERASE ", " OPEN #", " CLOSE #", " MERGE ", " VERIFY ", " BEEP ", " CIRCLE ", " INK ", " PAPER ",
+	" FLASH ", " BRIGHT ", " INVERSE ", " OVER ", " OUT ", " LPRINT ", " LLIST ", " STOP ", " READ ",
+	" DATA ", " RESTORE ", " NEW ", " BORDER ", " CONTINUE ", " DIM ", " REM ", " FOR ", " GO TO ",
+	" GO SUB ", " INPUT ", " LOAD ", " LIST ", " LET ", " PAUSE ", " NEXT ", " POKE ", " PRINT ",
+	" PLOT ", " RUN ", " SAVE ", " RANDOMIZE ", " IF ", " CLS ", " DRAW ", " CLEAR ", " RETURN ",
+	" COPY ",
+}
+
+// spectrum returns the ZX Spectrum character set.
+// The user-defined graphics use the letters A to U of their default shapes,
+// and the BASIC keyword tokens are not mapped.
+func spectrum() [256]rune {
+	t := blank()
+	ascii(&t, 0x20, 0x7f)
+	t[0x0d] = '\n'
+	t[0x5e], t[0x60], t[0x7f] = '↑', '£', '©'
+	for i := range 16 {
+		// bit 0 is the top right, bit 1 the top left, bit 2 the bottom right and bit 3 the bottom left
+		q := i&1<<1 | i&2>>1 | i&4<<1 | i&8>>1
+		t[0x80+i] = quadrants[q]
+	}
+	for i := 0x90; i < firstToken; i++ {
+		t[i] = 'A' + rune(i-0x90)
+	}
+	return t
+}
+
+// colors are the ANSI indexes of the eight Spectrum colors,
+// black, blue, red, magenta, green, cyan, yellow and white.
+var colors = [8]uint8{0, 4, 1, 5, 2, 6, 3, 7}
+
+// sinclair returns the text of the ZX Spectrum byte.
+func sinclair(e *Encoding, s *state, b byte) string {
+	if s.want > 0 {
+		if !s.param(b) {
+			return ""
+		}
+		return control(s)
+	}
+	switch {
+	case b == 0x0d:
+		return s.newline()
+	case b == 0x06:
+		// the print comma
+		return "\t"
+	case b == 0x08:
+		return left
+	case b == 0x09:
+		return right
+	case b >= ink && b <= over:
+		return s.wait(b, 1)
+	case b == at, b == tab:
+		return s.wait(b, 2) //nolint:mnd
+	case b < 0x20:
+		return ""
+	case b >= firstToken:
+		k := tokens[b-firstToken]
+		s.column += len(k)
+		return k
+	}
+	return e.glyph(s, b)
+}
+
+// control returns the text of the Spectrum control code and its parameters.
+func control(s *state) string {
+	p := s.params[0]
+	a := s.attr
+	on := func(f ansi.Flags) {
+		a.Flags &^= f
+		if p == 1 {
+			a.Flags |= f
+		}
+	}
+	switch s.control {
+	case ink, paper:
+		if int(p) >= len(colors) {
+			// 8 is transparent and 9 is contrast, both keep the colors
+			return ""
+		}
+		if s.control == ink {
+			a.FG = ansi.Index(colors[p])
+			break
+		}
+		a.BG = ansi.Index(colors[p])
+	case flash:
+		on(ansi.Blink)
+	case bright:
+		on(ansi.Bold)
+	case inverse:
+		on(ansi.Inverse)
+	case at:
+		s.column = int(s.params[1])
+		return locate(int(p)+1, int(s.params[1])+1)
+	case tab:
+		const columns = 32
+		s.column = int(p) % columns
+		return "\x1b[" + strconv.Itoa(s.column+1) + "G"
+	default:
+		return ""
+	}
+	return s.set(a)
+}
@@ -0,0 +1,439 @@
+// Package music finds the ANSI music sequences of text art and saves them as standard MIDI files.
+//
+// ANSI music is the extension of the BBS terminals that plays tunes on the PC speaker.
+// A sequence begins with the ESC [ M or ESC [ N controls, followed by the music
+// macro language (MML) of the BASIC PLAY statement and ends with a shift out (SO) control.
+// When the ESC [ M introducer is followed by the F, B, N, L or S characters,
+// they are read as the MF, MB, MN, ML and MS commands.
+//
+// The supported commands of the music macro language are:
+//
+//	A to G   play the note, followed by an optional #, + or - and a length
+//	N n      play the note number 0 to 84, where 0 is a rest
+//	O n      set the octave 0 to 6, octave 3 starts with middle C
+//	< >      change the octave down or up
+//	L n      set the length of the notes, 1 is a whole note and 4 is a quarter note
+//	P n      pause for the length, R is an alias
+//	T n      set the tempo of 32 to 255 quarter notes per minute
+//	MN ML MS play the notes normal, legato or staccato
+//	MF MB    play in the foreground or the background, which are ignored
+//
+// A period after a note or a pause extends its length by half.
+package music
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/transform"
+)
+
+var ErrNone = errors.New("no ansi music sequences were found")
+
+const (
+	esc    = 0x1b // esc is the escape control.
+	so     = 0x0e // so is the shift out control that ends a sequence.
+	maxLen = 2048 // maxLen is the maximum number of bytes of a sequence.
+)
+
+// Find returns the music macro language of each ANSI music sequence in b.
+func Find(b ...byte) []string {
+	var mml []string
+	for i := 0; i < len(b); i++ {
+		if b[i] != esc {
+			continue
+		}
+		n, s := sequence(b[i:])
+		if n > 0 {
+			mml = append(mml, s)
+			i += n - 1
+		}
+	}
+	return mml
+}
+
+// Count returns the number of ANSI music sequences in b.
+func Count(b ...byte) int {
+	return len(Find(b...))
+}
+
+// Counter counts the ANSI music sequences of a text written to it in chunks,
+// where a sequence split between the writes is counted once.
+type Counter struct {
+	n     int
+	carry []byte // carry is an incomplete sequence at the end of the last write.
+}
+
+func (c *Counter) Write(p []byte) (int, error) {
+	b := p
+	if len(c.carry) > 0 {
+		b = append(c.carry, p...)
+	}
+	c.carry = nil
+	for i := 0; i < len(b); i++ {
+		if b[i] != esc {
+			continue
+		}
+		n, _ := sequence(b[i:])
+		if n < 0 {
+			c.carry = append([]byte(nil), b[i:]...)
+			break
+		}
+		if n > 0 {
+			c.n++
+			i += n - 1
+		}
+	}
+	return len(p), nil
+}
+
+// Count returns the number of ANSI music sequences written,
+// an unterminated sequence at the end of the text is not counted.
+func (c *Counter) Count() int {
+	return c.n
+}
+
+// Contains reports whether b contains any ANSI music sequences.
+func Contains(b ...byte) bool {
+	return Count(b...) > 0
+}
+
+// Strip returns b with the ANSI music sequences removed.
+func Strip(b ...byte) []byte {
+	p, _, _ := transform.Bytes(NewStripper(), b)
+	return p
+}
+
+// NewStripper returns a transformer that removes the ANSI music sequences.
+func NewStripper() transform.Transformer {
+	return stripper{}
+}
+
+// stripper is the transformer that removes the ANSI music sequences.
+type stripper struct{ transform.NopResetter }
+
+func (stripper) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	nDst, nSrc := 0, 0
+	for nSrc < len(src) {
+		i := bytes.IndexByte(src[nSrc:], esc)
+		if i != 0 {
+			end := len(src)
+			if i > 0 {
+				end = nSrc + i
+			}
+			n := copy(dst[nDst:], src[nSrc:end])
+			nDst, nSrc = nDst+n, nSrc+n
+			if nSrc < end {
+				return nDst, nSrc, transform.ErrShortDst
+			}
+			continue
+		}
+		n, _ := sequence(src[nSrc:])
+		switch {
+		case n > 0:
+			nSrc += n
+			continue
+		case n < 0 && !atEOF:
+			return nDst, nSrc, transform.ErrShortSrc
+		}
+		if nDst == len(dst) {
+			return nDst, nSrc, transform.ErrShortDst
+		}
+		dst[nDst] = esc
+		nDst, nSrc = nDst+1, nSrc+1
+	}
+	return nDst, nSrc, nil
+}
+
+// sequence returns the number of bytes and the music macro language of the
+// ANSI music sequence at the start of b. The number of bytes is zero when b does not
+// start with a sequence, or -1 when more bytes are needed to tell.
+func sequence(b []byte) (int, string) {
+	const introducer = 3
+	switch {
+	case len(b) < introducer:
+		if bytes.HasPrefix([]byte("\x1b[M"), b) || bytes.HasPrefix([]byte("\x1b[N"), b) {
+			return -1, ""
+		}
+		return 0, ""
+	case b[0] != esc || b[1] != '[' || (b[2] != 'M' && b[2] != 'N'):
+		return 0, ""
+	}
+	for i := introducer; i < len(b); i++ {
+		switch {
+		case b[i] == so:
+			mml := string(b[introducer:i])
+			if b[2] == 'M' && mml != "" && strings.ContainsAny(mml[:1], "FBNLSfbnls") {
+				mml = "M" + mml
+			}
+			return i + 1, mml
+		case i >= maxLen, !valid(b[i]):
+			return 0, ""
+		}
+	}
+	return -1, ""
+}
+
+// valid reports whether c is a character of the music macro language.
+func valid(c byte) bool {
+	switch {
+	case c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.IndexByte(" #+-.<>;", c) >= 0
+}
+
+// MIDI file values.
+const (
+	division = 480  // division is the number of ticks per quarter note.
+	program  = 80   // program is the General MIDI square wave lead, which sounds like a PC speaker.
+	velocity = 100  // velocity of the notes.
+	noteOn   = 0x90 // noteOn is the note on event of the first channel.
+	noteOff  = 0x80 // noteOff is the note off event of the first channel.
+	change   = 0xc0 // change is the program change event of the first channel.
+	meta     = 0xff // meta is the meta event.
+	setTempo = 0x51 // setTempo is the type of the tempo meta event.
+	endTrack = 0x2f // endTrack is the type of the end of track meta event.
+	minute   = 60_000_000
+)
+
+// MIDI writes the music macro language of the sequences to w as a standard MIDI file.
+// The sequences are played in order, so the octave, length, tempo and style of
+// a sequence are kept by the following sequences.
+func MIDI(w io.Writer, mml ...string) error {
+	if len(mml) == 0 {
+		return ErrNone
+	}
+	if w == nil {
+		w = io.Discard
+	}
+	p := newPlayer()
+	for _, s := range mml {
+		p.play(s)
+	}
+	track := p.track()
+	b := []byte("MThd")
+	b = binary.BigEndian.AppendUint32(b, 6)
+	b = binary.BigEndian.AppendUint16(b, 0) // single track format
+	b = binary.BigEndian.AppendUint16(b, 1)
+	b = binary.BigEndian.AppendUint16(b, division)
+	b = append(b, "MTrk"...)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(track)))
+	b = append(b, track...)
+	if _, err := w.Write(b); err != nil {
+		return fmt.Errorf("music midi: %w", err)
+	}
+	return nil
+}
+
+// style is the portion of the note length that sounds, the rest is silent.
+type style struct{ num, den int }
+
+var (
+	normal   = style{7, 8}
+	legato   = style{1, 1}
+	staccato = style{3, 4}
+)
+
+// player holds the state of the music macro language and the MIDI events.
+type player struct {
+	octave, length, tempo int
+	style                 style
+	events                []byte
+	wait                  int // wait is the number of ticks since the last event.
+}
+
+func newPlayer() *player {
+	const octave, length, tempo = 4, 4, 120
+	p := &player{octave: octave, length: length, tempo: tempo, style: normal}
+	p.event(meta, setTempo, 3)
+	p.events = append(p.events, tempoBytes(tempo)...)
+	p.event(change, program)
+	return p
+}
+
+// play reads the commands of the music macro language.
+func (p *player) play(mml string) {
+	const maxOctave, maxNote = 6, 84
+	s := &scanner{s: strings.ToUpper(mml)}
+	for !s.done() {
+		c := s.next()
+		switch c {
+		case 'A', 'B', 'C', 'D', 'E', 'F', 'G':
+			key := 24 + 12*p.octave + semitone(c)
+			switch s.peek() {
+			case '#', '+':
+				key++
+				s.next()
+			case '-':
+				key--
+				s.next()
+			}
+			p.note(key, s.length(p.length), s.dots())
+		case 'N':
+			n := min(s.number(0), maxNote)
+			if n == 0 {
+				p.note(0, p.length, s.dots())
+				break
+			}
+			p.note(23+n, p.length, s.dots())
+		case 'O':
+			p.octave = min(s.number(p.octave), maxOctave)
+		case '<':
+			p.octave = max(p.octave-1, 0)
+		case '>':
+			p.octave = min(p.octave+1, maxOctave)
+		case 'L':
+			p.length = s.length(p.length)
+		case 'P', 'R':
+			p.note(0, s.length(p.length), s.dots())
+		case 'T':
+			p.setTempo(s.number(p.tempo))
+		case 'M':
+			switch s.peek() {
+			case 'N':
+				p.style = normal
+			case 'L':
+				p.style = legato
+			case 'S':
+				p.style = staccato
+			case 'F', 'B':
+			default:
+				continue
+			}
+			s.next()
+		}
+	}
+}
+
+// note plays the key using the length and the number of dots, a zero key is a rest.
+func (p *player) note(key, length, dots int) {
+	const maxKey = 127
+	if length < 1 {
+		return
+	}
+	d := 4.0 * division / float64(length)
+	for i, add := 0, d/2; i < dots; i, add = i+1, add/2 {
+		d += add
+	}
+	ticks := int(math.Round(d))
+	if key < 1 || key > maxKey {
+		p.wait += ticks
+		return
+	}
+	sound := ticks * p.style.num / p.style.den
+	p.event(noteOn, byte(key), velocity)
+	p.wait = sound
+	p.event(noteOff, byte(key), 0)
+	p.wait = ticks - sound
+}
+
+// setTempo changes the number of quarter notes per minute.
+func (p *player) setTempo(n int) {
+	const low, high = 32, 255
+	if n < low || n > high || n == p.tempo {
+		return
+	}
+	p.tempo = n
+	p.event(meta, setTempo, 3)
+	p.events = append(p.events, tempoBytes(n)...)
+}
+
+// event appends the event using the ticks waited since the previous event.
+func (p *player) event(b ...byte) {
+	p.events = append(p.events, vlq(p.wait)...)
+	p.events = append(p.events, b...)
+	p.wait = 0
+}
+
+// track returns the events of the track, ending with any remaining rest.
+func (p *player) track() []byte {
+	p.event(meta, endTrack, 0)
+	return p.events
+}
+
+// tempoBytes returns the microseconds per quarter note of the tempo as a 24-bit value.
+func tempoBytes(tempo int) []byte {
+	us := minute / tempo
+	return []byte{byte(us >> 16), byte(us >> 8), byte(us)}
+}
+
+// vlq returns the variable-length quantity of n used by the MIDI delta times.
+func vlq(n int) []byte {
+	b := []byte{byte(n & 0x7f)}
+	for n >>= 7; n > 0; n >>= 7 {
+		b = append([]byte{byte(n&0x7f | 0x80)}, b...)
+	}
+	return b
+}
+
+// semitone returns the semitone of the note name above C.
+func semitone(c byte) int {
+	const scale = "C D EF G A B"
+	return strings.IndexByte(scale, c)
+}
+
+// scanner reads the characters and numbers of the music macro language.
+type scanner struct {
+	s string
+	i int
+}
+
+func (s *scanner) done() bool {
+	return s.i >= len(s.s)
+}
+
+func (s *scanner) next() byte {
+	if s.done() {
+		return 0
+	}
+	c := s.s[s.i]
+	s.i++
+	return c
+}
+
+func (s *scanner) peek() byte {
+	if s.done() {
+		return 0
+	}
+	return s.s[s.i]
+}
+
+// number returns the number at the scanner, or the fallback value when there is no number.
+func (s *scanner) number(fallback int) int {
+	start := s.i
+	for !s.done() && s.peek() >= '0' && s.peek() <= '9' {
+		s.i++
+	}
+	n, err := strconv.Atoi(s.s[start:s.i])
+	if err != nil {
+		return fallback
+	}
+	return n
+}
+
+// length returns the note length at the scanner, or the fallback value when it is not valid.
+func (s *scanner) length(fallback int) int {
+	const shortest = 64
+	n := s.number(fallback)
+	if n < 1 || n > shortest {
+		return fallback
+	}
+	return n
+}
+
+// dots returns the number of periods at the scanner.
+func (s *scanner) dots() int {
+	n := 0
+	for s.peek() == '.' {
+		s.next()
+		n++
+	}
+	return n
+}
@@ -0,0 +1,134 @@
+package music_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/bengarrett/retrotxtgo/music"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/transform"
+)
+
+// midi returns a MIDI file of the track events that follow the default tempo and program change.
+func midi(events ...byte) []byte {
+	track := append([]byte{0, 0xff, 0x51, 3, 0x07, 0xa1, 0x20, 0, 0xc0, 80}, events...)
+	b := []byte("MThd\x00\x00\x00\x06\x00\x00\x00\x01\x01\xe0MTrk")
+	b = append(b, 0, 0, 0, byte(len(track)))
+	return append(b, track...)
+}
+
+func ExampleFind() {
+	b := []byte("\x1b[MFT120 O4 L8 CDE\x0e\x1b[1;31mHello\x1b[NP4 C\x0e")
+	fmt.Printf("%q\n", music.Find(b...))
+	fmt.Printf("%q", music.Strip(b...))
+	// Output: ["MFT120 O4 L8 CDE" "P4 C"]
+	// "\x1b[1;31mHello"
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want int
+	}{
+		{"music", "\x1b[MBcde\x0e", 1},
+		{"alternative", "\x1b[Ncde\x0e\x1b[Mcde\x0e", 2},
+		{"delete line", "\x1b[M\r\nhello", 0},
+		{"delete lines", "\x1b[2M", 0},
+		{"not music", "\x1b[Mhello\r\n\x0e", 0},
+		{"unterminated", "\x1b[Mcde", 0},
+		{"too long", "\x1b[M" + strings.Repeat("c", 3000) + "\x0e", 0},
+		{"text", "hello", 0},
+	}
+	for _, tt := range tests {
+		be.Equal(t, music.Count([]byte(tt.s)...), tt.want)
+	}
+	be.Equal(t, music.Find([]byte("\x1b[MScde\x0e\x1b[Mcde\x0e")...), []string{"MScde", "cde"})
+}
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+	const s = "\x1b[MFT120 O4 L8 CDE\x0e\x1b[1;31mHello\x1b[NP4 C\x0e\x1b[Mcde"
+	for _, size := range []int{1, 2, 5, len(s)} {
+		c := music.Counter{}
+		for p := []byte(s); len(p) > 0; p = p[min(size, len(p)):] {
+			_, err := c.Write(p[:min(size, len(p))])
+			be.Err(t, err, nil)
+		}
+		be.Equal(t, c.Count(), music.Count([]byte(s)...))
+		be.Equal(t, c.Count(), 2)
+	}
+}
+
+func TestStrip(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"music", "a\x1b[MFcde\x0eb", "ab"},
+		{"ansi", "\x1b[M\x1b[0ma", "\x1b[M\x1b[0ma"},
+		{"unterminated", "a\x1b[Mcd", "a\x1b[Mcd"},
+		{"escape at end", "a\x1b", "a\x1b"},
+	}
+	for _, tt := range tests {
+		be.Equal(t, string(music.Strip([]byte(tt.s)...)), tt.want)
+	}
+}
+
+func TestNewStripper(t *testing.T) {
+	t.Parallel()
+	s := strings.Repeat("\x1b[0mhello\x1b[MFT200L16cdefgab>c\x0e world\r\n", 100)
+	r := transform.NewReader(iotest.OneByteReader(strings.NewReader(s)), music.NewStripper())
+	b, err := io.ReadAll(r)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), strings.Repeat("\x1b[0mhello world\r\n", 100))
+}
+
+func TestMIDI(t *testing.T) {
+	t.Parallel()
+	const off = 0x80
+	tests := []struct {
+		name string
+		mml  []string
+		want []byte
+	}{
+		{"quarter note", []string{"T120 L4 C"}, midi(
+			0, 0x90, 72, 100, // middle C is in octave 3
+			0x83, 0x24, off, 72, 0, // the note sounds for 7/8 of 480 ticks
+			60, 0xff, 0x2f, 0,
+		)},
+		{"legato sharp", []string{"ML O3 C#2"}, midi(
+			0, 0x90, 61, 100,
+			0x87, 0x40, off, 61, 0,
+			0, 0xff, 0x2f, 0,
+		)},
+		{"dotted rest", []string{"MS P8. N1"}, midi(
+			0x82, 0x68, 0x90, 24, 100, // a dotted eighth rest is 360 ticks
+			0x82, 0x68, off, 24, 0,
+			0x78, 0xff, 0x2f, 0,
+		)},
+		{"tempo and octaves", []string{"T240 O0<", "L64 >B-"}, midi(
+			0, 0xff, 0x51, 3, 0x03, 0xd0, 0x90,
+			0, 0x90, 46, 100, // the B flat is in octave 1
+			26, off, 46, 0,
+			4, 0xff, 0x2f, 0,
+		)},
+		{"empty", []string{""}, midi(0, 0xff, 0x2f, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b bytes.Buffer
+			be.Err(t, music.MIDI(&b, tt.mml...), nil)
+			be.Equal(t, b.Bytes(), tt.want)
+		})
+	}
+	be.Err(t, music.MIDI(io.Discard), music.ErrNone)
+}
@@ -0,0 +1,321 @@
+// Package reflow wraps text to a display width at the word boundaries.
+//
+// The width of the text is the number of terminal columns it displays,
+// so the East Asian wide characters use two columns, while the combining marks
+// and the ANSI escape sequences use none. The escape sequences are never split.
+package reflow
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+var ErrMode = errors.New("wrap mode is not known")
+
+// Mode is the method used to fit the lines of text to the width.
+type Mode int
+
+const (
+	// Hard wraps the long lines at the word boundaries and keeps every line break.
+	Hard Mode = iota
+	// Soft joins the lines of each paragraph and wraps them again at the word boundaries.
+	// A paragraph ends with a blank line or the following line being indented.
+	Soft
+	// Truncate cuts off the long lines at the width.
+	Truncate
+)
+
+const (
+	esc     = 0x1b // esc is the escape control code.
+	csi     = '['  // csi is the control sequence introducer that follows an escape.
+	tabStop = 8    // tabStop is the number of columns between the horizontal tab stops.
+)
+
+// Modes returns the names of the wrap modes.
+func Modes() []string {
+	return []string{"hard", "soft", "truncate"}
+}
+
+// ParseMode returns the named wrap mode, an empty name is the Hard mode.
+func ParseMode(name string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "hard", "wrap":
+		return Hard, nil
+	case "soft", "reflow":
+		return Soft, nil
+	case "truncate", "cut":
+		return Truncate, nil
+	}
+	return Hard, fmt.Errorf("%w: %q", ErrMode, name)
+}
+
+// RuneWidth returns the number of columns used to display the rune.
+func RuneWidth(r rune) int {
+	switch {
+	case r < ' ', r == 0x7f, unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	}
+	switch width.LookupRune(r).Kind() {
+	case width.EastAsianWide, width.EastAsianFullwidth:
+		return 2 //nolint:mnd
+	case width.Neutral, width.EastAsianAmbiguous, width.EastAsianHalfwidth, width.EastAsianNarrow:
+	}
+	return 1
+}
+
+// Width returns the number of columns used to display the runes,
+// where the ANSI escape sequences are not counted.
+// It expects a single line of text.
+func Width(r ...rune) int {
+	n := 0
+	for i := 0; i < len(r); i++ {
+		if size := sequence(r[i:]); size > 0 {
+			i += size - 1
+			continue
+		}
+		n += RuneWidth(r[i])
+	}
+	return n
+}
+
+// StringWidth returns the number of columns used to display the string,
+// where the ANSI escape sequences are not counted.
+func StringWidth(s string) int {
+	return Width([]rune(s)...)
+}
+
+// Fit pads the string with spaces or truncates it to fill the width.
+func Fit(width int, s string) string {
+	if n := StringWidth(s); n <= width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	t := truncate(width, []rune(s))
+	return t + strings.Repeat(" ", width-StringWidth(t))
+}
+
+// Wrap fits the lines of the runes to the width using the mode.
+// The tabs are expanded to spaces and the line breaks become line feeds.
+// A width of less than 1 returns the runes as is.
+func Wrap(width int, mode Mode, r ...rune) []rune {
+	if width < 1 || len(r) == 0 {
+		return r
+	}
+	lines := strings.Split(strings.ReplaceAll(string(r), "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	switch mode {
+	case Soft:
+		for _, p := range paragraphs(lines...) {
+			out = append(out, wrap(width, p)...)
+		}
+	case Truncate:
+		for _, line := range lines {
+			out = append(out, truncate(width, []rune(expand(line))))
+		}
+	case Hard:
+		for _, line := range lines {
+			out = append(out, wrap(width, expand(line))...)
+		}
+	}
+	return []rune(strings.Join(out, "\n"))
+}
+
+// paragraphs joins the lines of each paragraph using single spaces, the blank lines are kept.
+// The indentation of the first line of a paragraph is kept.
+func paragraphs(lines ...string) []string {
+	out := []string{}
+	open := false
+	for _, line := range lines {
+		line = expand(line)
+		s := strings.TrimSpace(line)
+		switch {
+		case Width([]rune(s)...) == 0:
+			out = append(out, line)
+			open = false
+		case !open, line != strings.TrimLeft(line, " "):
+			out = append(out, strings.TrimRight(line, " "))
+			open = true
+		default:
+			out[len(out)-1] += " " + s
+		}
+	}
+	return out
+}
+
+// token is a word or the spaces between the words, including any escape sequences.
+type token struct {
+	r     []rune
+	width int
+	space bool
+}
+
+// tokens splits the line into the words and the spaces.
+// The escape sequences belong to the token that follows them.
+func tokens(line []rune) []token {
+	ts := []token{}
+	cur := token{}
+	for i := 0; i < len(line); i++ {
+		if size := sequence(line[i:]); size > 0 {
+			cur.r = append(cur.r, line[i:i+size]...)
+			i += size - 1
+			continue
+		}
+		space := line[i] == ' '
+		if cur.width > 0 && space != cur.space {
+			ts = append(ts, cur)
+			cur = token{}
+		}
+		cur.r = append(cur.r, line[i])
+		cur.width += RuneWidth(line[i])
+		cur.space = space
+	}
+	if len(cur.r) > 0 {
+		ts = append(ts, cur)
+	}
+	return ts
+}
+
+// wrap breaks the line into lines of the width at the spaces between the words,
+// the words that are longer than the width are broken at the width.
+func wrap(width int, line string) []string {
+	r := []rune(line)
+	if Width(r...) <= width {
+		return []string{line}
+	}
+	out := []string{}
+	cur, col := []rune{}, 0
+	for _, t := range tokens(r) {
+		switch {
+		case t.space && col+t.width > width:
+			// the spaces at the end of a line are dropped, but not the escape sequences
+			out = append(out, string(append(cur, controls(t.r)...)))
+			cur, col = []rune{}, 0
+			continue
+		case t.space, col+t.width <= width:
+			cur, col = append(cur, t.r...), col+t.width
+			continue
+		case col > 0:
+			out = append(out, string(trimSpace(cur)))
+			cur, col = []rune{}, 0
+		}
+		parts := split(width, t.r)
+		for _, x := range parts[:len(parts)-1] {
+			out = append(out, string(x))
+		}
+		cur, col = parts[len(parts)-1], Width(parts[len(parts)-1]...)
+	}
+	return append(out, string(trimSpace(cur)))
+}
+
+// split breaks the word into the parts that fit the width,
+// where the escape sequences are kept with the following rune.
+func split(width int, r []rune) [][]rune {
+	parts := [][]rune{}
+	cur, col := []rune{}, 0
+	for i := 0; i < len(r); i++ {
+		if size := sequence(r[i:]); size > 0 {
+			cur = append(cur, r[i:i+size]...)
+			i += size - 1
+			continue
+		}
+		w := RuneWidth(r[i])
+		if col+w > width && Width(cur...) > 0 {
+			parts = append(parts, cur)
+			cur, col = []rune{}, 0
+		}
+		cur = append(cur, r[i])
+		col += w
+	}
+	if len(cur) > 0 {
+		parts = append(parts, cur)
+	}
+	return parts
+}
+
+// truncate cuts off the line at the width, while keeping all the escape sequences.
+func truncate(width int, r []rune) string {
+	out := make([]rune, 0, len(r))
+	col := 0
+	for i := 0; i < len(r); i++ {
+		if size := sequence(r[i:]); size > 0 {
+			out = append(out, r[i:i+size]...)
+			i += size - 1
+			continue
+		}
+		w := RuneWidth(r[i])
+		if col+w > width {
+			continue
+		}
+		out = append(out, r[i])
+		col += w
+	}
+	return string(out)
+}
+
+// trimSpace removes the spaces at the end of the line.
+func trimSpace(r []rune) []rune {
+	for len(r) > 0 && r[len(r)-1] == ' ' {
+		r = r[:len(r)-1]
+	}
+	return r
+}
+
+// controls returns the escape sequences of the runes.
+func controls(r []rune) []rune {
+	out := []rune{}
+	for i := 0; i < len(r); i++ {
+		if size := sequence(r[i:]); size > 0 {
+			out = append(out, r[i:i+size]...)
+			i += size - 1
+		}
+	}
+	return out
+}
+
+// expand replaces the horizontal tabs of the line with the spaces to the next tab stop.
+func expand(line string) string {
+	if !strings.ContainsRune(line, '\t') {
+		return line
+	}
+	sb := strings.Builder{}
+	col := 0
+	r := []rune(line)
+	for i := 0; i < len(r); i++ {
+		if size := sequence(r[i:]); size > 0 {
+			sb.WriteString(string(r[i : i+size]))
+			i += size - 1
+			continue
+		}
+		if r[i] == '\t' {
+			n := tabStop - col%tabStop
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		sb.WriteRune(r[i])
+		col += RuneWidth(r[i])
+	}
+	return sb.String()
+}
+
+// sequence returns the number of runes of the ANSI escape sequence at the start of r,
+// or zero when r does not start with a complete sequence.
+func sequence(r []rune) int {
+	const paramLow, interHigh, finalLow, finalHigh = 0x20, 0x3f, 0x40, 0x7e
+	if len(r) < 2 || r[0] != esc || r[1] != csi { //nolint:mnd
+		return 0
+	}
+	for i := 2; i < len(r); i++ {
+		switch {
+		case r[i] >= paramLow && r[i] <= interHigh:
+			continue
+		case r[i] >= finalLow && r[i] <= finalHigh:
+			return i + 1
+		}
+		return 0
+	}
+	return 0
+}
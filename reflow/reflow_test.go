@@ -0,0 +1,112 @@
+package reflow_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/reflow"
+	"github.com/nalgeon/be"
+)
+
+func ExampleWrap() {
+	s := "The quick brown fox\njumps over the lazy dog.\n\nThe end."
+	fmt.Println(string(reflow.Wrap(12, reflow.Soft, []rune(s)...)))
+	// Output: The quick
+	// brown fox
+	// jumps over
+	// the lazy
+	// dog.
+	//
+	// The end.
+}
+
+func ExampleWidth() {
+	fmt.Println(reflow.Width([]rune("\x1b[1;31mhello\x1b[0m")...))
+	fmt.Println(reflow.Width([]rune("日本語")...))
+	// Output: 5
+	// 6
+}
+
+func ExampleFit() {
+	fmt.Printf("[%s]\n", reflow.Fit(6, "日本語"))
+	fmt.Printf("[%s]\n", reflow.Fit(5, "日本語"))
+	// Output: [日本語]
+	// [日本 ]
+}
+
+func TestParseMode(t *testing.T) {
+	t.Parallel()
+	for _, name := range reflow.Modes() {
+		_, err := reflow.ParseMode(name)
+		be.Err(t, err, nil)
+	}
+	m, err := reflow.ParseMode("")
+	be.Err(t, err, nil)
+	be.Equal(t, m, reflow.Hard)
+	m, err = reflow.ParseMode("Truncate")
+	be.Err(t, err, nil)
+	be.Equal(t, m, reflow.Truncate)
+	_, err = reflow.ParseMode("justify")
+	be.Err(t, err, reflow.ErrMode)
+}
+
+func TestRuneWidth(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		r    rune
+		want int
+	}{
+		{"ascii", 'a', 1},
+		{"box drawing", '╬', 1},
+		{"wide", '日', 2},
+		{"fullwidth", 'Ａ', 2},
+		{"halfwidth", 'ｱ', 1},
+		{"combining", '́', 0},
+		{"control", '\x1b', 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, reflow.RuneWidth(tt.r), tt.want)
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	t.Parallel()
+	const red, reset = "\x1b[31m", "\x1b[0m"
+	tests := []struct {
+		name  string
+		width int
+		mode  reflow.Mode
+		s     string
+		want  string
+	}{
+		{"no width", 0, reflow.Hard, "abc def", "abc def"},
+		{"fits", 10, reflow.Hard, "abc def", "abc def"},
+		{"words", 7, reflow.Hard, "abc def ghi", "abc def\nghi"},
+		{"line breaks", 7, reflow.Hard, "abc def ghi\r\njkl", "abc def\nghi\njkl"},
+		{"paragraphs", 7, reflow.Hard, "abc\n\ndef", "abc\n\ndef"},
+		{"long word", 3, reflow.Hard, "abcdefgh", "abc\ndef\ngh"},
+		{"long word after", 4, reflow.Hard, "ab cdefgh", "ab\ncdef\ngh"},
+		{"spaces", 3, reflow.Hard, "ab    cd", "ab\ncd"},
+		{"tab", 10, reflow.Hard, "a\tb", "a       b"},
+		{"wide", 5, reflow.Hard, "日本語", "日本\n語"},
+		{"escapes", 5, reflow.Hard, red + "abc" + reset + " def", red + "abc" + reset + "\ndef"},
+		{"escapes not split", 3, reflow.Hard, "ab" + red + "cdef", "ab" + red + "c\ndef"},
+		{"soft", 7, reflow.Soft, "abc\ndef\nghi jkl", "abc def\nghi jkl"},
+		{"soft paragraphs", 10, reflow.Soft, "abc\ndef\n\nghi\njkl", "abc def\n\nghi jkl"},
+		{"soft indent", 10, reflow.Soft, "abc\n  def\nghi", "abc\n  def ghi"},
+		{"truncate", 3, reflow.Truncate, "abcdef\nghi jkl", "abc\nghi"},
+		{"truncate escapes", 2, reflow.Truncate, red + "abc" + reset, red + "ab" + reset},
+		{"truncate wide", 3, reflow.Truncate, "日本語", "日"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := reflow.Wrap(tt.width, tt.mode, []rune(tt.s)...)
+			be.Equal(t, string(got), tt.want)
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package render
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/unicode/norm"
+)
+
+var ErrFont = errors.New("bitmap font is not known")
+
+// bitmaps are the raw font files, each file contains 256 glyphs in the code page 437 order
+// and each glyph is a sequence of bytes with one byte per row of 8 pixels.
+// The text files are the sheets of the extra glyphs drawn for the Latin, Greek and Cyrillic
+// letters and the punctuation of the other code pages.
+//
+//go:embed font/vga.f16 font/cga.f08 font/vga.txt font/cga.txt
+var bitmaps embed.FS
+
+// Font is a bitmap font of the IBM PC text modes.
+type Font struct {
+	Name   string          // Name of the font.
+	Width  int             // Width of a glyph in pixels.
+	Height int             // Height of a glyph in pixels.
+	bitmap []byte          // bitmap is the raw font data.
+	extra  map[rune][]byte // extra are the glyphs of the runes not found in code page 437.
+}
+
+const (
+	glyphs     = 256 // glyphs is the number of glyphs in a font.
+	glyphWidth = 8   // glyphWidth is the width of a glyph in pixels, which is one byte per row.
+)
+
+var (
+	vga = sync.OnceValue(func() Font { return load("vga", 16, "font/vga.f16", "font/vga.txt") })
+	cga = sync.OnceValue(func() Font { return load("cga", 8, "font/cga.f08", "font/cga.txt") })
+)
+
+// VGA returns the 8x16 font of the IBM VGA text mode.
+func VGA() Font {
+	return vga()
+}
+
+// CGA returns the 8x8 font of the IBM CGA text mode.
+func CGA() Font {
+	return cga()
+}
+
+// load returns the font using the embedded bitmap and the sheet of extra glyphs.
+func load(name string, height int, bitmap, sheet string) Font {
+	b, _ := bitmaps.ReadFile(bitmap)
+	s, _ := bitmaps.ReadFile(sheet)
+	f := Font{Name: name, Width: glyphWidth, Height: height, bitmap: b}
+	f.extra = extras(f, string(s))
+	return f
+}
+
+// Open returns the named font, either "vga" or "cga".
+func Open(name string) (Font, error) {
+	switch strings.ToLower(name) {
+	case "vga", "v", "":
+		return VGA(), nil
+	case "cga", "c":
+		return CGA(), nil
+	}
+	return Font{}, fmt.Errorf("%w: %s", ErrFont, name)
+}
+
+// Glyph returns the rows of the glyph at the code page 437 index.
+// The most significant bit of each row is the leftmost pixel.
+func (f Font) Glyph(i byte) []byte {
+	if len(f.bitmap) != glyphs*f.Height {
+		return make([]byte, f.Height)
+	}
+	n := int(i) * f.Height
+	return f.bitmap[n : n+f.Height]
+}
+
+// Rune returns the rows of the glyph of the rune using the glyph index m of the code page 437 runes,
+// and reports whether the glyph is one of the line graphics that extend into the ninth column.
+// Runes that are not found in code page 437 use the extra glyphs of the font,
+// otherwise the glyph of Index is returned.
+func (f Font) Rune(m map[rune]byte, r rune) ([]byte, bool) {
+	const lineFirst, lineLast = 0xc0, 0xdf
+	if i, ok := m[r]; ok {
+		return f.Glyph(i), i >= lineFirst && i <= lineLast
+	}
+	if g, ok := f.extra[r]; ok && len(g) == f.Height {
+		return g, false
+	}
+	return f.Glyph(Index(m, r)), false
+}
+
+// Runes returns the glyph index of the Unicode runes found in code page 437.
+// This includes the picture glyphs that are shown in place of the C0 controls.
+func Runes() map[rune]byte {
+	ctrls := []rune("␀☺☻♥♦♣♠•◘○◙♂♀♪♫☼►◄↕‼¶§▬↨↑↓→←∟↔▲▼")
+	m := make(map[rune]byte, glyphs+len(ctrls))
+	for i := range glyphs {
+		m[charmap.CodePage437.DecodeByte(byte(i))] = byte(i)
+	}
+	for i, r := range ctrls {
+		m[r] = byte(i)
+	}
+	const house, broken, nul = 0x7f, 0x7c, 0x00
+	m['⌂'] = house
+	m['¦'] = broken
+	m['␠'] = nul
+	return m
+}
+
+// Index returns the code page 437 glyph index of the rune.
+// Runes that are not found in the font use the glyph of their base letter,
+// otherwise the question mark glyph is returned.
+func Index(m map[rune]byte, r rune) byte {
+	if i, ok := m[r]; ok {
+		return i
+	}
+	for _, x := range norm.NFD.String(string(r)) {
+		if unicode.Is(unicode.Mn, x) {
+			continue
+		}
+		if i, ok := m[x]; ok {
+			return i
+		}
+		break
+	}
+	return '?'
+}
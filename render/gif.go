@@ -0,0 +1,99 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"iter"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+)
+
+var ErrGIF = errors.New("could not encode the gif image")
+
+// Hold is the number of hundredths of a second that the last frame of an animation
+// is shown before it loops.
+const Hold = 300
+
+// GIF writes the frames of the rows of cells as an animated GIF image, where each frame
+// is shown for the delay in hundredths of a second. Only the area that changes is
+// stored for each frame, and the frames without any changes extend the previous delay.
+// The colors use the VGA palette followed by the 256 color xterm values.
+func GIF(w io.Writer, o Options, delay int, frames iter.Seq[[][]ansi.Cell]) error {
+	if w == nil {
+		w = io.Discard
+	}
+	pal := Palette()
+	g := &gif.GIF{}
+	var prev *image.Paletted
+	for rows := range frames {
+		img := Image(o, rows...)
+		p := image.NewPaletted(img.Bounds(), pal)
+		draw.Draw(p, p.Rect, img, image.Point{}, draw.Src)
+		r := changed(prev, p)
+		if r.Empty() {
+			if n := len(g.Delay); n > 0 {
+				g.Delay[n-1] += delay
+			}
+			continue
+		}
+		frame := image.NewPaletted(r, pal)
+		draw.Draw(frame, r, p, r.Min, draw.Src)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, delay)
+		g.Config.Width = max(g.Config.Width, r.Max.X)
+		g.Config.Height = max(g.Config.Height, r.Max.Y)
+		prev = p
+	}
+	if len(g.Image) == 0 {
+		return fmt.Errorf("%w: there are no frames", ErrGIF)
+	}
+	g.Delay[len(g.Delay)-1] += Hold
+	g.Config.ColorModel = pal
+	if err := gif.EncodeAll(w, g); err != nil {
+		return fmt.Errorf("%w: %w", ErrGIF, err)
+	}
+	return nil
+}
+
+// Palette returns the 256 colors of the GIF images,
+// the VGA palette followed by the other xterm colors.
+func Palette() color.Palette {
+	const colors = 256
+	vga := ansi.VGA()
+	p := make(color.Palette, 0, colors)
+	for _, c := range vga {
+		p = append(p, c)
+	}
+	for i := len(vga); i < colors; i++ {
+		p = append(p, ansi.Xterm(uint8(i)))
+	}
+	return p
+}
+
+// changed returns the area of the next image that differs from the previous image,
+// where any area outside of the previous image is a change.
+func changed(prev, next *image.Paletted) image.Rectangle {
+	if prev == nil {
+		return next.Rect
+	}
+	b := next.Rect
+	x0, y0, x1, y1 := b.Max.X, b.Max.Y, b.Min.X, b.Min.Y
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if image.Pt(x, y).In(prev.Rect) && prev.ColorIndexAt(x, y) == next.ColorIndexAt(x, y) {
+				continue
+			}
+			x0, y0 = min(x0, x), min(y0, y)
+			x1, y1 = max(x1, x+1), max(y1, y+1)
+		}
+	}
+	if x0 >= x1 {
+		return image.Rectangle{}
+	}
+	return image.Rect(x0, y0, x1, y1)
+}
@@ -0,0 +1,440 @@
+package render
+
+import (
+	"math/bits"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// alike are the runes drawn using the glyph of another rune with the same shape,
+// such as the Cyrillic and Greek letters that look like Latin letters,
+// or the combining diacritical marks that use their spacing mark.
+var alike = map[rune]rune{
+	// Cyrillic
+	'А': 'A', 'В': 'B', 'Г': 'Γ', 'Е': 'E', 'З': '3', 'К': 'K', 'М': 'M', 'Н': 'H',
+	'О': 'O', 'Р': 'P', 'С': 'C', 'Т': 'T', 'Ф': 'Φ', 'Х': 'X', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J',
+	'а': 'a', 'е': 'e', 'о': 'o', 'р': 'p', 'с': 'c', 'у': 'y', 'х': 'x', 'ѕ': 's',
+	'і': 'i', 'ј': 'j', 'ћ': 'ħ',
+	// Greek
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Π': 'П', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+	'β': 'ß', 'ι': 'ı', 'κ': 'к', 'μ': 'µ', 'ν': 'v', 'ο': 'o', 'ϕ': 'φ', '΄': '´',
+	// Latin
+	'Ð': 'Đ', 'ĸ': 'к',
+	// punctuation and symbols
+	'\u00ad': '-', '–': '-', '―': '—', '‚': ',', '⁄': '/', '∆': 'Δ', '∏': 'П', '∑': 'Σ',
+	'╭': '┌', '╮': '┐', '╯': '┘', '╰': '└', '▶': '►', '◀': '◄',
+	// combining diacritical marks
+	'\u0300': '`', '\u0301': '´', '\u0302': 'ˆ', '\u0303': '˜', '\u0304': '¯', '\u0306': '˘',
+	'\u0307': '˙', '\u0308': '¨', '\u030a': '˚', '\u030b': '˝', '\u030c': 'ˇ', '\u0327': '¸',
+	'\u0328': '˛',
+}
+
+// composed are the ranges of the precomposed letters that are drawn
+// using the glyphs of their base letter and diacritical marks.
+var composed = [][2]rune{{0x00c0, 0x024f}, {0x0370, 0x03ff}, {0x0400, 0x04ff}, {0x1e00, 0x1eff}}
+
+// dotless are the letters that lose their dot when drawn with a mark above.
+var dotless = map[rune]rune{'i': 'ı', 'j': 'ȷ', 'і': 'ı', 'ј': 'ȷ'}
+
+const (
+	caron = '\u030c' // caron is drawn as an apostrophe beside the ascenders of ď, ľ, ť and Ľ.
+	horn  = '\u031b' // horn is attached to the top right of the letter.
+	ogonk = '\u0328' // ogonk is the ogonek that hooks the bottom right of the letter.
+)
+
+// metrics are the rows of a font used to place the diacritical marks.
+type metrics struct {
+	capTop   int // capTop is the first row of the capital letters.
+	xTop     int // xTop is the first row of the small letters.
+	baseline int // baseline is the last row of the letters without descenders.
+}
+
+// builder draws the glyphs of the runes that are not found in code page 437.
+type builder struct {
+	font   Font
+	cp     map[rune]byte
+	glyphs map[rune][]byte
+	m      metrics
+}
+
+// extras returns the glyphs of the font for the runes that are not found in code page 437.
+// The glyphs are read from the sheet, copied from the look-alike runes,
+// generated for the block elements, or composed from a base letter and its diacritical marks.
+func extras(f Font, s string) map[rune][]byte {
+	b := builder{font: f, cp: Runes(), glyphs: sheet(s, f.Height)}
+	capTop, baseline := extent(b.glyph('H'))
+	xTop, _ := extent(b.glyph('x'))
+	b.m = metrics{capTop: capTop, xTop: xTop, baseline: baseline}
+	b.dotless()
+	b.blocks()
+	for r := range alike {
+		if _, ok := b.cp[r]; ok {
+			continue
+		}
+		if g, ok := b.get(r); ok {
+			b.glyphs[r] = g
+		}
+	}
+	for _, rng := range composed {
+		for r := rng[0]; r <= rng[1]; r++ {
+			if _, ok := b.get(r); ok {
+				continue
+			}
+			if g, ok := b.compose(r); ok {
+				b.glyphs[r] = g
+			}
+		}
+	}
+	return b.glyphs
+}
+
+// sheet parses the glyphs drawn in the text s. Each block of lines is separated by
+// an empty line and starts with the runes of the glyphs, written as characters
+// or in the U+ notation. Each following line is a row of the glyphs,
+// with a field of 8 pixels per glyph where a '#' is a set pixel.
+func sheet(s string, height int) map[rune][]byte {
+	m := make(map[rune][]byte)
+	for block := range strings.SplitSeq(strings.TrimSpace(s), "\n\n") {
+		lines := strings.Split(block, "\n")
+		if len(lines) != height+1 {
+			continue
+		}
+		names := strings.Fields(lines[0])
+		glyphs := make([][]byte, len(names))
+		for i := range glyphs {
+			glyphs[i] = make([]byte, height)
+		}
+		for y, line := range lines[1:] {
+			for i, px := range strings.Fields(line) {
+				if i >= len(glyphs) {
+					break
+				}
+				for x, c := range px {
+					if c == '#' && x < glyphWidth {
+						glyphs[i][y] |= 0x80 >> x
+					}
+				}
+			}
+		}
+		for i, name := range names {
+			r, _ := utf8.DecodeRuneInString(name)
+			if hex, ok := strings.CutPrefix(name, "U+"); ok {
+				n, err := strconv.ParseUint(hex, 16, 32)
+				if err != nil {
+					continue
+				}
+				r = rune(n)
+			}
+			m[r] = glyphs[i]
+		}
+	}
+	return m
+}
+
+// get returns the glyph of the rune from the font, the drawn glyphs or a look-alike rune.
+func (b *builder) get(r rune) ([]byte, bool) {
+	if i, ok := b.cp[r]; ok {
+		return b.font.Glyph(i), true
+	}
+	if g, ok := b.glyphs[r]; ok {
+		return g, true
+	}
+	if a, ok := alike[r]; ok {
+		return b.get(a)
+	}
+	return nil, false
+}
+
+// glyph returns the glyph of the rune, or an empty glyph when it is not found.
+func (b *builder) glyph(r rune) []byte {
+	if g, ok := b.get(r); ok {
+		return g
+	}
+	return make([]byte, b.font.Height)
+}
+
+// dotless draws the dotless i and j by removing their dots above the small letters.
+func (b *builder) dotless() {
+	for r, base := range map[rune]rune{'ı': 'i', 'ȷ': 'j'} {
+		if _, ok := b.glyphs[r]; ok {
+			continue
+		}
+		g := slices.Clone(b.glyph(base))
+		clear(g[:min(max(b.m.xTop, 0), len(g))])
+		b.glyphs[r] = g
+	}
+}
+
+// blocks generates the block elements, quadrants and the half and diagonal lines
+// using the height of the font and the box drawing lines of code page 437.
+func (b *builder) blocks() {
+	const full, lhalf, rhalf = 0xff, 0xf0, 0x0f
+	h := b.font.Height
+	eighth, half := h/8, h/2
+	rect := func(top, bottom int, px byte) []byte {
+		g := make([]byte, h)
+		for y := top; y < bottom; y++ {
+			g[y] = px
+		}
+		return g
+	}
+	or := func(glyphs ...[]byte) []byte {
+		g := make([]byte, h)
+		for _, x := range glyphs {
+			for y := range g {
+				g[y] |= x[y]
+			}
+		}
+		return g
+	}
+	ul, ur, ll, lr := rect(0, half, lhalf), rect(0, half, rhalf), rect(half, h, lhalf), rect(half, h, rhalf)
+	// the half lines use the rows and columns of the box drawing lines
+	horiz, vert := b.glyph('─'), b.glyph('│')
+	top, bottom := extent(horiz)
+	var col byte
+	for _, px := range vert {
+		col |= px
+	}
+	left, right := byte(full<<bits.TrailingZeros8(col)), byte(full>>bits.LeadingZeros8(col))
+	west, east, north, south := make([]byte, h), make([]byte, h), make([]byte, h), make([]byte, h)
+	rise, fall := make([]byte, h), make([]byte, h)
+	lowerRight, lowerLeft, upperLeft, upperRight := make([]byte, h), make([]byte, h), make([]byte, h), make([]byte, h)
+	for y := range h {
+		west[y], east[y] = horiz[y]&left, horiz[y]&right
+		if y <= bottom {
+			north[y] = vert[y]
+		}
+		if y >= top {
+			south[y] = vert[y]
+		}
+		x := y * (glyphWidth - 1) / max(h-1, 1)
+		fall[y], rise[y] = 0x80>>x, 0x80>>(glyphWidth-1-x)
+		lowerLeft[y], upperRight[y] = full<<(glyphWidth-1-x), full>>x
+		upperLeft[y], lowerRight[y] = full<<x, full>>(glyphWidth-1-x)
+	}
+	for r, g := range map[rune][]byte{
+		'▁': rect(h-eighth, h, full), '▂': rect(h-2*eighth, h, full), '▃': rect(h-3*eighth, h, full),
+		'▔': rect(0, eighth, full), '▏': rect(0, h, 0x80), '▎': rect(0, h, 0xc0), '▍': rect(0, h, 0xe0),
+		'▕': rect(0, h, 0x01), '▘': ul, '▝': ur, '▖': ll, '▗': lr,
+		'▚': or(ul, lr), '▞': or(ur, ll), '▙': or(ul, ll, lr), '▛': or(ul, ur, ll),
+		'▜': or(ul, ur, lr), '▟': or(ur, ll, lr),
+		'╴': west, '╶': east, '╵': north, '╷': south,
+		'╱': rise, '╲': fall, '╳': or(rise, fall),
+		'◢': lowerRight, '◣': lowerLeft, '◤': upperLeft, '◥': upperRight,
+	} {
+		if _, ok := b.glyphs[r]; !ok {
+			b.glyphs[r] = g
+		}
+	}
+}
+
+// compose draws the precomposed rune using the glyph of its base letter,
+// with the diacritical marks drawn above or below the letter.
+// The tall letters are squeezed to make room for the marks above.
+func (b *builder) compose(r rune) ([]byte, bool) {
+	d := []rune(norm.NFD.String(string(r)))
+	if len(d) < 2 {
+		return nil, false
+	}
+	base, marks := d[0], d[1:]
+	g, ok := b.get(base)
+	if !ok {
+		return nil, false
+	}
+	for _, mk := range marks {
+		if !unicode.Is(unicode.Mn, mk) {
+			return nil, false
+		}
+		if _, ok := b.get(mk); !ok {
+			return nil, false
+		}
+	}
+	var up, down [][]byte
+	var side []rune
+	for _, mk := range marks {
+		mg := b.glyph(mk)
+		top, _ := extent(mg)
+		switch {
+		case mk == horn, mk == caron && strings.ContainsRune("dltL", base):
+			side = append(side, mk)
+		case top > b.m.baseline:
+			if _, bottom := extent(g); bottom > b.m.baseline {
+				// the letters with descenders use a turned comma above, such as ģ
+				up = append(up, b.glyph('‘'))
+				continue
+			}
+			down = append(down, mg)
+		default:
+			up = append(up, mg)
+		}
+	}
+	if i, ok := dotless[base]; ok && len(up) > 0 {
+		g = b.glyph(i)
+	}
+	g = slices.Clone(g)
+	left, right := span(g)
+	if len(down) > 0 {
+		g = b.below(g, left, right, marks, down)
+	}
+	if len(up) > 0 {
+		g = b.above(g, left, right, up)
+	}
+	for _, mk := range side {
+		g = b.beside(g, mk)
+	}
+	return g, true
+}
+
+// above draws the marks above the letter, with the first mark nearest to the letter.
+func (b *builder) above(g []byte, left, right int, marks [][]byte) []byte {
+	var mark []byte
+	for _, mg := range marks {
+		l, r := span(mg)
+		mark = append(shift(trim(mg), (left+right-l-r)/2), mark...)
+	}
+	top, bottom := extent(g)
+	end := max(b.m.xTop-2, len(mark)-1)
+	if top < b.m.xTop {
+		// the tall letters are squeezed to leave an empty row below the mark
+		const gap = 1
+		end = max(b.m.capTop-1, len(mark)-1)
+		g = squeeze(g, top, min(bottom, b.m.baseline), end+1+gap-top)
+	}
+	for i, px := range mark {
+		if y := end - len(mark) + 1 + i; y >= 0 && y < len(g) {
+			g[y] |= px
+		}
+	}
+	return g
+}
+
+// below draws the marks below the baseline, where the ogonek hooks the right of the letter.
+func (b *builder) below(g []byte, left, right int, marks []rune, down [][]byte) []byte {
+	y := b.m.baseline + 1
+	ogonek := slices.Contains(marks, ogonk)
+	for _, mg := range down {
+		l, r := span(mg)
+		n := (left + right - l - r) / 2
+		if ogonek {
+			n = right - r
+		}
+		for _, px := range shift(trim(mg), n) {
+			if y < len(g) {
+				g[y] |= px
+			}
+			y++
+		}
+	}
+	return g
+}
+
+// beside draws the horn at the top right of the letter,
+// or the caron as a thin apostrophe in the last column beside the ascender.
+func (b *builder) beside(g []byte, mk rune) []byte {
+	top, _ := extent(g)
+	if mk == caron {
+		const last, gap = 0x01, 0x02
+		for y := top; y < min(top+len(trim(b.glyph('’'))), len(g)); y++ {
+			g[y] = g[y]&^gap | last
+		}
+		return g
+	}
+	_, right := span(g)
+	mg := b.glyph(horn)
+	l, _ := span(mg)
+	y := top - len(trim(mg)) + 1
+	for _, px := range shift(trim(mg), right-l) {
+		if y >= 0 && y < len(g) {
+			g[y] |= px
+		}
+		y++
+	}
+	return g
+}
+
+// squeeze removes n rows from the letter between the top and last rows, keeping the last row
+// in place. The removed rows are those most like the row above, nearest to the middle.
+func squeeze(g []byte, top, last, n int) []byte {
+	if n <= 0 || top < 0 || last < top {
+		return g
+	}
+	body := slices.Clone(g[top : last+1])
+	for range n {
+		if len(body) <= 2 {
+			break
+		}
+		k, best, mid := 0, 0, len(body)/2
+		for i := 1; i < len(body)-1; i++ {
+			d := bits.OnesCount8(body[i] ^ body[i-1])
+			if k == 0 || d < best || (d == best && abs(i-mid) < abs(k-mid)) {
+				k, best = i, d
+			}
+		}
+		body = slices.Delete(body, k, k+1)
+	}
+	clear(g[top : last+1])
+	copy(g[last+1-len(body):], body)
+	return g
+}
+
+// extent returns the first and last rows of the glyph with a set pixel, or -1 for an empty glyph.
+func extent(g []byte) (int, int) {
+	first, last := -1, -1
+	for y, px := range g {
+		if px == 0 {
+			continue
+		}
+		if first < 0 {
+			first = y
+		}
+		last = y
+	}
+	return first, last
+}
+
+// span returns the first and last columns of the glyph with a set pixel.
+func span(g []byte) (int, int) {
+	var col byte
+	for _, px := range g {
+		col |= px
+	}
+	if col == 0 {
+		return 0, glyphWidth - 1
+	}
+	return bits.LeadingZeros8(col), glyphWidth - 1 - bits.TrailingZeros8(col)
+}
+
+// trim returns the rows of the glyph between the first and last rows with a set pixel.
+func trim(g []byte) []byte {
+	first, last := extent(g)
+	if first < 0 {
+		return nil
+	}
+	return g[first : last+1]
+}
+
+// shift returns a copy of the rows moved n pixels to the right, or to the left when n is negative.
+func shift(rows []byte, n int) []byte {
+	s := make([]byte, len(rows))
+	for i, px := range rows {
+		if n >= 0 {
+			s[i] = px >> n
+			continue
+		}
+		s[i] = px << -n
+	}
+	return s
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
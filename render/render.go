@@ -0,0 +1,131 @@
+// Package render rasterises text and text art into images using the bitmap fonts of the IBM PC.
+package render
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+)
+
+var ErrPNG = errors.New("could not encode the png image")
+
+// Options for the rendered image.
+type Options struct {
+	Font    Font // Font is the bitmap font used to draw the glyphs.
+	Columns int  // Columns is the minimum number of characters per row, usually the SAUCE character width.
+	Nine    bool // Nine uses the 9 pixel letter spacing of the VGA text mode.
+	Aspect  bool // Aspect stretches the image to the 4:3 display aspect ratio of a legacy CRT monitor.
+	ICE     bool // ICE uses the blink attribute for bright background colors.
+}
+
+// PNG writes the rows of cells as a PNG image.
+func PNG(w io.Writer, o Options, rows ...[]ansi.Cell) error {
+	if w == nil {
+		w = io.Discard
+	}
+	if err := png.Encode(w, Image(o, rows...)); err != nil {
+		return fmt.Errorf("%w: %w", ErrPNG, err)
+	}
+	return nil
+}
+
+// Image draws the rows of cells using the font and the VGA palette.
+// The blink attribute is ignored unless ICE is used, as a still image cannot blink.
+func Image(o Options, rows ...[]ansi.Cell) *image.RGBA {
+	if o.Font.Height == 0 {
+		o.Font = VGA()
+	}
+	cols := max(o.Columns, 1)
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	w, h := o.Font.Width, o.Font.Height
+	if o.Nine {
+		w++
+	}
+	img := image.NewRGBA(image.Rect(0, 0, cols*w, max(len(rows), 1)*h))
+	p, m := ansi.VGA(), Runes()
+	fill(img, img.Bounds(), p[0])
+	for y, row := range rows {
+		for x, c := range row {
+			cell(img, image.Pt(x*w, y*h), o, p, m, c)
+		}
+	}
+	if o.Aspect {
+		return stretch(img, Aspect(o.Font, w))
+	}
+	return img
+}
+
+// Aspect returns the vertical scale that displays a screen of 80 columns and 25 rows
+// in the 4:3 aspect ratio of a legacy CRT monitor, using the font with the cell width in pixels.
+func Aspect(f Font, width int) float64 {
+	const columns, rows, ratio = 80, 25, 3.0 / 4.0
+	if f.Height == 0 {
+		return 1
+	}
+	return ratio * float64(columns*width) / float64(rows*f.Height)
+}
+
+// cell draws the glyph of the character at point pt using its display attributes.
+func cell(img *image.RGBA, pt image.Point, o Options, p ansi.Palette, m map[rune]byte, c ansi.Cell) {
+	a := c.Attr
+	fg, bg := a.Colors(o.ICE)
+	fc, _ := p.RGBA(fg)
+	bc, _ := p.RGBA(bg)
+	w := o.Font.Width
+	if o.Nine {
+		w++
+	}
+	fill(img, image.Rect(pt.X, pt.Y, pt.X+w, pt.Y+o.Font.Height), bc)
+	if a.Has(ansi.Conceal) {
+		return
+	}
+	glyph, line := o.Font.Rune(m, c.Rune)
+	for y, bits := range glyph {
+		for x := range o.Font.Width {
+			if bits&(0x80>>x) != 0 {
+				img.SetRGBA(pt.X+x, pt.Y+y, fc)
+			}
+		}
+		if o.Nine && line && bits&1 != 0 {
+			img.SetRGBA(pt.X+o.Font.Width, pt.Y+y, fc)
+		}
+	}
+	if a.Has(ansi.Underline) {
+		fill(img, image.Rect(pt.X, pt.Y+o.Font.Height-1, pt.X+w, pt.Y+o.Font.Height), fc)
+	}
+	if a.Has(ansi.Strike) {
+		y := pt.Y + o.Font.Height/2 - 1
+		fill(img, image.Rect(pt.X, y, pt.X+w, y+1), fc)
+	}
+}
+
+// fill draws the rectangle using the color.
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// stretch returns a copy of the image with the height scaled using the nearest pixel row.
+func stretch(src *image.RGBA, scale float64) *image.RGBA {
+	b := src.Bounds()
+	h := int(float64(b.Dy())*scale + 0.5)
+	if h < 1 || h == b.Dy() {
+		return src
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), h))
+	for y := range h {
+		sy := y * b.Dy() / h
+		copy(dst.Pix[y*dst.Stride:(y+1)*dst.Stride], src.Pix[sy*src.Stride:(sy+1)*src.Stride])
+	}
+	return dst
+}
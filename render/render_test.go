@@ -0,0 +1,200 @@
+package render_test
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"iter"
+	"testing"
+	"unicode"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/render"
+	"github.com/bengarrett/retrotxtgo/table"
+	"github.com/nalgeon/be"
+)
+
+func rows(s string) [][]ansi.Cell {
+	scr := ansi.New(0)
+	scr.Runes([]rune(s)...)
+	return scr.Rows()
+}
+
+func ExampleImage() {
+	img := render.Image(render.Options{Font: render.VGA()}, rows("Hello\r\nworld")...)
+	fmt.Println(img.Bounds().Dx(), "x", img.Bounds().Dy())
+	// Output: 40 x 32
+}
+
+func ExampleIndex() {
+	m := render.Runes()
+	fmt.Println(render.Index(m, '░'), render.Index(m, 'é'), render.Index(m, 'Ā'), render.Index(m, 'Ж'))
+	// Output: 176 130 65 63
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+	f, err := render.Open("")
+	be.Err(t, err, nil)
+	be.Equal(t, f.Height, 16)
+	f, err = render.Open("CGA")
+	be.Err(t, err, nil)
+	be.Equal(t, f.Height, 8)
+	_, err = render.Open("ega")
+	be.Err(t, err, render.ErrFont)
+}
+
+func TestGlyph(t *testing.T) {
+	t.Parallel()
+	for _, f := range []render.Font{render.VGA(), render.CGA()} {
+		be.Equal(t, f.Glyph(0), make([]byte, f.Height))
+		be.Equal(t, f.Glyph(0xdb), bytes.Repeat([]byte{0xff}, f.Height))
+		be.Equal(t, len(f.Glyph(0xff)), f.Height)
+	}
+	be.Equal(t, len(render.Font{Height: 4}.Glyph('A')), 4)
+}
+
+func TestRunes(t *testing.T) {
+	t.Parallel()
+	m := render.Runes()
+	tests := []struct {
+		r    rune
+		want byte
+	}{
+		{'A', 0x41}, {'☺', 0x01}, {'▼', 0x1f}, {'⌂', 0x7f}, {'Ç', 0x80},
+		{'█', 0xdb}, {'■', 0xfe}, {' ', 0xff}, {'¦', 0x7c},
+	}
+	for _, tt := range tests {
+		be.Equal(t, render.Index(m, tt.r), tt.want)
+	}
+}
+
+func TestRune(t *testing.T) {
+	t.Parallel()
+	m := render.Runes()
+	for _, f := range []render.Font{render.VGA(), render.CGA()} {
+		question := f.Glyph('?')
+		// the Latin, Greek and Cyrillic letters of the 8-bit code pages are all drawn
+		for _, e := range table.Charmaps() {
+			d := e.NewDecoder()
+			for i := 0x20; i < 0x100; i++ {
+				b, err := d.Bytes([]byte{byte(i)})
+				if err != nil {
+					continue
+				}
+				for _, r := range string(b) {
+					if !unicode.In(r, unicode.Latin, unicode.Greek, unicode.Cyrillic) {
+						continue
+					}
+					g, _ := f.Rune(m, r)
+					if bytes.Equal(g, question) {
+						t.Errorf("%s font has no glyph for %q of %s", f.Name, r, e)
+					}
+				}
+			}
+		}
+		for _, r := range "€–—‘’“”„†‡…‰‹›™©®×¤№≠∂◊▂▚╱" {
+			g, _ := f.Rune(m, r)
+			be.Equal(t, bytes.Equal(g, question), false)
+		}
+		// look-alike letters share their glyphs
+		g, line := f.Rune(m, 'А')
+		be.Equal(t, g, f.Glyph('A'))
+		be.Equal(t, line, false)
+		g, _ = f.Rune(m, 'Ж')
+		be.Equal(t, len(g), f.Height)
+		// the composed letters are not their base letter
+		g, _ = f.Rune(m, 'Ā')
+		be.Equal(t, bytes.Equal(g, f.Glyph('A')), false)
+		g, _ = f.Rune(m, 'ą')
+		be.Equal(t, bytes.Equal(g, f.Glyph('a')), false)
+		// only the code page 437 line graphics extend into the ninth column
+		_, line = f.Rune(m, '─')
+		be.True(t, line)
+		_, line = f.Rune(m, '╴')
+		be.Equal(t, line, false)
+		// the other scripts use the question mark
+		g, _ = f.Rune(m, 'א')
+		be.Equal(t, g, question)
+	}
+}
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+	white := color.RGBA{0xff, 0xff, 0xff, 0xff}
+	blue := color.RGBA{0x00, 0x00, 0xaa, 0xff}
+	// a bright white full block on a blue background
+	img := render.Image(render.Options{Columns: 2}, rows("\x1b[1;37;44m█ ")...)
+	be.Equal(t, img.Bounds().Dx(), 16)
+	be.Equal(t, img.Bounds().Dy(), 16)
+	be.Equal(t, img.RGBAAt(0, 0), white)
+	be.Equal(t, img.RGBAAt(15, 15), blue)
+	// the line graphics extend into the ninth column, but letters do not
+	img = render.Image(render.Options{Font: render.CGA(), Nine: true}, rows("─H")...)
+	be.Equal(t, img.Bounds().Dx(), 18)
+	be.Equal(t, img.Bounds().Dy(), 8)
+	gray := color.RGBA{0xaa, 0xaa, 0xaa, 0xff}
+	be.Equal(t, img.RGBAAt(8, 4), gray)
+	be.Equal(t, img.RGBAAt(17, 3), color.RGBA{0x00, 0x00, 0x00, 0xff})
+	// legacy aspect ratio of 720x400 displayed as 720x540
+	img = render.Image(render.Options{Columns: 80, Nine: true, Aspect: true}, rows("x")...)
+	be.Equal(t, img.Bounds().Dx(), 720)
+	be.Equal(t, img.Bounds().Dy(), 22)
+	// ice colors
+	img = render.Image(render.Options{ICE: true}, rows("\x1b[5;44m ")...)
+	be.Equal(t, img.RGBAAt(0, 0), color.RGBA{0x55, 0x55, 0xff, 0xff})
+	// no rows
+	img = render.Image(render.Options{})
+	be.Equal(t, img.Bounds().Dx(), 8)
+	be.Equal(t, img.Bounds().Dy(), 16)
+}
+
+func TestAspect(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, render.Aspect(render.VGA(), 8), 1.2)
+	be.Equal(t, render.Aspect(render.VGA(), 9), 1.35)
+	be.Equal(t, render.Aspect(render.CGA(), 8), 2.4)
+	be.Equal(t, render.Aspect(render.Font{}, 8), 1.0)
+}
+
+func TestPNG(t *testing.T) {
+	t.Parallel()
+	b := &bytes.Buffer{}
+	err := render.PNG(b, render.Options{}, rows("hello")...)
+	be.Err(t, err, nil)
+	img, err := png.Decode(b)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Bounds().Dx(), 40)
+	err = render.PNG(nil, render.Options{})
+	be.Err(t, err, nil)
+}
+
+func TestGIF(t *testing.T) {
+	t.Parallel()
+	frames := func(s ...string) iter.Seq[[][]ansi.Cell] {
+		return func(yield func([][]ansi.Cell) bool) {
+			for _, x := range s {
+				if !yield(rows(x)) {
+					return
+				}
+			}
+		}
+	}
+	b := &bytes.Buffer{}
+	err := render.GIF(b, render.Options{}, 5, frames("ab", "ab", "ac", "ac\r\nd"))
+	be.Err(t, err, nil)
+	g, err := gif.DecodeAll(b)
+	be.Err(t, err, nil)
+	be.Equal(t, g.Config.Width, 16)
+	be.Equal(t, g.Config.Height, 32)
+	be.Equal(t, len(g.Image), 3)
+	be.Equal(t, g.Delay, []int{10, 5, 5 + render.Hold})
+	// only the second character changed, then the new row was added
+	be.Equal(t, g.Image[1].Bounds().Min.X >= 8, true)
+	be.Equal(t, g.Image[2].Bounds().Min.Y >= 16, true)
+	err = render.GIF(b, render.Options{}, 5, frames())
+	be.Err(t, err, render.ErrGIF)
+	be.Equal(t, len(render.Palette()), 256)
+}
@@ -0,0 +1,335 @@
+// Package saucer reads and writes the SAUCE metadata records of text and art files.
+//
+// SAUCE, the Standard Architecture for Universal Comment Extensions, is a 128 byte
+// record appended to a file, with an optional COMNT block of comments placed before it.
+// The record and block follow the end-of-file (0x1A) separator, so they are hidden
+// when the text is displayed using DOS or the byter.TrimEOF function.
+// https://www.acid.org/info/sauce/sauce.htm
+package saucer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bengarrett/retrotxtgo/byter"
+	"github.com/bengarrett/retrotxtgo/convert"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	ErrComments = errors.New("too many comment lines")
+	ErrDate     = errors.New("date must use the CCYYMMDD format")
+	ErrEOF      = errors.New("the end-of-file separator is missing")
+	ErrNone     = errors.New("no SAUCE record found")
+	ErrWidth    = errors.New("field is too long")
+)
+
+const (
+	ID       = "SAUCE" // ID is the identity of the SAUCE record.
+	Version  = "00"    // Version of the SAUCE record.
+	CommntID = "COMNT" // CommntID is the identity of the comment block.
+	Size     = 128     // Size of the SAUCE record in bytes.
+	LineSize = 64      // LineSize is the size of each comment line in bytes.
+	MaxLines = 255     // MaxLines is the maximum number of comment lines.
+	Layout   = "20060102"
+)
+
+// The widths of the text fields in bytes.
+const (
+	TitleWidth  = 35
+	AuthorWidth = 20
+	GroupWidth  = 20
+	DateWidth   = 8
+	FontWidth   = 22
+)
+
+// The offsets of the fields within the SAUCE record.
+const (
+	offTitle    = 7
+	offAuthor   = offTitle + TitleWidth
+	offGroup    = offAuthor + AuthorWidth
+	offDate     = offGroup + GroupWidth
+	offSize     = offDate + DateWidth
+	offDataType = offSize + 4
+	offFileType = offDataType + 1
+	offTInfo    = offFileType + 1
+	offComments = offTInfo + 8
+	offFlags    = offComments + 1
+	offFont     = offFlags + 1
+)
+
+// Data types of the SAUCE record.
+const (
+	None      uint8 = iota // None is an undefined file.
+	Character              // Character is a text or ANSI art file.
+)
+
+// File types of the character data type.
+const (
+	ASCII uint8 = iota // ASCII is a plain text file.
+	ANSI               // ANSI is a text file with ANSI escape controls.
+)
+
+// NonBlink is the flag of the character data type that uses the blink attribute
+// for the bright background colors, known as iCE colors.
+const NonBlink uint8 = 1
+
+// Record is the SAUCE metadata, with the text fields as UTF-8.
+type Record struct {
+	Title    string    // Title of the work.
+	Author   string    // Author is the handle or name of the creator.
+	Group    string    // Group is the name of the group or company.
+	Date     string    // Date of creation using the CCYYMMDD format.
+	FileSize uint32    // FileSize is the size of the file without the SAUCE metadata.
+	DataType uint8     // DataType is the type of data.
+	FileType uint8     // FileType is the type of file of the data type.
+	TInfo    [4]uint16 // TInfo are the numeric type information fields, such as the character width.
+	Flags    uint8     // Flags are the type dependent flags, such as the iCE colors and letter-spacing.
+	Font     string    // Font is the name of the font used to display the text.
+	Comments []string  // Comments are the lines of the comment block.
+}
+
+// New returns a new record for the text using the date of today.
+// Text with ANSI escape controls is an ANSI file type, otherwise it is ASCII.
+func New(b ...byte) Record {
+	const columns = 80
+	r := Record{
+		Date:     time.Now().Format(Layout),
+		DataType: Character,
+		FileType: ASCII,
+	}
+	r.TInfo[0] = columns
+	if bytes.Contains(b, []byte{0x1b, '['}) {
+		r.FileType = ANSI
+	}
+	return r
+}
+
+// Index returns the index of the SAUCE metadata in b, including any comment block and end-of-file separator.
+// If there is no SAUCE record, then -1 is returned.
+func Index(b ...byte) int {
+	i := index(b...)
+	if i < 0 {
+		return -1
+	}
+	if lines := int(b[i+offComments]); lines > 0 {
+		c := i - len(CommntID) - lines*LineSize
+		if c >= 0 && string(b[c:c+len(CommntID)]) == CommntID {
+			i = c
+		}
+	}
+	if i > 0 && b[i-1] == byter.SUB {
+		i--
+	}
+	return i
+}
+
+// index returns the index of the SAUCE record in b, or -1.
+// The record must use the last 128 bytes of b.
+func index(b ...byte) int {
+	i := len(b) - Size
+	if i < 0 || !bytes.HasPrefix(b[i:], []byte(ID+Version)) {
+		return -1
+	}
+	return i
+}
+
+// ICE reports whether the record sets the non-blink flag for iCE colors.
+func (r Record) ICE() bool {
+	return r.DataType == Character && r.Flags&NonBlink != 0
+}
+
+// Read returns the SAUCE record found in b.
+func Read(b ...byte) (Record, error) {
+	i := index(b...)
+	if i < 0 {
+		return Record{}, ErrNone
+	}
+	p := b[i : i+Size]
+	r := Record{
+		Title:    text(p[offTitle:offAuthor]...),
+		Author:   text(p[offAuthor:offGroup]...),
+		Group:    text(p[offGroup:offDate]...),
+		Date:     text(p[offDate:offSize]...),
+		FileSize: binary.LittleEndian.Uint32(p[offSize:offDataType]),
+		DataType: p[offDataType],
+		FileType: p[offFileType],
+		Flags:    p[offFlags],
+		Font:     text(p[offFont:Size]...),
+	}
+	for n := range r.TInfo {
+		const size = 2
+		x := offTInfo + n*size
+		r.TInfo[n] = binary.LittleEndian.Uint16(p[x : x+size])
+	}
+	lines := int(p[offComments])
+	c := i - len(CommntID) - lines*LineSize
+	if lines == 0 || c < 0 || string(b[c:c+len(CommntID)]) != CommntID {
+		return r, nil
+	}
+	for n := range lines {
+		x := c + len(CommntID) + n*LineSize
+		r.Comments = append(r.Comments, text(b[x:x+LineSize]...))
+	}
+	return r, nil
+}
+
+// Strip returns b without any SAUCE metadata.
+func Strip(b ...byte) []byte {
+	i := Index(b...)
+	if i < 0 {
+		return b
+	}
+	return b[:i]
+}
+
+// Write returns b with the SAUCE record, replacing any existing metadata.
+// The file size of the record is set to the size of b without the metadata,
+// and a single end-of-file separator is always placed before the metadata.
+func Write(r Record, b ...byte) ([]byte, error) {
+	content := bytes.TrimSuffix(Strip(b...), []byte{byter.SUB})
+	r.FileSize = uint32(min(len(content), int(^uint32(0)))) //nolint:gosec
+	meta, err := r.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	p := make([]byte, 0, len(content)+1+len(meta))
+	p = append(p, content...)
+	p = append(p, byter.SUB)
+	p = append(p, meta...)
+	if err := Validate(p...); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate returns an error if the SAUCE metadata in b is not found
+// or is not preceded by the end-of-file separator.
+func Validate(b ...byte) error {
+	i := Index(b...)
+	if i < 0 {
+		return ErrNone
+	}
+	if b[i] != byter.SUB {
+		return ErrEOF
+	}
+	return nil
+}
+
+// Bytes returns the comment block and SAUCE record, without the end-of-file separator.
+// An error is returned if a field is too long or the date is invalid.
+func (r Record) Bytes() ([]byte, error) {
+	if err := r.Valid(); err != nil {
+		return nil, err
+	}
+	p := make([]byte, Size)
+	copy(p, ID+Version)
+	fields := []struct {
+		s      string
+		offset int
+		width  int
+		pad    byte
+	}{
+		{r.Title, offTitle, TitleWidth, ' '},
+		{r.Author, offAuthor, AuthorWidth, ' '},
+		{r.Group, offGroup, GroupWidth, ' '},
+		{r.Date, offDate, DateWidth, ' '},
+		{r.Font, offFont, FontWidth, 0},
+	}
+	for _, f := range fields {
+		s, _ := cp437(f.s)
+		copy(p[f.offset:], pad(f.width, f.pad, s...))
+	}
+	binary.LittleEndian.PutUint32(p[offSize:], r.FileSize)
+	p[offDataType] = r.DataType
+	p[offFileType] = r.FileType
+	for n, v := range r.TInfo {
+		const size = 2
+		binary.LittleEndian.PutUint16(p[offTInfo+n*size:], v)
+	}
+	p[offComments] = uint8(len(r.Comments)) //nolint:gosec
+	p[offFlags] = r.Flags
+	if len(r.Comments) == 0 {
+		return p, nil
+	}
+	c := []byte(CommntID)
+	for _, line := range r.Comments {
+		s, _ := cp437(line)
+		c = append(c, pad(LineSize, ' ', s...)...)
+	}
+	return append(c, p...), nil
+}
+
+// Valid returns an error if a field of the record is too long, cannot be encoded or the date is invalid.
+func (r Record) Valid() error {
+	fields := []struct {
+		name  string
+		s     string
+		width int
+	}{
+		{"title", r.Title, TitleWidth},
+		{"author", r.Author, AuthorWidth},
+		{"group", r.Group, GroupWidth},
+		{"font", r.Font, FontWidth},
+	}
+	for _, f := range fields {
+		if err := width(f.name, f.s, f.width); err != nil {
+			return err
+		}
+	}
+	if r.Date != "" {
+		if _, err := time.Parse(Layout, r.Date); err != nil || len(r.Date) != DateWidth {
+			return fmt.Errorf("%w: %q", ErrDate, r.Date)
+		}
+	}
+	if len(r.Comments) > MaxLines {
+		return fmt.Errorf("%w: %d lines, the maximum is %d", ErrComments, len(r.Comments), MaxLines)
+	}
+	for i, line := range r.Comments {
+		if err := width(fmt.Sprintf("comment line %d", i+1), line, LineSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// width returns an error if the text cannot be encoded or is longer than w bytes.
+func width(name, s string, w int) error {
+	b, err := cp437(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if len(b) > w {
+		return fmt.Errorf("%w: %s is %d characters, the maximum is %d", ErrWidth, name, len(b), w)
+	}
+	return nil
+}
+
+// cp437 encodes the UTF-8 text as Code Page 437 which is used by the SAUCE text fields.
+func cp437(s string) ([]byte, error) {
+	b, err := convert.Encode(charmap.CodePage437, convert.Strict, []byte(s)...)
+	if err != nil {
+		return nil, fmt.Errorf("saucer: %w", err)
+	}
+	return b, nil
+}
+
+// text decodes the Code Page 437 field as UTF-8 and removes the padding.
+func text(b ...byte) string {
+	s, err := charmap.CodePage437.NewDecoder().Bytes(b)
+	if err != nil {
+		s = b
+	}
+	return strings.TrimRight(string(s), " \x00")
+}
+
+// pad returns b padded to the width using the pad byte.
+func pad(w int, pad byte, b ...byte) []byte {
+	p := bytes.Repeat([]byte{pad}, w)
+	copy(p, b)
+	return p
+}
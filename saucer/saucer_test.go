@@ -0,0 +1,137 @@
+package saucer_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/saucer"
+	"github.com/nalgeon/be"
+)
+
+func ExampleWrite() {
+	r := saucer.Record{
+		Title:  "Hello world",
+		Author: "Ben",
+		Date:   "19960131",
+	}
+	b, _ := saucer.Write(r, []byte("hello world")...)
+	x, _ := saucer.Read(b...)
+	fmt.Println(len(b), x.Title, x.Author, x.Date, x.FileSize)
+	// Output: 140 Hello world Ben 19960131 11
+}
+
+func TestRoundTrip(t *testing.T) {
+	t.Parallel()
+	text := []byte("\x1b[0mhello\r\nworld")
+	r := saucer.New(text...)
+	be.Equal(t, r.FileType, saucer.ANSI)
+	r.Title = "Ça va"
+	r.Group = "Group"
+	r.Flags = 1
+	r.Comments = []string{"first", "second"}
+	b, err := saucer.Write(r, text...)
+	be.Err(t, err, nil)
+	be.Equal(t, len(b), len(text)+1+len(saucer.CommntID)+2*saucer.LineSize+saucer.Size)
+	be.Err(t, saucer.Validate(b...), nil)
+	x, err := saucer.Read(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, x.Title, "Ça va")
+	be.Equal(t, x.Group, "Group")
+	be.Equal(t, x.TInfo[0], uint16(80))
+	be.Equal(t, x.Flags, uint8(1))
+	be.True(t, x.ICE())
+	be.True(t, !saucer.New(text...).ICE())
+	be.Equal(t, x.FileSize, uint32(len(text)))
+	be.Equal(t, x.Comments, []string{"first", "second"})
+	be.Equal(t, saucer.Strip(b...), text)
+}
+
+func TestEmbeddedID(t *testing.T) {
+	t.Parallel()
+	body := []byte("a text about the " + saucer.ID + saucer.Version + " record\r\n" + strings.Repeat("x", 300))
+	_, err := saucer.Read(body...)
+	be.Err(t, err, saucer.ErrNone)
+	be.Equal(t, saucer.Strip(body...), body)
+	b, err := saucer.Write(saucer.Record{Title: "set"}, body...)
+	be.Err(t, err, nil)
+	be.Equal(t, saucer.Strip(b...), body)
+	x, err := saucer.Read(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, x.Title, "set")
+	be.Equal(t, x.FileSize, uint32(len(body)))
+	// a title that contains the record id
+	b, err = saucer.Write(saucer.Record{Title: saucer.ID + saucer.Version}, []byte("hello")...)
+	be.Err(t, err, nil)
+	x, err = saucer.Read(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, x.Title, saucer.ID+saucer.Version)
+	be.Equal(t, saucer.Strip(b...), []byte("hello"))
+	b, err = saucer.Write(saucer.Record{Title: "again"}, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, saucer.Strip(b...), []byte("hello"))
+}
+
+func TestWriteReplace(t *testing.T) {
+	t.Parallel()
+	text := []byte("hello\x1a")
+	b, err := saucer.Write(saucer.Record{Title: "one", Comments: []string{"a"}}, text...)
+	be.Err(t, err, nil)
+	b, err = saucer.Write(saucer.Record{Title: "two"}, b...)
+	be.Err(t, err, nil)
+	be.Equal(t, len(b), len("hello")+1+saucer.Size)
+	be.Equal(t, bytes.Count(b, []byte{0x1a}), 1)
+	x, err := saucer.Read(b...)
+	be.Err(t, err, nil)
+	be.Equal(t, x.Title, "two")
+	be.Equal(t, len(x.Comments), 0)
+	be.Equal(t, x.FileSize, uint32(5))
+}
+
+func TestValid(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		r    saucer.Record
+		want error
+	}{
+		{"empty", saucer.Record{}, nil},
+		{"title", saucer.Record{Title: strings.Repeat("x", saucer.TitleWidth)}, nil},
+		{"long title", saucer.Record{Title: strings.Repeat("x", saucer.TitleWidth+1)}, saucer.ErrWidth},
+		{"long author", saucer.Record{Author: strings.Repeat("x", saucer.AuthorWidth+1)}, saucer.ErrWidth},
+		{"long group", saucer.Record{Group: strings.Repeat("x", saucer.GroupWidth+1)}, saucer.ErrWidth},
+		{"date", saucer.Record{Date: "20240229"}, nil},
+		{"bad date", saucer.Record{Date: "2024-2-1"}, saucer.ErrDate},
+		{"invalid date", saucer.Record{Date: "20230229"}, saucer.ErrDate},
+		{"comment", saucer.Record{Comments: []string{strings.Repeat("x", saucer.LineSize)}}, nil},
+		{"long comment", saucer.Record{Comments: []string{strings.Repeat("x", saucer.LineSize+1)}}, saucer.ErrWidth},
+		{"lines", saucer.Record{Comments: make([]string, saucer.MaxLines+1)}, saucer.ErrComments},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Err(t, tt.r.Valid(), tt.want)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	be.Err(t, saucer.Validate([]byte("hello")...), saucer.ErrNone)
+	b, err := saucer.Write(saucer.Record{}, []byte("hello")...)
+	be.Err(t, err, nil)
+	be.Err(t, saucer.Validate(b...), nil)
+	b = append([]byte("hello"), b[len("hello")+1:]...)
+	be.Err(t, saucer.Validate(b...), saucer.ErrEOF)
+}
+
+func TestRead(t *testing.T) {
+	t.Parallel()
+	_, err := saucer.Read([]byte("hello")...)
+	be.Err(t, err, saucer.ErrNone)
+	_, err = saucer.Read([]byte("hello\x1aSAUCE00")...)
+	be.Err(t, err, saucer.ErrNone)
+	be.Equal(t, saucer.Index([]byte("hello")...), -1)
+	be.Equal(t, saucer.Strip([]byte("hello")...), []byte("hello"))
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"github.com/bengarrett/retrotxtgo/micro"
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"github.com/bengarrett/retrotxtgo/xud"
 	"github.com/charmbracelet/lipgloss"
 	"golang.org/x/text/encoding"
@@ -35,57 +38,97 @@ func Languages() *Lang {
 		usa = "English, US"
 	)
 	lang := Lang{
-		unicode.UTF8:            "Unicode, all major languages",
-		charmap.CodePage037:     usa,
-		charmap.CodePage437:     usa,
-		charmap.CodePage850:     weu,
-		charmap.CodePage852:     cels,
-		charmap.CodePage855:     "Central Europe Cyrillic script",
-		charmap.CodePage858:     weu + eur,
-		charmap.CodePage860:     "Portuguese",
-		charmap.CodePage862:     heb,
-		charmap.CodePage863:     "French Canadian",
-		charmap.CodePage865:     "Danish, Norwegian",
-		charmap.CodePage866:     "USSR Cyrillic script",
-		charmap.CodePage1047:    weu,
-		charmap.CodePage1140:    usa,
-		charmap.ISO8859_1:       weu,
-		charmap.ISO8859_2:       cels,
-		charmap.ISO8859_3:       "Esperanto, Maltese, Turkish",
-		charmap.ISO8859_4:       "Estonian, Latvian, Lithuanian, Greenlandic, Sámi",
-		charmap.ISO8859_5:       "Russian Cyrillic script",
-		charmap.ISO8859_6:       arb,
-		charmap.ISO8859_6E:      arb,
-		charmap.ISO8859_6I:      arb,
-		charmap.ISO8859_7:       "Greek",
-		charmap.ISO8859_8:       heb,
-		charmap.ISO8859_8E:      heb,
-		charmap.ISO8859_8I:      heb,
-		charmap.ISO8859_9:       "Turkish",
-		charmap.ISO8859_10:      "Nordic languages",
-		xud.XUserDefinedISO11:   "Thai", // ISO-8859-11
-		charmap.ISO8859_13:      "Baltic languages",
-		charmap.ISO8859_14:      "Celtic languages",
-		charmap.ISO8859_15:      weu + eur,
-		charmap.ISO8859_16:      "Gaj's Latin alphabet for European languages",
-		charmap.KOI8R:           "Russian, Bulgarian",
-		charmap.KOI8U:           "Ukrainian",
-		charmap.Macintosh:       weu,
-		charmap.Windows874:      "Thai",
-		charmap.Windows1250:     cels,
-		charmap.Windows1251:     "Cyrillic script",
-		charmap.Windows1252:     "English, " + weu,
-		charmap.Windows1253:     "Greek",
-		charmap.Windows1254:     "Turkish",
-		charmap.Windows1255:     heb,
-		charmap.Windows1256:     arb,
-		charmap.Windows1257:     "Estonian, Latvian, Lithuanian",
-		charmap.Windows1258:     "Vietnamese",
-		japanese.ShiftJIS:       "Japanese",
-		traditionalchinese.Big5: "Traditional Chinese",
-		xud.XUserDefined1963:    usa,
-		xud.XUserDefined1965:    usa,
-		xud.XUserDefined1967:    usa,
+		unicode.UTF8:              "Unicode, all major languages",
+		charmap.CodePage037:       usa,
+		charmap.CodePage437:       usa,
+		charmap.CodePage850:       weu,
+		charmap.CodePage852:       cels,
+		charmap.CodePage855:       "Central Europe Cyrillic script",
+		charmap.CodePage858:       weu + eur,
+		charmap.CodePage860:       "Portuguese",
+		charmap.CodePage862:       heb,
+		charmap.CodePage863:       "French Canadian",
+		charmap.CodePage865:       "Danish, Norwegian",
+		charmap.CodePage866:       "USSR Cyrillic script",
+		charmap.CodePage1047:      weu,
+		charmap.CodePage1140:      usa,
+		charmap.ISO8859_1:         weu,
+		charmap.ISO8859_2:         cels,
+		charmap.ISO8859_3:         "Esperanto, Maltese, Turkish",
+		charmap.ISO8859_4:         "Estonian, Latvian, Lithuanian, Greenlandic, Sámi",
+		charmap.ISO8859_5:         "Russian Cyrillic script",
+		charmap.ISO8859_6:         arb,
+		charmap.ISO8859_6E:        arb,
+		charmap.ISO8859_6I:        arb,
+		charmap.ISO8859_7:         "Greek",
+		charmap.ISO8859_8:         heb,
+		charmap.ISO8859_8E:        heb,
+		charmap.ISO8859_8I:        heb,
+		charmap.ISO8859_9:         "Turkish",
+		charmap.ISO8859_10:        "Nordic languages",
+		xud.XUserDefinedISO11:     "Thai", // ISO-8859-11
+		charmap.ISO8859_13:        "Baltic languages",
+		charmap.ISO8859_14:        "Celtic languages",
+		charmap.ISO8859_15:        weu + eur,
+		charmap.ISO8859_16:        "Gaj's Latin alphabet for European languages",
+		charmap.KOI8R:             "Russian, Bulgarian",
+		charmap.KOI8U:             "Ukrainian",
+		charmap.Macintosh:         weu,
+		charmap.Windows874:        "Thai",
+		charmap.Windows1250:       cels,
+		charmap.Windows1251:       "Cyrillic script",
+		charmap.Windows1252:       "English, " + weu,
+		charmap.Windows1253:       "Greek",
+		charmap.Windows1254:       "Turkish",
+		charmap.Windows1255:       heb,
+		charmap.Windows1256:       arb,
+		charmap.Windows1257:       "Estonian, Latvian, Lithuanian",
+		charmap.Windows1258:       "Vietnamese",
+		japanese.ShiftJIS:         "Japanese",
+		traditionalchinese.Big5:   "Traditional Chinese",
+		xud.XUserDefined1963:      usa,
+		xud.XUserDefined1965:      usa,
+		xud.XUserDefined1967:      usa,
+		xud.XUserDefined737:       "Greek",
+		xud.XUserDefined775:       "Estonian, Latvian, Lithuanian",
+		xud.XUserDefined857:       "Turkish",
+		xud.XUserDefined861:       "Icelandic",
+		xud.XUserDefined864:       arb,
+		xud.XUserDefined869:       "Greek",
+		xud.XUserDefinedKamenicky: "Czech, Slovak",
+		xud.XUserDefinedMazovia:   "Polish",
+		xud.XUserDefinedMIK:       "Bulgarian",
+		xud.XUserDefined273:       "German",
+		xud.XUserDefined277:       "Danish, Norwegian",
+		xud.XUserDefined278:       "Finnish, Swedish",
+		xud.XUserDefined280:       "Italian",
+		xud.XUserDefined284:       "Spanish",
+		xud.XUserDefined285:       "English, UK",
+		xud.XUserDefined297:       "French",
+		xud.XUserDefined500:       weu,
+		xud.XUserDefined871:       "Icelandic",
+		xud.XUserDefined1141:      "German" + eur,
+		xud.XUserDefined1142:      "Danish, Norwegian" + eur,
+		xud.XUserDefined1143:      "Finnish, Swedish" + eur,
+		xud.XUserDefined1144:      "Italian" + eur,
+		xud.XUserDefined1145:      "Spanish" + eur,
+		xud.XUserDefined1146:      "English, UK" + eur,
+		xud.XUserDefined1147:      "French" + eur,
+		xud.XUserDefined1148:      weu + eur,
+		xud.XUserDefined1149:      "Icelandic" + eur,
+		micro.PETSCII:             "English, Commodore 64",
+		micro.PETSCIIShifted:      "English, Commodore 64",
+		micro.ScreenCodes:         "English, Commodore 64",
+		micro.ScreenCodesShifted:  "English, Commodore 64",
+		micro.ATASCII:             "English, Atari 8-bit",
+		micro.AtariST:             "English, German, Hebrew",
+		micro.ZXSpectrum:          "English, UK",
+		micro.AmstradCPC:          "English, UK",
+	}
+	for _, m := range mapping.All() {
+		if m.Language != "" {
+			lang[m] = m.Language
+		}
 	}
 	return &lang
 }
@@ -216,21 +259,21 @@ func calculateLanguageColumnWidths(rows []LanguageRow) [3]int {
 	// Header widths
 	headers := []string{formalName, namedVal, langRegion}
 	for i, header := range headers {
-		if len(header) > widths[i] {
-			widths[i] = len(header)
+		if reflow.StringWidth(header) > widths[i] {
+			widths[i] = reflow.StringWidth(header)
 		}
 	}
 
 	// Data widths
 	for _, row := range rows {
-		if len(row.Name) > widths[0] {
-			widths[0] = len(row.Name)
+		if reflow.StringWidth(row.Name) > widths[0] {
+			widths[0] = reflow.StringWidth(row.Name)
 		}
-		if len(row.Value) > widths[1] {
-			widths[1] = len(row.Value)
+		if reflow.StringWidth(row.Value) > widths[1] {
+			widths[1] = reflow.StringWidth(row.Value)
 		}
-		if len(row.Language) > widths[2] {
-			widths[2] = len(row.Language)
+		if reflow.StringWidth(row.Language) > widths[2] {
+			widths[2] = reflow.StringWidth(row.Language)
 		}
 	}
 
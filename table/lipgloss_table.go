@@ -5,6 +5,7 @@ import (
 	"io"
 	"strings"
 
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -71,24 +72,24 @@ func calculateColumnWidths(rows []Row) [4]int {
 	// Header widths
 	headers := []string{"Formal name", "Named value", "Numeric value", "Alias value"}
 	for i, header := range headers {
-		if len(header) > widths[i] {
-			widths[i] = len(header)
+		if reflow.StringWidth(header) > widths[i] {
+			widths[i] = reflow.StringWidth(header)
 		}
 	}
 
 	// Data widths
 	for _, row := range rows {
-		if len(row.Name) > widths[0] {
-			widths[0] = len(row.Name)
+		if reflow.StringWidth(row.Name) > widths[0] {
+			widths[0] = reflow.StringWidth(row.Name)
 		}
-		if len(row.Value) > widths[1] {
-			widths[1] = len(row.Value)
+		if reflow.StringWidth(row.Value) > widths[1] {
+			widths[1] = reflow.StringWidth(row.Value)
 		}
-		if len(row.Numeric) > widths[2] {
-			widths[2] = len(row.Numeric)
+		if reflow.StringWidth(row.Numeric) > widths[2] {
+			widths[2] = reflow.StringWidth(row.Numeric)
 		}
-		if len(row.Alias) > widths[3] {
-			widths[3] = len(row.Alias)
+		if reflow.StringWidth(row.Alias) > widths[3] {
+			widths[3] = reflow.StringWidth(row.Alias)
 		}
 	}
 
@@ -166,8 +167,5 @@ func createRow(
 
 // FitString fits a string to a specific width by padding or truncating.
 func FitString(s string, width int) string {
-	if len(s) < width {
-		return s + strings.Repeat(" ", width-len(s))
-	}
-	return s[:width]
+	return reflow.Fit(width, s)
 }
@@ -36,6 +36,30 @@ func TestLipglossTable(t *testing.T) {
 	}
 }
 
+func TestFitString(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"pad", "cp437", 8, "cp437   "},
+		{"truncate", "cp437", 3, "cp4"},
+		{"wide pad", "日本語", 8, "日本語  "},
+		{"wide truncate", "日本語", 5, "日本 "},
+		{"combining", "cafe\u0301", 5, "cafe\u0301 "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := table.FitString(tt.s, tt.width); got != tt.want {
+				t.Errorf("FitString() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestListLipgloss(t *testing.T) {
 	t.Parallel()
 	tests := []struct {
@@ -6,7 +6,9 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
 	"github.com/bengarrett/retrotxtgo/meta"
+	"github.com/bengarrett/retrotxtgo/micro"
 	"github.com/bengarrett/retrotxtgo/term"
 	"github.com/bengarrett/retrotxtgo/xud"
 	"github.com/charmbracelet/lipgloss"
@@ -52,6 +54,35 @@ func Charmaps() []encoding.Encoding {
 			continue
 		}
 		e = append(e, m)
+		// Insert the DOS national and the EBCDIC code pages in numeric order.
+		switch m {
+		case charmap.CodePage037:
+			e = append(e, xud.XUserDefined273, xud.XUserDefined277, xud.XUserDefined278,
+				xud.XUserDefined280, xud.XUserDefined284, xud.XUserDefined285,
+				xud.XUserDefined297, xud.XUserDefined500, xud.XUserDefined871)
+		case charmap.CodePage437:
+			e = append(e, xud.XUserDefined737, xud.XUserDefined775)
+		case charmap.CodePage855:
+			e = append(e, xud.XUserDefined857)
+		case charmap.CodePage860:
+			e = append(e, xud.XUserDefined861)
+		case charmap.CodePage863:
+			e = append(e, xud.XUserDefined864)
+		case charmap.CodePage866:
+			e = append(e, xud.XUserDefined869,
+				xud.XUserDefinedKamenicky, xud.XUserDefinedMazovia, xud.XUserDefinedMIK)
+		case charmap.CodePage1140:
+			e = append(e, xud.XUserDefined1141, xud.XUserDefined1142, xud.XUserDefined1143,
+				xud.XUserDefined1144, xud.XUserDefined1145, xud.XUserDefined1146,
+				xud.XUserDefined1147, xud.XUserDefined1148, xud.XUserDefined1149)
+		}
+	}
+	// Append the home computer and the user-defined code pages.
+	for _, m := range micro.All() {
+		e = append(e, m)
+	}
+	for _, m := range mapping.All() {
+		e = append(e, m)
 	}
 	return e
 }
@@ -101,6 +132,10 @@ func createTableRows() ([]Row, error) {
 
 // processRow processes a single row based on the encoding type.
 func processRow(e encoding.Encoding, c Row) []Row {
+	if xud.CodeEBCDIC(e) {
+		c.Name = "* " + c.Name
+		return []Row{c}
+	}
 	switch e {
 	case charmap.ISO8859_10:
 		const iso885910Rows = 2 // ISO-8859-10 + ISO-8859-11
@@ -148,7 +183,7 @@ func printLegendAndFooter(wr io.Writer) {
 	fmt.Fprintln(wr, " "+nonTableStyle.Render("Darker text")+
 		" indicates encodings not usable with the "+term.Example("table")+" command.")
 	fmt.Fprintln(wr, " "+tableOnlyStyle.Render("Purple text")+
-		" indicates encodings only usable with the "+term.Example("table")+" command."+
+		" indicates the historic 7-bit ASA X3.4 encodings."+
 		"\n You can use the \""+term.Example("table ascii")+"\" command to list all three X3.4 tables.")
 	fmt.Fprintln(wr, "\nNamed, numeric, or alias values are all valid code page arguments.")
 	fmt.Fprintln(wr, "These values all match ISO 8859-1:")
@@ -169,8 +204,25 @@ func printLegendAndFooter(wr io.Writer) {
 		term.Comment("Windows 1252"))
 	fmt.Fprintf(wr, " %s is found on Mac OS 9 and earlier systems.\n",
 		term.Comment("Macintosh"))
+	fmt.Fprintf(wr, " %s, %s, and %s are found on the 8-bit home computers of the 1980s.\n",
+		term.Comment("PETSCII"), term.Comment("ATASCII"), term.Comment("ZX Spectrum"))
 	fmt.Fprintf(wr, " %s is incompatible with %s, most computers, and the web.\n",
 		specialStyle.Render("EBCDIC"), term.Comment(ansi))
+	printUserDefined(wr)
+}
+
+// printUserDefined prints the location of the user-defined code pages
+// and any mapping files that could not be loaded.
+func printUserDefined(wr io.Writer) {
+	dir, err := mapping.Path()
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(wr, "\n User-defined code pages are loaded from the Unicode mapping (.txt)\n"+
+		" or ICU (.ucm) files in %s\n", term.Comment(dir))
+	if err := mapping.Errors(); err != nil {
+		fmt.Fprintf(wr, "\n%s%s\n", term.Alert(), err)
+	}
 }
 
 // Rows return character encoding details for use in a text table.
@@ -181,6 +233,12 @@ func Rows(e encoding.Encoding) (Row, error) {
 	r := Row{
 		Name: fmt.Sprint(e),
 	}
+	if xud.CodeDOS(e) || xud.CodeEBCDIC(e) {
+		r.Value = xud.Name(e)
+		r.Numeric = xud.Numeric(e)
+		r.Alias = xud.Alias(e)
+		return r, nil
+	}
 	switch e {
 	case xud.XUserDefined1963, xud.XUserDefined1965, xud.XUserDefined1967:
 		r.Value = xud.Name(e)
@@ -188,6 +246,21 @@ func Rows(e encoding.Encoding) (Row, error) {
 		r.Alias = xud.Alias(e)
 		return r, nil
 	}
+	if m, ok := e.(*micro.Encoding); ok {
+		r.Value = m.Value
+		r.Alias = m.Alias
+		return r, nil
+	}
+	if m, ok := e.(*mapping.Encoding); ok {
+		r.Value = m.Value
+		// use the digits of the named value, such as 861 for cp861
+		digits := strings.TrimLeftFunc(m.Value, func(r rune) bool { return r < '0' || r > '9' })
+		if i, err := strconv.Atoi(digits); err == nil {
+			r.Numeric = strconv.Itoa(i)
+		}
+		r.Alias = strings.Join(m.Aliases, ", ")
+		return r, nil
+	}
 	var err error
 	if r.Value, err = htmlindex.Name(e); err != nil {
 		r.Value, err = ianaindex.MIME.Name(e)
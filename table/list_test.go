@@ -5,7 +5,9 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
 	"github.com/bengarrett/retrotxtgo/table"
+	"github.com/bengarrett/retrotxtgo/xud"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/encoding/ianaindex"
@@ -21,8 +23,8 @@ func ExampleAlias() {
 
 func TestCharmaps(t *testing.T) {
 	t.Parallel()
-	const totalCharmaps = 54
-	got, want := len(table.Charmaps()), totalCharmaps
+	const totalCharmaps = 89
+	got, want := len(table.Charmaps()), totalCharmaps+len(mapping.All())
 	if got != want {
 		t.Errorf("Charmaps() count = %v, want %v", got, want)
 	}
@@ -39,6 +41,11 @@ func TestList(t *testing.T) {
 
 func TestRows(t *testing.T) {
 	t.Parallel()
+	const txt = "# Name: CP861 Icelandic\n# Alias: icelandic, ibm861\n0x80\t0x00C7\n"
+	cp861, err := mapping.Parse("cp861.txt", strings.NewReader(txt))
+	if err != nil {
+		t.Fatal(err)
+	}
 	tests := []struct {
 		name    string
 		e       encoding.Encoding
@@ -66,6 +73,16 @@ func TestRows(t *testing.T) {
 			table.Row{"UTF-32BE (Use BOM)", "utf-32", "", "utf32"},
 			false,
 		},
+		{
+			"cp1141", xud.XUserDefined1141,
+			table.Row{"IBM Code Page 1141", "cp1141", "1141", "ibm01141"},
+			false,
+		},
+		{
+			"user-defined", cp861,
+			table.Row{"CP861 Icelandic", "cp861", "861", "icelandic, ibm861"},
+			false,
+		},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
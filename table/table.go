@@ -13,7 +13,10 @@ import (
 
 	"github.com/bengarrett/retrotxtgo/byter"
 	"github.com/bengarrett/retrotxtgo/convert"
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"github.com/bengarrett/retrotxtgo/micro"
 	"github.com/bengarrett/retrotxtgo/term"
+	"github.com/bengarrett/retrotxtgo/videotex"
 	"github.com/bengarrett/retrotxtgo/xud"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/gookit/color"
@@ -30,6 +33,7 @@ var (
 	ErrBig5  = errors.New("big5 table encodings are not supported")
 	ErrUTF16 = errors.New("utf-16 table encodings are not supported")
 	ErrUTF32 = errors.New("utf-32 table encodings are not supported")
+	ErrPage  = errors.New("teletext and videotex page formats have no tables")
 )
 
 const width = 68 // width of the table in characters.
@@ -222,6 +226,15 @@ func Footnote(w io.Writer, name string) {
 	if SHY240(x) {
 		fmt.Fprintln(w, "* Cell F-0"+msg)
 	}
+	if x == xud.XUserDefined864 {
+		fmt.Fprintln(w, "* Cell A-1"+msg)
+	}
+	switch x {
+	case micro.ZXSpectrum:
+		fmt.Fprintln(w, "* Cells A-5 to F-F are the BASIC keyword tokens, such as PRINT and GO TO.")
+	case micro.AmstradCPC:
+		fmt.Fprintln(w, "* Cells C-0 to D-F, E-F and F-C to F-F are graphics without Unicode characters.")
+	}
 }
 
 // CodePage returns the encoding of the code page name or alias.
@@ -243,6 +256,9 @@ func CodePage(s string) (encoding.Encoding, error) {
 		utf32.UTF32(utf32.LittleEndian, utf32.IgnoreBOM):
 		return nil, ErrUTF32
 	}
+	if _, ok := cp.(*videotex.Encoding); ok {
+		return nil, ErrPage
+	}
 	return cp, nil
 }
 
@@ -282,7 +298,10 @@ func SHY240(x encoding.Encoding) bool {
 	case charmap.CodePage850,
 		charmap.CodePage852,
 		charmap.CodePage855,
-		charmap.CodePage858:
+		charmap.CodePage858,
+		xud.XUserDefined775,
+		xud.XUserDefined857,
+		xud.XUserDefined869:
 		return true
 	}
 	return false
@@ -313,6 +332,13 @@ func Replacement(name string, code int) string {
 			return "\u00FF"
 		}
 	}
+	if x == xud.XUserDefined864 {
+		const shy = 161
+		if code == shy {
+			return "-"
+		}
+		return ""
+	}
 	if SHY240(x) {
 		const shy = 240
 		if code == shy {
@@ -371,6 +397,9 @@ func CharmapAlias(cp encoding.Encoding) string { //nolint:cyclop
 	if c := charmapDOS(cp); c != "" {
 		return c
 	}
+	if c := charmapEBCDIC(cp); c != "" {
+		return c
+	}
 	switch cp {
 	case charmap.CodePage1047:
 		return " (C programming language)"
@@ -410,6 +439,19 @@ func CharmapAlias(cp encoding.Encoding) string { //nolint:cyclop
 	if c := charmapMisc(cp); c != "" {
 		return c
 	}
+	switch cp {
+	case micro.PETSCII:
+		return " (uppercase and graphics)"
+	case micro.PETSCIIShifted:
+		return " (lowercase and uppercase)"
+	case micro.ScreenCodes, micro.ScreenCodesShifted:
+		return " (video memory)"
+	case micro.ATASCII:
+		return " (Atari 8-bit)"
+	}
+	if m, ok := cp.(*mapping.Encoding); ok && m.Language != "" {
+		return " (" + m.Language + ")"
+	}
 	return ""
 }
 
@@ -438,6 +480,68 @@ func charmapDOS(cp encoding.Encoding) string {
 		return " (DOS, Nordic)"
 	case charmap.CodePage866:
 		return " (DOS, Cyrillic Russian)"
+	case xud.XUserDefined737:
+		return " (DOS, Greek)"
+	case xud.XUserDefined775:
+		return " (DOS, Baltic Rim)"
+	case xud.XUserDefined857:
+		return " (DOS, Turkish)"
+	case xud.XUserDefined861:
+		return " (DOS, Icelandic)"
+	case xud.XUserDefined864:
+		return " (DOS, Arabic)"
+	case xud.XUserDefined869:
+		return " (DOS, Greek 2)"
+	case xud.XUserDefinedKamenicky:
+		return " (DOS, Czech and Slovak)"
+	case xud.XUserDefinedMazovia:
+		return " (DOS, Polish)"
+	case xud.XUserDefinedMIK:
+		return " (DOS, Bulgarian)"
+	}
+	return ""
+}
+
+// charmapEBCDIC humanizes the national and Euro EBCDIC encodings.
+func charmapEBCDIC(cp encoding.Encoding) string {
+	const euro = " plus €)"
+	switch cp {
+	case xud.XUserDefined273:
+		return " (Germany/Austria)"
+	case xud.XUserDefined277:
+		return " (Denmark/Norway)"
+	case xud.XUserDefined278:
+		return " (Finland/Sweden)"
+	case xud.XUserDefined280:
+		return " (Italy)"
+	case xud.XUserDefined284:
+		return " (Spain/Latin America)"
+	case xud.XUserDefined285:
+		return " (United Kingdom)"
+	case xud.XUserDefined297:
+		return " (France)"
+	case xud.XUserDefined500:
+		return " (International Latin 1)"
+	case xud.XUserDefined871:
+		return " (Iceland)"
+	case xud.XUserDefined1141:
+		return " (Germany/Austria" + euro
+	case xud.XUserDefined1142:
+		return " (Denmark/Norway" + euro
+	case xud.XUserDefined1143:
+		return " (Finland/Sweden" + euro
+	case xud.XUserDefined1144:
+		return " (Italy" + euro
+	case xud.XUserDefined1145:
+		return " (Spain/Latin America" + euro
+	case xud.XUserDefined1146:
+		return " (United Kingdom" + euro
+	case xud.XUserDefined1147:
+		return " (France" + euro
+	case xud.XUserDefined1148:
+		return " (International Latin 1" + euro
+	case xud.XUserDefined1149:
+		return " (Iceland" + euro
 	}
 	return ""
 }
@@ -463,6 +567,12 @@ func charmapMisc(cp encoding.Encoding) string {
 
 // charmapStandard humanizes common encodings.
 func charmapStandard(cp encoding.Encoding) string {
+	if xud.CodeEBCDIC(cp) {
+		return " - EBCDIC"
+	}
+	if _, ok := cp.(*micro.Encoding); ok {
+		return " - Home computer"
+	}
 	switch cp {
 	case charmap.CodePage037, charmap.CodePage1047, charmap.CodePage1140:
 		return " - EBCDIC"
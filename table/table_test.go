@@ -2,9 +2,12 @@ package table_test
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
 	"github.com/bengarrett/retrotxtgo/table"
+	"github.com/bengarrett/retrotxtgo/xud"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 	"golang.org/x/text/encoding/japanese"
@@ -20,6 +23,8 @@ func TestTable(t *testing.T) {
 	}{
 		{"IBM437", false},
 		{"cp437", false},
+		{"cp273", false},
+		{"1148", false},
 		{"win", false},
 		{"xxx", true},
 	}
@@ -49,6 +54,7 @@ func Test_CodePage(t *testing.T) {
 		{"empty", args{}, nil, true},
 		{"none", args{"helloworld"}, nil, true},
 		{"437", args{"cp437"}, charmap.CodePage437, false},
+		{"teletext", args{"teletext"}, nil, true},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
@@ -95,6 +101,11 @@ func Test_character(t *testing.T) {
 
 func Test_CharmapAlias(t *testing.T) {
 	t.Parallel()
+	cp861, err := mapping.Parse("cp861.ucm", strings.NewReader(
+		"# Language: Icelandic\nCHARMAP\n<U00C7> \\x80 |0\nEND CHARMAP\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
 	type args struct {
 		cp encoding.Encoding
 	}
@@ -107,6 +118,9 @@ func Test_CharmapAlias(t *testing.T) {
 		{"win874", args{charmap.Windows874}, " (Thai)"},
 		{"shiftjis", args{japanese.ShiftJIS}, " (Japanese)"},
 		{"big5", args{traditionalchinese.Big5}, " (Traditional Chinese)"},
+		{"cp273", args{xud.XUserDefined273}, " (Germany/Austria)"},
+		{"cp1147", args{xud.XUserDefined1147}, " (France plus €)"},
+		{"user-defined", args{cp861}, " (Icelandic)"},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
@@ -14,6 +14,7 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/bengarrett/retrotxtgo/reflow"
 	"github.com/gookit/color"
 )
 
@@ -51,7 +52,7 @@ func Border(w io.Writer, s string) {
 	maxLen, scanner := 0, bufio.NewScanner(strings.NewReader(s))
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
-		l := utf8.RuneCountInString(scanner.Text())
+		l := reflow.StringWidth(scanner.Text())
 		if l > maxLen {
 			maxLen = l
 		}
@@ -61,7 +62,7 @@ func Border(w io.Writer, s string) {
 	scanner.Split(bufio.ScanLines)
 	fmt.Fprintln(w, ("┌" + strings.Repeat("─", maxLen) + "┐"))
 	for scanner.Scan() {
-		l := utf8.RuneCountInString(scanner.Text())
+		l := reflow.StringWidth(scanner.Text())
 		lp := ((maxLen - l) / split)
 		rp := lp
 		// if lp/rp are X.5 decimal values, add 1 right padd to account for the uneven split
@@ -79,7 +80,7 @@ func BorderString(s string) string {
 	maxLen, scanner := 0, bufio.NewScanner(strings.NewReader(s))
 	scanner.Split(bufio.ScanLines)
 	for scanner.Scan() {
-		l := utf8.RuneCountInString(scanner.Text())
+		l := reflow.StringWidth(scanner.Text())
 		if l > maxLen {
 			maxLen = l
 		}
@@ -91,7 +92,7 @@ func BorderString(s string) string {
 	var result strings.Builder
 	result.WriteString("┌" + strings.Repeat("─", maxLen) + "┐\n")
 	for scanner.Scan() {
-		l := utf8.RuneCountInString(scanner.Text())
+		l := reflow.StringWidth(scanner.Text())
 		lp := ((maxLen - l) / split)
 		rp := lp
 		// if lp/rp are X.5 decimal values, add 1 right padd to account for the uneven split
@@ -109,7 +110,7 @@ func BorderString(s string) string {
 // There is no padding after the string.
 func Center(width int, s string) string {
 	const split, space = 2, "\u0020"
-	if w := (width - len(s)) / split; w > 0 {
+	if w := (width - reflow.StringWidth(s)) / split; w > 0 {
 		return strings.Repeat(space, w) + s
 	}
 	return s
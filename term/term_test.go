@@ -160,6 +160,8 @@ func TestCenter(t *testing.T) {
 		{"empty", args{"", 0}, ""},
 		{"even", args{"hi", 10}, "    hi"},
 		{"odd", args{"hello", 10}, "  hello"},
+		{"wide", args{"日本", 10}, "   日本"},
+		{"ansi", args{"\x1b[1mhi\x1b[0m", 10}, "    \x1b[1mhi\x1b[0m"},
 	}
 	t.Run("", func(t *testing.T) {
 		t.Parallel()
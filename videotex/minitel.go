@@ -0,0 +1,296 @@
+package videotex
+
+import (
+	"bytes"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Minitel control codes.
+const (
+	so  = 0x0e // so shifts to the G1 mosaic set.
+	si  = 0x0f // si shifts to the G0 alphanumeric set.
+	rep = 0x12 // rep repeats the previous character.
+	can = 0x18 // can clears the rest of the row.
+	ss2 = 0x19 // ss2 prints a single character of the G2 supplementary set.
+	us  = 0x1f // us moves the cursor to a row and column.
+)
+
+// g2 are the characters of the Minitel G2 supplementary set.
+var g2 = map[byte]rune{
+	0x23: '£', 0x24: '$', 0x26: '#', 0x27: '§', 0x2c: '←', 0x2d: '↑', 0x2e: '→', 0x2f: '↓',
+	0x30: '°', 0x31: '±', 0x38: '÷', 0x3c: '¼', 0x3d: '½', 0x3e: '¾',
+	0x6a: 'Œ', 0x7a: 'œ', 0x7b: 'ß',
+}
+
+// accents are the combining diacritical marks of the Minitel G2 supplementary set,
+// that are followed by the letter they accent.
+var accents = map[byte]rune{
+	0x41: '\u0300', // grave
+	0x42: '\u0301', // acute
+	0x43: '\u0302', // circumflex
+	0x48: '\u0308', // diaeresis
+	0x4b: '\u0327', // cedilla
+}
+
+// screen is the state of a Minitel screen.
+type screen struct {
+	page      [][]cell
+	x, y      int
+	fg, bg    int
+	pending   int // pending is the background color that starts at the next space or mosaic.
+	mosaic    bool
+	separated bool
+	underline bool
+	flash     bool
+	conceal   bool
+	inverse   bool
+	last      rune
+	used      bool
+	t         [256]rune
+}
+
+// newScreen returns a blank Minitel screen with the cursor at the first row of the page.
+func newScreen() *screen {
+	s := &screen{t: g0(ascii), last: ' '}
+	s.clear()
+	return s
+}
+
+// clear blanks the screen and homes the cursor.
+func (s *screen) clear() {
+	s.page = blankPage(Rows)
+	s.used = false
+	s.home()
+}
+
+// home moves the cursor to the first row of the page and resets the attributes.
+func (s *screen) home() {
+	s.move(1, 0)
+}
+
+// move moves the cursor to the row and column and resets the attributes.
+func (s *screen) move(y, x int) {
+	s.y, s.x = min(max(y, 0), Rows-1), min(max(x, 0), Columns-1)
+	s.fg, s.bg, s.pending = white, black, black
+	s.mosaic, s.separated, s.underline = false, false, false
+	s.flash, s.conceal, s.inverse = false, false, false
+}
+
+// attr returns the display attributes of the screen state.
+func (s *screen) attr() ansi.Attr {
+	a := ansi.Attr{FG: foreground(s.fg), BG: background(s.bg)}
+	flags := []struct {
+		on bool
+		f  ansi.Flags
+	}{
+		{s.flash, ansi.Blink}, {s.conceal, ansi.Conceal}, {s.inverse, ansi.Inverse},
+		{s.underline && !s.mosaic, ansi.Underline},
+	}
+	for _, x := range flags {
+		if x.on {
+			a.Flags |= x.f
+		}
+	}
+	return a
+}
+
+// put writes the rune at the cursor and moves the cursor to the next column.
+func (s *screen) put(r rune) {
+	if r == ' ' || s.mosaic {
+		// the serial attributes are validated by a delimiter
+		s.bg = s.pending
+	}
+	s.page[s.y][s.x] = cell{r: r, attr: s.attr()}
+	s.last, s.used = r, true
+	s.x++
+	if s.x >= Columns {
+		s.x = 0
+		if s.y > 0 {
+			s.y = s.y%(Rows-1) + 1
+		}
+	}
+}
+
+// char writes the character code of the G0 or G1 set.
+func (s *screen) char(c byte) {
+	if s.mosaic && c&0x20 != 0 {
+		s.put(sextant(c, s.separated))
+		return
+	}
+	s.put(s.t[c])
+}
+
+// escape applies the attribute of the escape sequence.
+func (s *screen) escape(c byte) {
+	switch {
+	case c >= 0x40 && c <= 0x47:
+		s.fg = int(c - 0x40)
+	case c >= 0x50 && c <= 0x57:
+		s.pending = int(c - 0x50)
+	case c == 0x48:
+		s.flash = true
+	case c == 0x49:
+		s.flash = false
+	case c == 0x58:
+		s.conceal = true
+	case c == 0x5f:
+		s.conceal = false
+	case c == 0x59:
+		s.underline, s.separated = false, false
+	case c == 0x5a:
+		s.underline, s.separated = true, s.mosaic
+	case c == 0x5c:
+		s.inverse = false
+	case c == 0x5d:
+		s.inverse = true
+	}
+}
+
+// minitel returns the pages of the Minitel videotex stream.
+// The double size characters are displayed at the normal size,
+// and a form feed completes the page.
+func minitel(b ...byte) [][][]cell {
+	var pages [][][]cell
+	s := newScreen()
+	flush := func() {
+		if s.used {
+			p := s.page
+			if blank(p[0]) {
+				// the first row is the status row
+				p = p[1:]
+			}
+			pages = append(pages, p)
+		}
+		s.clear()
+	}
+	for i := 0; i < len(b); i++ {
+		c := b[i] & 0x7f
+		next := func() (byte, bool) {
+			if i+1 >= len(b) {
+				return 0, false
+			}
+			i++
+			return b[i] & 0x7f, true
+		}
+		switch c {
+		case esc:
+			n, ok := next()
+			if !ok {
+				break
+			}
+			switch n {
+			case 0x39, 0x3a, 0x3b:
+				// the protocol sequences PRO1, PRO2 and PRO3
+				i += int(n - 0x38)
+			case '[':
+				for {
+					n, ok = next()
+					if !ok || (n >= 0x40 && n <= 0x7e) {
+						break
+					}
+				}
+			case 0x23:
+				i += 2
+			default:
+				s.escape(n)
+			}
+		case so:
+			s.mosaic = true
+		case si:
+			s.mosaic, s.separated = false, false
+		case rep:
+			n, ok := next()
+			if !ok {
+				break
+			}
+			for range int(n) - 0x40 {
+				s.put(s.last)
+			}
+		case can:
+			for x := s.x; x < Columns; x++ {
+				s.page[s.y][x] = cell{r: ' ', attr: s.attr()}
+			}
+		case ss2:
+			n, ok := next()
+			if !ok {
+				break
+			}
+			if mark, ok := accents[n]; ok {
+				l, ok := next()
+				if !ok {
+					break
+				}
+				r := []rune(norm.NFC.String(string(s.t[l]) + string(mark)))
+				s.put(r[0])
+				break
+			}
+			if r, ok := g2[n]; ok {
+				s.put(r)
+			}
+		case us:
+			y, ok1 := next()
+			x, ok2 := next()
+			if !ok1 || !ok2 {
+				break
+			}
+			if digit(y) && digit(x) {
+				// the digits format uses two digits for the row and two for the column
+				tens, _ := next()
+				units, _ := next()
+				s.move(number(y, x), number(tens, units)-1)
+				break
+			}
+			s.move(int(y)-0x40, int(x)-0x41)
+		case 0x0c:
+			flush()
+		case 0x1e:
+			s.home()
+		case 0x08:
+			s.x = max(s.x-1, 0)
+		case 0x09:
+			s.x = min(s.x+1, Columns-1)
+		case '\n':
+			s.y = min(s.y+1, Rows-1)
+		case 0x0b:
+			s.y = max(s.y-1, 1)
+		case '\r':
+			s.x = 0
+		default:
+			if c >= 0x20 {
+				s.char(c)
+			}
+		}
+	}
+	flush()
+	return pages
+}
+
+// digit reports whether the byte is an ASCII digit.
+func digit(b byte) bool {
+	return b >= '0' && b <= '9'
+}
+
+// number returns the value of the two ASCII digits.
+func number(tens, units byte) int {
+	const base = 10
+	return int(tens-'0')*base + int(units-'0')
+}
+
+// isMinitel reports whether the bytes are a Minitel videotex stream,
+// that uses the unit separator to position the cursor.
+func isMinitel(b []byte) bool {
+	const least = 2
+	n := 0
+	for i := 0; i+2 < len(b); i++ {
+		if b[i] != us {
+			continue
+		}
+		y, x := b[i+1], b[i+2]
+		if y >= 0x40 && y <= 0x58 && x >= 0x41 && x <= 0x68 {
+			n++
+		}
+	}
+	return n >= least && !bytes.Contains(b, []byte("\x1b["))
+}
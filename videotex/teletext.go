@@ -0,0 +1,463 @@
+package videotex
+
+import (
+	"bytes"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+)
+
+// Teletext packet and page sizes.
+const (
+	packet   = 42           // packet is the length of a T42 packet.
+	rawPage  = Columns * 25 // rawPage is the length of a raw page of 25 rows.
+	rawShort = Columns * 24 // rawShort is the length of a raw page without the header row.
+	esc      = 0x1b
+)
+
+// national is a teletext national option subset, the 13 characters that replace
+// the ASCII characters of the G0 set.
+type national struct {
+	name  string
+	value string
+	chars [13]rune
+}
+
+// positions are the G0 set codes replaced by a national option subset.
+var positions = [13]byte{0x23, 0x24, 0x40, 0x5b, 0x5c, 0x5d, 0x5e, 0x5f, 0x60, 0x7b, 0x7c, 0x7d, 0x7e}
+
+// english is the index of the default national option subset.
+const english = 0
+
+// nationals are the Latin national option subsets of the teletext level 1 pages,
+// in the order of the C12, C13 and C14 page header control bits.
+var nationals = [...]national{
+	{"English", "en", [13]rune{'£', '$', '@', '←', '½', '→', '↑', '#', '―', '¼', '‖', '¾', '÷'}},
+	{"German", "de", [13]rune{'#', '$', '§', 'Ä', 'Ö', 'Ü', '^', '_', '°', 'ä', 'ö', 'ü', 'ß'}},
+	{"Swedish", "sv", [13]rune{'#', '¤', 'É', 'Ä', 'Ö', 'Å', 'Ü', '_', 'é', 'ä', 'ö', 'å', 'ü'}},
+	{"Italian", "it", [13]rune{'£', '$', 'é', '°', 'ç', '→', '↑', '#', 'ù', 'à', 'ò', 'è', 'ì'}},
+	{"French", "fr", [13]rune{'é', 'ï', 'à', 'ë', 'ê', 'ù', 'î', '#', 'è', 'â', 'ô', 'û', 'ç'}},
+	{"Spanish", "es", [13]rune{'ç', '$', '¡', 'á', 'é', 'í', 'ó', 'ú', '¿', 'ü', 'ñ', 'è', 'à'}},
+	{"Czech", "cs", [13]rune{'#', 'ů', 'č', 'ť', 'ž', 'ý', 'í', 'ř', 'é', 'á', 'ě', 'ú', 'š'}},
+}
+
+// ascii is the unmodified G0 set used by the Minitel pages.
+var ascii = national{name: "ASCII", value: "ascii", chars: func() [13]rune {
+	var c [13]rune
+	for i, b := range positions {
+		c[i] = rune(b)
+	}
+	return c
+}()}
+
+// g0 returns the G0 character set of the national option subset,
+// the control codes are not mapped.
+func g0(n national) [256]rune {
+	var t [256]rune
+	for i := range t {
+		t[i] = utf8.RuneError
+	}
+	for i := 0x20; i < 0x7f; i++ {
+		t[i] = rune(i)
+	}
+	t[0x7f] = '■'
+	for i, b := range positions {
+		t[b] = n.chars[i]
+	}
+	return t
+}
+
+// colors are the eight teletext colors, black, red, green, yellow, blue, magenta, cyan and white.
+var colors = [8]ansi.Color{
+	ansi.TrueColor(0, 0, 0),
+	ansi.TrueColor(255, 0, 0),
+	ansi.TrueColor(0, 255, 0),
+	ansi.TrueColor(255, 255, 0),
+	ansi.TrueColor(0, 0, 255),
+	ansi.TrueColor(255, 0, 255),
+	ansi.TrueColor(0, 255, 255),
+	ansi.TrueColor(255, 255, 255),
+}
+
+// Teletext colors used as the page defaults.
+const (
+	black = 0
+	white = 7
+)
+
+// foreground returns the foreground color, white uses the terminal default.
+func foreground(i int) ansi.Color {
+	if i == white {
+		return ansi.Color{}
+	}
+	return colors[i]
+}
+
+// background returns the background color, black uses the terminal default.
+func background(i int) ansi.Color {
+	if i == black {
+		return ansi.Color{}
+	}
+	return colors[i]
+}
+
+// sextant returns the block sextant of the mosaic character code.
+// Bits 0 to 4 and bit 6 of the code are the six cells of the mosaic, from the top left
+// to the bottom right. The separated mosaics use the separated block sextants.
+func sextant(c byte, separated bool) rune {
+	v := rune(c&0x1f | c&0x40>>1)
+	const left, right, full = 21, 42, 63
+	switch {
+	case v == 0:
+		return ' '
+	case separated:
+		return 0x1ce51 + v - 1
+	case v == left:
+		return '▌'
+	case v == right:
+		return '▐'
+	case v == full:
+		return '█'
+	}
+	r := 0x1fb00 + v - 1
+	if v > left {
+		r--
+	}
+	if v > right {
+		r--
+	}
+	return r
+}
+
+// row is the state of the spacing attributes of a teletext row.
+type row struct {
+	fg, bg    int
+	mosaic    bool
+	separated bool
+	hold      bool
+	held      rune
+	flash     bool
+	conceal   bool
+	double    bool
+}
+
+// attr returns the display attributes of the row state.
+func (s *row) attr() ansi.Attr {
+	a := ansi.Attr{FG: foreground(s.fg), BG: background(s.bg)}
+	if s.flash {
+		a.Flags |= ansi.Blink
+	}
+	if s.conceal {
+		a.Flags |= ansi.Conceal
+	}
+	return a
+}
+
+// decodeRow returns the cells of a row of teletext codes using the national option subset.
+// The spacing attributes are interpreted in the set-at and the set-after orders of the level 1 pages.
+func decodeRow(t *[256]rune, b []byte) []cell {
+	cells := blankRow()
+	s := row{fg: white, bg: black, held: ' '}
+	for i := 0; i < Columns && i < len(b); i++ {
+		c := b[i] & 0x7f
+		if c >= 0x20 {
+			r := t[c]
+			if s.mosaic && c&0x20 != 0 {
+				r = sextant(c, s.separated)
+				s.held = r
+			}
+			cells[i] = cell{r: r, attr: s.attr(), tall: s.double}
+			continue
+		}
+		// set-at attributes
+		switch c {
+		case 0x09:
+			s.flash = false
+		case 0x0c:
+			s.double, s.held = false, ' '
+		case 0x18:
+			s.conceal = true
+		case 0x19:
+			s.separated = false
+		case 0x1a:
+			s.separated = true
+		case 0x1c:
+			s.bg = black
+		case 0x1d:
+			s.bg = s.fg
+		case 0x1e:
+			s.hold = true
+		}
+		r := ' '
+		if s.hold && s.mosaic {
+			r = s.held
+		}
+		cells[i] = cell{r: r, attr: s.attr(), tall: s.double}
+		// set-after attributes
+		switch {
+		case c <= 0x07:
+			s.fg, s.mosaic, s.conceal, s.held = int(c), false, false, ' '
+		case c == 0x08:
+			s.flash = true
+		case c == 0x0d:
+			s.double, s.held = true, ' '
+		case c >= 0x10 && c <= 0x17:
+			s.fg, s.mosaic, s.conceal = int(c-0x10), true, false
+		case c == 0x1f:
+			s.hold = false
+		}
+	}
+	return cells
+}
+
+// decode returns the cells of a page of teletext rows using the national option subset.
+// The rows that follow a row with double height characters display the lower halves
+// of those characters.
+func decode(n int, rows [][]byte) [][]cell {
+	t := g0(nationals[n])
+	page := make([][]cell, 0, len(rows))
+	for i := 0; i < len(rows); i++ {
+		cells := decodeRow(&t, rows[i])
+		page = append(page, cells)
+		if i == 0 || i+1 >= len(rows) || !tall(cells) {
+			continue
+		}
+		lower := make([]cell, len(cells))
+		for j, c := range cells {
+			lower[j] = c
+			if !c.tall {
+				lower[j] = cell{r: ' ', attr: ansi.Attr{BG: c.attr.BG}}
+			}
+		}
+		page = append(page, lower)
+		i++
+	}
+	return page
+}
+
+// tall reports whether the row uses any double height characters.
+func tall(cells []cell) bool {
+	for _, c := range cells {
+		if c.tall && c.r != ' ' {
+			return true
+		}
+	}
+	return false
+}
+
+// teletext returns the pages of the T42 packets, the TTI page file or the raw pages.
+func teletext(n int, b ...byte) [][][]cell {
+	var pages []ttxPage
+	switch {
+	case isT42(b):
+		pages = t42(n, b)
+	case isTTI(b):
+		pages = tti(n, b)
+	default:
+		pages = raw(n, b)
+	}
+	p := make([][][]cell, 0, len(pages))
+	for _, x := range pages {
+		p = append(p, decode(x.national, x.rows[:]))
+	}
+	return p
+}
+
+// ttxPage is a teletext page of codes and its national option subset.
+type ttxPage struct {
+	national int
+	rows     [Rows][]byte
+}
+
+// newPage returns a teletext page filled with spaces.
+func newPage(n int) *ttxPage {
+	p := &ttxPage{national: n}
+	for i := range p.rows {
+		p.rows[i] = bytes.Repeat([]byte{' '}, Columns)
+	}
+	return p
+}
+
+// raw returns the teletext pages of 40 by 25 characters,
+// a file of 960 bytes is a single page without the header row.
+func raw(n int, b []byte) []ttxPage {
+	var pages []ttxPage
+	first := 0
+	if len(b) == rawShort {
+		first = 1
+	}
+	for len(b) > 0 {
+		p := newPage(n)
+		for i := first; i < Rows && len(b) > 0; i++ {
+			l := min(Columns, len(b))
+			copy(p.rows[i], b[:l])
+			b = b[l:]
+		}
+		pages = append(pages, *p)
+	}
+	return pages
+}
+
+// hamming returns the data nibble of a Hamming 8/4 protected byte,
+// the protection bits are not checked.
+func hamming(b byte) byte {
+	return b>>1&1 | b>>3&1<<1 | b>>5&1<<2 | b>>7&1<<3
+}
+
+// t42 returns the teletext pages of the T42 packets.
+// Each magazine has its own page in progress, a page header packet completes the
+// previous page of the magazine.
+func t42(n int, b []byte) []ttxPage {
+	var pages []ttxPage
+	var open [8]*ttxPage
+	for ; len(b) >= packet; b = b[packet:] {
+		p := b[:packet]
+		a, c := hamming(p[0]), hamming(p[1])
+		mag, y := a&7, int(a>>3|c<<1)
+		switch {
+		case y == 0:
+			if open[mag] != nil {
+				pages = append(pages, *open[mag])
+			}
+			open[mag] = newPage(subset(n, hamming(p[9])))
+			m := int(mag)
+			if m == 0 {
+				m = 8
+			}
+			num := fmt.Sprintf("P%d%X%X", m, hamming(p[3]), hamming(p[2]))
+			copy(open[mag].rows[0], num)
+			copy(open[mag].rows[0][8:], p[10:])
+		case y < Rows && open[mag] != nil:
+			copy(open[mag].rows[y], p[2:])
+		}
+	}
+	for _, p := range open {
+		if p != nil {
+			pages = append(pages, *p)
+		}
+	}
+	return pages
+}
+
+// subset returns the national option subset of the page header control bits C11 to C14,
+// or the default national option subset when the bits are not set.
+func subset(n int, c byte) int {
+	i := int(c>>1&1<<2 | c>>2&1<<1 | c>>3&1)
+	if i == english || i >= len(nationals) {
+		return n
+	}
+	return i
+}
+
+// tti returns the teletext pages of the TTI page file.
+// Each page number (PN) command starts a page and the output line (OL) commands
+// are its rows, where the control codes use either the escape character or the high bit.
+func tti(n int, b []byte) []ttxPage {
+	var pages []ttxPage
+	var p *ttxPage
+	for line := range bytes.Lines(b) {
+		line = bytes.TrimRight(line, "\r\n")
+		cmd, val, ok := bytes.Cut(line, []byte(","))
+		if !ok {
+			continue
+		}
+		switch string(cmd) {
+		case "PN":
+			if p != nil {
+				pages = append(pages, *p)
+			}
+			p = newPage(n)
+		case "OL":
+			num, text, ok := bytes.Cut(val, []byte(","))
+			y, err := strconv.Atoi(string(num))
+			if !ok || err != nil || y < 0 || y >= Rows {
+				continue
+			}
+			if p == nil {
+				p = newPage(n)
+			}
+			ttiRow(p.rows[y], text)
+		}
+	}
+	if p != nil {
+		pages = append(pages, *p)
+	}
+	return pages
+}
+
+// ttiRow copies the TTI output line text to the row.
+func ttiRow(row, text []byte) {
+	x := 0
+	for i := 0; i < len(text) && x < Columns; i++ {
+		c := text[i]
+		if c == esc && i+1 < len(text) {
+			i++
+			c = text[i] - 0x40
+		}
+		row[x] = c & 0x7f
+		x++
+	}
+}
+
+// isT42 reports whether the bytes are T42 packets,
+// where the display bytes of each packet use odd parity.
+func isT42(b []byte) bool {
+	if len(b) < packet || len(b)%packet != 0 {
+		return false
+	}
+	odd, n := 0, 0
+	for p := b; len(p) >= packet; p = p[packet:] {
+		for _, c := range p[10:packet] {
+			n++
+			if bits.OnesCount8(c)%2 == 1 {
+				odd++
+			}
+		}
+	}
+	const threshold = 0.95
+	return float64(odd) >= float64(n)*threshold
+}
+
+// isTTI reports whether the bytes are a TTI page file.
+func isTTI(b []byte) bool {
+	pn := bytes.HasPrefix(b, []byte("PN,")) || bytes.Contains(b, []byte("\nPN,"))
+	ol := bytes.HasPrefix(b, []byte("OL,")) || bytes.Contains(b, []byte("\nOL,"))
+	return pn && ol
+}
+
+// isRaw reports whether the bytes are raw teletext pages,
+// that use the spacing attributes and no line breaks.
+// Several rows must start with a spacing attribute, while the texts that end
+// with a DOS end of file marker or a SAUCE record are never raw pages.
+func isRaw(b []byte) bool {
+	const eof, sauce, sauceSize, minRows = 0x1a, "SAUCE00", 128, 3
+	if len(b) != rawShort && len(b)%rawPage != 0 {
+		return false
+	}
+	if b[len(b)-1] == eof || bytes.HasPrefix(b[len(b)-sauceSize:], []byte(sauce)) {
+		return false
+	}
+	if bytes.Contains(b, []byte("\r\n")) || bytes.Contains(b, []byte("\x1b[")) {
+		return false
+	}
+	rows := 0
+	for p := b; len(p) >= Columns; p = p[Columns:] {
+		if attribute(p[0]) || attribute(p[1]) {
+			rows++
+		}
+	}
+	return rows >= minRows
+}
+
+// attribute reports whether the byte is a teletext spacing attribute,
+// ignoring any parity bit.
+func attribute(c byte) bool {
+	switch c &= 0x7f; c {
+	case '\t', '\n', '\r':
+		return false
+	}
+	return c < 0x20
+}
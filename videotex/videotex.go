@@ -0,0 +1,284 @@
+// Package videotex provides the decoders of the broadcast teletext and the videotex page formats.
+//
+// Teletext pages, such as the BBC Ceefax and the ITV Oracle services, are read from
+// T42 packet captures, TTI page files or raw 40 by 25 character pages.
+// Videotex pages are read from Prestel viewdata frames and Minitel (.vdt) streams.
+//
+// The pages are drawn onto a grid of 40 column cells, that interprets the spacing
+// attributes of the pages, such as the alphanumeric and mosaic colors, flash, conceal,
+// double height, the separated mosaics and the held mosaics.
+// The grid is returned as text with ANSI select graphic rendition (SGR) sequences
+// for the colors and attributes.
+//
+// The mosaic graphics use the block sextants of the Unicode Symbols for Legacy Computing block,
+// and the separated mosaics use the separated block sextants of its supplement.
+package videotex
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+
+	"github.com/bengarrett/retrotxtgo/ansi"
+	"github.com/bengarrett/retrotxtgo/mapping"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/transform"
+)
+
+// Page dimensions.
+const (
+	Columns = 40 // Columns is the number of characters per row of a page.
+	Rows    = 25 // Rows is the number of rows of a teletext page, including the header row.
+)
+
+// Format is the file format of the teletext or videotex pages.
+type Format int
+
+const (
+	Unknown  Format = iota // Unknown is not a teletext or videotex format.
+	T42                    // T42 are the 42 byte teletext packets captured from a broadcast.
+	TTI                    // TTI are the teletext page files used by the broadcast editors.
+	Raw                    // Raw are the teletext pages of 40 by 25 characters.
+	Viewdata               // Viewdata are the Prestel videotex frames.
+	Minitel                // Minitel are the French videotex streams.
+)
+
+// String returns the description of the format.
+func (f Format) String() string {
+	switch f {
+	case T42:
+		return "Teletext T42 packet stream"
+	case TTI:
+		return "Teletext TTI page"
+	case Raw:
+		return "Teletext page"
+	case Viewdata:
+		return "Prestel viewdata frame"
+	case Minitel:
+		return "Minitel videotex stream"
+	}
+	return ""
+}
+
+// Detect returns the teletext or videotex format of the named file and its bytes,
+// or Unknown if the bytes are not a known page format.
+func Detect(name string, b ...byte) Format {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".t42":
+		return T42
+	case ".tti", ".ttix":
+		return TTI
+	case ".vdt":
+		return Minitel
+	}
+	switch {
+	case len(b) == 0:
+		return Unknown
+	case isT42(b):
+		return T42
+	case isTTI(b):
+		return TTI
+	case isMinitel(b):
+		return Minitel
+	case isViewdata(b):
+		return Viewdata
+	case isRaw(b):
+		return Raw
+	}
+	return Unknown
+}
+
+// Encoding is a teletext or videotex page format that implements the Encoding interface.
+// The decoder reads all of the bytes before returning the decoded pages.
+type Encoding struct {
+	Name  string // Name is the formal name of the page format.
+	Value string // Value is the short name of the page format.
+	Alias string // Alias is an informal name of the page format.
+
+	national int  // national is the teletext national option subset used by default.
+	minitel  bool // minitel reports whether the Minitel pages are used.
+	viewdata bool // viewdata reports whether the Prestel frames are used.
+}
+
+var (
+	// Teletext is the teletext page format, using the English national option subset.
+	// The T42 packets, TTI page files and raw pages are all supported.
+	Teletext = &Encoding{Name: "Teletext", Value: "teletext", Alias: "ceefax"}
+	// Prestel is the British Telecom Prestel viewdata page format.
+	Prestel = &Encoding{Name: "Prestel viewdata", Value: "viewdata", Alias: "prestel", viewdata: true}
+	// Videotex is the French Minitel videotex page format.
+	Videotex = &Encoding{Name: "Minitel videotex", Value: "videotex", Alias: "minitel", minitel: true}
+)
+
+// All returns the teletext and videotex page formats,
+// including the teletext pages of every national option subset.
+func All() []*Encoding {
+	e := []*Encoding{Teletext}
+	for i, n := range nationals {
+		if i == english {
+			continue
+		}
+		e = append(e, &Encoding{
+			Name:     "Teletext " + n.name,
+			Value:    "teletext-" + n.value,
+			national: i,
+		})
+	}
+	return append(e, Prestel, Videotex)
+}
+
+// Find returns the page format that matches the name, or nil if there is no match.
+func Find(name string) *Encoding {
+	for _, e := range All() {
+		if e.Match(name) {
+			return e
+		}
+	}
+	return nil
+}
+
+// String returns the formal name of the page format.
+func (e *Encoding) String() string {
+	return e.Name
+}
+
+// Match reports whether the name matches the formal name, the named value
+// or the alias of the page format. The comparison is case-insensitive.
+func (e *Encoding) Match(name string) bool {
+	return strings.EqualFold(name, e.Name) ||
+		strings.EqualFold(name, e.Value) ||
+		(e.Alias != "" && strings.EqualFold(name, e.Alias))
+}
+
+// Pages returns the pages of the bytes as text with ANSI SGR sequences.
+func (e *Encoding) Pages(b ...byte) string {
+	var pages [][][]cell
+	switch {
+	case e.minitel:
+		pages = minitel(b...)
+	case e.viewdata:
+		pages = viewdata(b...)
+	default:
+		pages = teletext(e.national, b...)
+	}
+	s := make([]string, 0, len(pages))
+	for _, p := range pages {
+		s = append(s, render(p))
+	}
+	return strings.Join(s, "\n")
+}
+
+// NewDecoder returns a decoder that converts the pages into UTF-8 text
+// with ANSI SGR sequences.
+func (e *Encoding) NewDecoder() *encoding.Decoder {
+	return &encoding.Decoder{Transformer: &decoder{e: e}}
+}
+
+// NewEncoder returns an encoder that converts UTF-8 text into the characters of the
+// teletext national option subset or the videotex character set, without any attributes.
+// Runes that are not mapped by the page format return an error.
+func (e *Encoding) NewEncoder() *encoding.Encoder {
+	t := g0(nationals[e.national])
+	if e.minitel {
+		t = g0(ascii)
+	}
+	return mapping.New(e.Name, e.Value, t).NewEncoder()
+}
+
+// decoder is the transformer that reads all of the bytes before decoding the pages.
+type decoder struct {
+	e   *Encoding
+	src bytes.Buffer // src are the bytes read.
+	dst []byte       // dst is the decoded text that is not yet written.
+	eof bool         // eof reports whether the pages have been decoded.
+}
+
+func (d *decoder) Reset() {
+	d.src.Reset()
+	d.dst, d.eof = nil, false
+}
+
+func (d *decoder) Transform(dst, src []byte, atEOF bool) (int, int, error) {
+	d.src.Write(src)
+	if !atEOF {
+		return 0, len(src), nil
+	}
+	if !d.eof {
+		d.dst = []byte(d.e.Pages(d.src.Bytes()...))
+		d.eof = true
+	}
+	n := copy(dst, d.dst)
+	d.dst = d.dst[n:]
+	if len(d.dst) > 0 {
+		return n, len(src), transform.ErrShortDst
+	}
+	return n, len(src), nil
+}
+
+// cell is a character of the page and its display attributes.
+type cell struct {
+	r    rune
+	attr ansi.Attr
+	tall bool // tall reports whether the character uses double height.
+}
+
+// blankPage returns a page of rows filled with spaces.
+func blankPage(rows int) [][]cell {
+	p := make([][]cell, rows)
+	for i := range p {
+		p[i] = blankRow()
+	}
+	return p
+}
+
+// blankRow returns a row filled with spaces.
+func blankRow() []cell {
+	row := make([]cell, Columns)
+	for i := range row {
+		row[i] = cell{r: ' '}
+	}
+	return row
+}
+
+// render returns the page as text, the trailing blank rows and spaces are removed.
+func render(page [][]cell) string {
+	var sb strings.Builder
+	last := len(page) - 1
+	for last >= 0 && blank(page[last]) {
+		last--
+	}
+	for _, row := range page[:last+1] {
+		end := len(row)
+		for end > 0 && row[end-1].blank() {
+			end--
+		}
+		var attr ansi.Attr
+		for _, c := range row[:end] {
+			if c.attr != attr {
+				sb.WriteString(c.attr.SGR())
+				attr = c.attr
+			}
+			sb.WriteRune(c.r)
+		}
+		if attr != (ansi.Attr{}) {
+			sb.WriteString(ansi.Attr{}.SGR())
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// blank reports whether the cell displays nothing but the default background.
+func (c cell) blank() bool {
+	return c.r == ' ' && c.attr.BG.Mode == ansi.Default && !c.attr.Has(ansi.Inverse)
+}
+
+// blank reports whether the row only contains spaces without any attributes.
+func blank(row []cell) bool {
+	for _, c := range row {
+		if c.r != ' ' || c.attr != (ansi.Attr{}) {
+			return false
+		}
+	}
+	return true
+}
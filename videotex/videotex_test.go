@@ -0,0 +1,245 @@
+package videotex_test
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bengarrett/retrotxtgo/videotex"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/transform"
+)
+
+const reset = "\x1b[0m"
+
+// page returns a raw teletext page of 25 rows, with the rows placed at the top.
+func page(rows ...string) []byte {
+	p := bytes.Repeat([]byte{' '}, videotex.Columns*videotex.Rows)
+	for i, r := range rows {
+		copy(p[i*videotex.Columns:], r)
+	}
+	return p
+}
+
+// odd returns the byte with the odd parity bit set.
+func odd(b byte) byte {
+	p := byte(1)
+	for x := b; x > 0; x >>= 1 {
+		p ^= x & 1
+	}
+	return b | p<<7
+}
+
+// ham returns the Hamming 8/4 byte of the nibble.
+func ham(n byte) byte {
+	d1, d2, d3, d4 := n&1, n>>1&1, n>>2&1, n>>3&1
+	p1 := 1 ^ d1 ^ d3 ^ d4
+	p2 := 1 ^ d1 ^ d2 ^ d4
+	p3 := 1 ^ d1 ^ d2 ^ d3
+	b := p1 | d1<<1 | p2<<2 | d2<<3 | p3<<4 | d3<<5 | d4<<7
+	p4 := byte(1)
+	for x := b; x > 0; x >>= 1 {
+		p4 ^= x & 1
+	}
+	return b | p4<<6
+}
+
+// t42 returns a T42 packet of the magazine and row, with the display text.
+func t42(mag, row byte, text string, control ...byte) []byte {
+	p := make([]byte, 42)
+	p[0], p[1] = ham(mag|row&1<<3), ham(row>>1)
+	data := p[2:]
+	if row == 0 {
+		for i := range 8 {
+			p[2+i] = ham(0)
+		}
+		for i, c := range control {
+			p[8+i] = ham(c)
+		}
+		data = p[10:]
+	}
+	for i := range data {
+		c := byte(' ')
+		if i < len(text) {
+			c = text[i]
+		}
+		data[i] = odd(c)
+	}
+	return p
+}
+
+func ExampleFind() {
+	e := videotex.Find("ceefax")
+	fmt.Println(e, e.Value)
+	s, _ := e.NewDecoder().String(string(page("", "\x01NEWS\x07#1")))
+	fmt.Printf("%q", s)
+	// Output: Teletext teletext
+	// "\n \x1b[0;38;2;255;0;0mNEWS \x1b[0m£1\n"
+}
+
+func TestTeletext(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		e    *videotex.Encoding
+		row  string
+		want string
+	}{
+		{"text", videotex.Teletext, "Hello [#]", "Hello ←£→"},
+		{"german", videotex.Find("teletext-de"), "[\\]~", "ÄÖÜß"},
+		{"mosaic", videotex.Teletext, "\x17\x7f\x35\x6a\x20", " █▌▐"},
+		{"sextant", videotex.Teletext, "\x17\x21\x36\x7e", " \U0001FB00\U0001FB14\U0001FB3B"},
+		{"separated", videotex.Teletext, "\x17\x1a\x21\x7f", "  \U0001CE51\U0001CE8F"},
+		{"blast through", videotex.Teletext, "\x17\x21ABC", " \U0001FB00ABC"},
+		{"hold", videotex.Teletext, "\x17\x1e\x7f\x13\x7f", "  ██\x1b[0;38;2;255;255;0m█" + reset},
+		{"flash", videotex.Teletext, "\x08A\x09B", " \x1b[0;5mA\x1b[0m B"},
+		{"conceal", videotex.Teletext, "\x18A", "\x1b[0;8m A" + reset},
+		{"background", videotex.Teletext, "\x04\x1d\x07A\x1cB",
+			" \x1b[0;38;2;0;0;255;48;2;0;0;255m  \x1b[0;48;2;0;0;255mA\x1b[0m B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			s, err := tt.e.NewDecoder().String(string(page("", tt.row)))
+			be.Err(t, err, nil)
+			be.Equal(t, s, "\n"+tt.want+"\n")
+		})
+	}
+}
+
+func TestDoubleHeight(t *testing.T) {
+	t.Parallel()
+	s := videotex.Teletext.Pages(page("", "\x0dBIG", "ignored", "small")...)
+	be.Equal(t, s, "\n BIG\n BIG\nsmall\n")
+}
+
+func TestT42(t *testing.T) {
+	t.Parallel()
+	var b []byte
+	b = append(b, t42(1, 0, "CEEFAX 1 100")...)
+	b = append(b, t42(1, 1, "First page")...)
+	b = append(b, t42(1, 0, "CEEFAX 1 101", 0, 0b1000)...)
+	b = append(b, t42(1, 1, "Zweite [\\]")...)
+	b = append(b, t42(1, 26, "not displayed")...)
+	be.Equal(t, videotex.Detect("", b...), videotex.T42)
+	s := videotex.Teletext.Pages(b...)
+	be.Equal(t, s, "P100    CEEFAX 1 100\nFirst page\n\n"+
+		"P100    CEEFAX 1 101\nZweite ÄÖÜ\n")
+}
+
+func TestTTI(t *testing.T) {
+	t.Parallel()
+	const s = "DE,Example\r\nPN,10000\r\nOL,1,\x1bAred\x83yellow\r\n" +
+		"PN,10100\r\nOL,0,header\r\nOL,30,ignored\r\n"
+	b := []byte(s)
+	be.Equal(t, videotex.Detect("", b...), videotex.TTI)
+	got := videotex.Teletext.Pages(b...)
+	be.Equal(t, got, "\n \x1b[0;38;2;255;0;0mred \x1b[0;38;2;255;255;0myellow"+reset+"\n\nheader\n")
+}
+
+func TestViewdata(t *testing.T) {
+	t.Parallel()
+	const s = "\x0c\x1bAPRESTEL\x1bG\r\n\x1bT\x7f\x7f\x0cNext\x1e*"
+	b := []byte(s)
+	be.Equal(t, videotex.Detect("", b...), videotex.Viewdata)
+	got := videotex.Prestel.Pages(b...)
+	be.Equal(t, got, " \x1b[0;38;2;255;0;0mPRESTEL"+reset+"\n"+
+		" \x1b[0;38;2;0;0;255m██"+reset+"\n\n*ext\n")
+}
+
+func TestMinitel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"text", "\x0c\x1f\x41\x41Bonjour", "Bonjour\n"},
+		{"position", "\x0c\x1f\x42\x43AB\x1f0102C", " C\n  AB\n"},
+		{"accents", "\x0c\x19\x42e\x19\x4bc\x19\x23", "éç£\n"},
+		{"repeat", "\x0cA\x12\x43", "AAAA\n"},
+		{"color", "\x0c\x1bAred\x1bGwhite", "\x1b[0;38;2;255;0;0mred\x1b[0mwhite\n"},
+		{"background", "\x0c\x1bTA B", "A\x1b[0;48;2;0;0;255m B" + reset + "\n"},
+		{"mosaic", "\x0c\x0e\x21\x7f\x0fA", "\U0001FB00█A\n"},
+		{"protocol", "\x0c\x1b\x3a\x69\x43A\x1b[2JB", "AB\n"},
+		{"pages", "\x0cOne\x0cTwo", "One\n\nTwo\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			be.Equal(t, videotex.Videotex.Pages([]byte(tt.s)...), tt.want)
+		})
+	}
+}
+
+func TestShortDst(t *testing.T) {
+	t.Parallel()
+	b := page("", "\x01RED\x02GREEN\x03YELLOW", "\x17\x7f\x7f\x7f")
+	want := videotex.Teletext.Pages(b...)
+	r := transform.NewReader(bytes.NewReader(b), videotex.Teletext.NewDecoder())
+	var sb strings.Builder
+	p := make([]byte, 7)
+	for {
+		n, err := r.Read(p)
+		sb.Write(p[:n])
+		if err != nil {
+			break
+		}
+	}
+	be.Equal(t, sb.String(), want)
+}
+
+func TestEncoder(t *testing.T) {
+	t.Parallel()
+	s, err := videotex.Teletext.NewEncoder().String("£1 ½")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "#1 \\")
+	s, err = videotex.Find("teletext-fr").NewEncoder().String("été")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "#t#")
+	_, err = videotex.Teletext.NewEncoder().String("é")
+	be.True(t, err != nil)
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		b    string
+		want videotex.Format
+	}{
+		{"page.t42", "", videotex.T42},
+		{"page.TTI", "", videotex.TTI},
+		{"page.vdt", "", videotex.Minitel},
+		{"minitel", "\x0c\x1f\x41\x41Hi\x1f\x42\x41There", videotex.Minitel},
+		{"raw", string(page("", "\x01Red", "\x02Green", "\x16\x7fMosaic")), videotex.Raw},
+		{"raw one row", string(page("", "\x01Red")), videotex.Unknown},
+		{"raw eof", string(page("", "\x01Red", "\x02Green", "\x03Yellow"))[:videotex.Columns*videotex.Rows-1] + "\x1a",
+			videotex.Unknown},
+		{"raw sauce", string(page("", "\x01Red", "\x02Green", "\x03Yellow"))[:videotex.Columns*videotex.Rows-128] +
+			"SAUCE00" + strings.Repeat(" ", 121), videotex.Unknown},
+		{"readme", string(bytes.Repeat([]byte("\x80 readme text with \x9a letters.\n"), 40)[:999]) + "\x1a",
+			videotex.Unknown},
+		{"readme no eof", string(bytes.Repeat([]byte("A readme text with \x9a letters.\n"), 40)[:1000]),
+			videotex.Unknown},
+		{"text", "Hello world\r\n", videotex.Unknown},
+		{"ansi", "\x1b[0;31mRed\x1bA\x1bB\x1bC", videotex.Unknown},
+		{"empty", "", videotex.Unknown},
+	}
+	for _, tt := range tests {
+		be.Equal(t, videotex.Detect(tt.name, []byte(tt.b)...), tt.want)
+	}
+	be.Equal(t, videotex.T42.String(), "Teletext T42 packet stream")
+	be.Equal(t, videotex.Unknown.String(), "")
+}
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	for _, e := range videotex.All() {
+		be.Equal(t, videotex.Find(strings.ToUpper(e.Value)).Name, e.Name)
+	}
+	be.Equal(t, videotex.Find("prestel"), videotex.Prestel)
+	be.Equal(t, videotex.Find("Minitel videotex"), videotex.Videotex)
+	be.True(t, videotex.Find("cp437") == nil)
+}
@@ -0,0 +1,94 @@
+package videotex
+
+import "bytes"
+
+// frameRows is the number of rows of a Prestel frame.
+const frameRows = 24
+
+// viewdata returns the pages of the Prestel viewdata frames.
+// The frames are drawn onto a grid using the cursor control codes, where the escape
+// character followed by 0x40 to 0x5F places a spacing attribute.
+// The grid is then decoded as a teletext page, a form feed starts a new frame.
+func viewdata(b ...byte) [][][]cell {
+	var pages [][][]cell
+	grid := frame()
+	x, y, used := 0, 0, false
+	flush := func() {
+		if used {
+			pages = append(pages, decode(english, grid))
+		}
+		grid, x, y, used = frame(), 0, 0, false
+	}
+	put := func(c byte) {
+		grid[y][x] = c
+		used = true
+		x++
+		if x >= Columns {
+			x, y = 0, (y+1)%frameRows
+		}
+	}
+	for i := 0; i < len(b); i++ {
+		c := b[i] & 0x7f
+		switch {
+		case c == esc && i+1 < len(b):
+			i++
+			put(b[i] & 0x1f)
+		case b[i] >= 0x80 && c < 0x20:
+			// some frames store the spacing attributes using the high bit
+			put(c)
+		case c >= 0x20:
+			put(c)
+		case c == 0x08:
+			x--
+			if x < 0 {
+				x, y = Columns-1, (y+frameRows-1)%frameRows
+			}
+		case c == 0x09:
+			x++
+			if x >= Columns {
+				x, y = 0, (y+1)%frameRows
+			}
+		case c == '\n':
+			y = (y + 1) % frameRows
+		case c == 0x0b:
+			y = (y + frameRows - 1) % frameRows
+		case c == '\r':
+			x = 0
+		case c == 0x0c:
+			flush()
+		case c == 0x1e:
+			x, y = 0, 0
+		}
+	}
+	flush()
+	return pages
+}
+
+// frame returns an empty Prestel frame filled with spaces.
+func frame() [][]byte {
+	f := make([][]byte, frameRows)
+	for i := range f {
+		f[i] = bytes.Repeat([]byte{' '}, Columns)
+	}
+	return f
+}
+
+// isViewdata reports whether the bytes are Prestel frames,
+// that use the escape character to place the spacing attributes but no ANSI sequences.
+func isViewdata(b []byte) bool {
+	const least = 3
+	n := 0
+	for i := 0; i+1 < len(b); i++ {
+		if b[i] != esc {
+			continue
+		}
+		c := b[i+1]
+		if c == '[' {
+			return false
+		}
+		if c >= 0x40 && c <= 0x5f {
+			n++
+		}
+	}
+	return n >= least
+}
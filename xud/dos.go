@@ -0,0 +1,192 @@
+package xud
+
+import (
+	"golang.org/x/text/encoding"
+)
+
+// Named, numeric and alias values for the DOS national code pages.
+const (
+	Name737        = "cp737"     // name of IBM Code Page 737
+	Name775        = "cp775"     // name of IBM Code Page 775
+	Name857        = "cp857"     // name of IBM Code Page 857
+	Name861        = "cp861"     // name of IBM Code Page 861
+	Name864        = "cp864"     // name of IBM Code Page 864
+	Name869        = "cp869"     // name of IBM Code Page 869
+	NameKamenicky  = "kamenicky" // name of Kamenický
+	NameMazovia    = "mazovia"   // name of Mazovia
+	NameMIK        = "mik"       // name of MIK
+	Numr737        = "737"       // numeric value for IBM Code Page 737
+	Numr775        = "775"       // numeric value for IBM Code Page 775
+	Numr857        = "857"       // numeric value for IBM Code Page 857
+	Numr861        = "861"       // numeric value for IBM Code Page 861
+	Numr864        = "864"       // numeric value for IBM Code Page 864
+	Numr869        = "869"       // numeric value for IBM Code Page 869
+	NumrKamenicky  = "895"       // numeric value for Kamenický
+	NumrMazovia    = "667"       // numeric value for Mazovia
+	Alias737       = "ibm737"    // alias for IBM Code Page 737
+	Alias775       = "ibm775"    // alias for IBM Code Page 775
+	Alias857       = "ibm857"    // alias for IBM Code Page 857
+	Alias861       = "ibm861"    // alias for IBM Code Page 861
+	Alias864       = "ibm864"    // alias for IBM Code Page 864
+	Alias869       = "ibm869"    // alias for IBM Code Page 869
+	AliasKamenicky = "keybcs2"   // alias for Kamenický
+	AliasMazovia   = "cp790"     // alias for Mazovia
+	AliasMIK       = "bulgarian" // alias for MIK
+)
+
+var (
+	// XUserDefined737 IBM Code Page 737, DOS Greek.
+	XUserDefined737 encoding.Encoding = &x737
+	// XUserDefined775 IBM Code Page 775, DOS Baltic Rim.
+	XUserDefined775 encoding.Encoding = &x775
+	// XUserDefined857 IBM Code Page 857, DOS Turkish.
+	XUserDefined857 encoding.Encoding = &x857
+	// XUserDefined861 IBM Code Page 861, DOS Icelandic.
+	XUserDefined861 encoding.Encoding = &x861
+	// XUserDefined864 IBM Code Page 864, DOS Arabic.
+	XUserDefined864 encoding.Encoding = &x864
+	// XUserDefined869 IBM Code Page 869, DOS Greek 2.
+	XUserDefined869 encoding.Encoding = &x869
+	// XUserDefinedKamenicky Kamenický, DOS Czech and Slovak.
+	XUserDefinedKamenicky encoding.Encoding = &xKamenicky
+	// XUserDefinedMazovia Mazovia, DOS Polish.
+	XUserDefinedMazovia encoding.Encoding = &xMazovia
+	// XUserDefinedMIK MIK, DOS Bulgarian.
+	XUserDefinedMIK encoding.Encoding = &xMIK
+
+	x737       = named("IBM Code Page 737", Name737, upper(upper737))
+	x775       = named("IBM Code Page 775", Name775, upper(upper775))
+	x857       = named("IBM Code Page 857", Name857, upper(upper857))
+	x861       = named("IBM Code Page 861", Name861, upper(upper861))
+	x864       = named("IBM Code Page 864", Name864, arabic())
+	x869       = named("IBM Code Page 869", Name869, upper(upper869))
+	xKamenicky = named("Kamenický", NameKamenicky, upper(upperKamenicky))
+	xMazovia   = named("Mazovia", NameMazovia, upper(upperMazovia))
+	xMIK       = named("MIK", NameMIK, upper(upperMIK))
+)
+
+// DOS returns the DOS national code pages that are missing from the charmap package.
+func DOS() []encoding.Encoding {
+	return []encoding.Encoding{
+		XUserDefined737,
+		XUserDefined775,
+		XUserDefined857,
+		XUserDefined861,
+		XUserDefined864,
+		XUserDefined869,
+		XUserDefinedKamenicky,
+		XUserDefinedMazovia,
+		XUserDefinedMIK,
+	}
+}
+
+// CodeDOS reports whether the encoding is one of the DOS national code pages.
+func CodeDOS(e encoding.Encoding) bool {
+	switch e {
+	case XUserDefined737, XUserDefined775, XUserDefined857,
+		XUserDefined861, XUserDefined864, XUserDefined869,
+		XUserDefinedKamenicky, XUserDefinedMazovia, XUserDefinedMIK:
+		return true
+	}
+	return false
+}
+
+// upper returns a table that uses the ASCII characters for the lower 128 codes,
+// and the 128 runes of the string for the upper codes.
+func upper(s string) [256]rune {
+	var table [256]rune
+	for i := range 128 {
+		table[i] = rune(i)
+	}
+	i := 128
+	for _, r := range s {
+		table[i] = r
+		i++
+	}
+	return table
+}
+
+// arabic returns the table for IBM Code Page 864,
+// which also replaces the ASCII percent sign.
+func arabic() [256]rune {
+	table := upper(upper864)
+	table['%'] = '\u066A' // ARABIC PERCENT SIGN
+	return table
+}
+
+// The upper 128 characters of the DOS national code pages, listed by row.
+// The Unicode replacement character is used for the unused codes.
+const (
+	upper737 = "ΑΒΓΔΕΖΗΘΙΚΛΜΝΞΟΠ" + // 8
+		"ΡΣΤΥΦΧΨΩαβγδεζηθ" + // 9
+		"ικλμνξοπρσςτυφχψ" + // A
+		"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" + // B
+		"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" + // C
+		"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" + // D
+		"ωάέήϊίόύϋώΆΈΉΊΌΎ" + // E
+		"Ώ±≥≤ΪΫ÷≈°∙·√ⁿ²■\u00A0" // F
+	upper775 = "ĆüéāäģåćłēŖŗīŹÄÅ" + // 8
+		"ÉæÆōöĢ¢ŚśÖÜø£Ø×¤" + // 9
+		"ĀĪóŻżź”¦©®¬½¼Ł«»" + // A
+		"░▒▓│┤ĄČĘĖ╣║╗╝ĮŠ┐" + // B
+		"└┴┬├─┼ŲŪ╚╔╩╦╠═╬Ž" + // C
+		"ąčęėįšųūž┘┌█▄▌▐▀" + // D
+		"ÓßŌŃõÕµńĶķĻļņĒŅ’" + // E
+		"\u00AD±“¾¶§÷„°∙·¹³²■\u00A0" // F
+	upper857 = "ÇüéâäàåçêëèïîıÄÅ" + // 8
+		"ÉæÆôöòûùİÖÜø£ØŞş" + // 9
+		"áíóúñÑĞğ¿®¬½¼¡«»" + // A
+		"░▒▓│┤ÁÂÀ©╣║╗╝¢¥┐" + // B
+		"└┴┬├─┼ãÃ╚╔╩╦╠═╬¤" + // C
+		"ºªÊËÈ\uFFFDÍÎÏ┘┌█▄¦Ì▀" + // D
+		"ÓßÔÒõÕµ\uFFFD×ÚÛÙìÿ¯´" + // E
+		"\u00AD±\uFFFD¾¶§÷¸°¨·¹³²■\u00A0" // F
+	upper861 = "ÇüéâäàåçêëèÐðÞÄÅ" + // 8
+		"ÉæÆôöþûÝýÖÜø£Ø₧ƒ" + // 9
+		"áíóúÁÍÓÚ¿⌐¬½¼¡«»" + // A
+		"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" + // B
+		"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" + // C
+		"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" + // D
+		"αßΓπΣσµτΦΘΩδ∞φε∩" + // E
+		"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00A0" // F
+	upper864 = "°·∙√▒─│┼┤┬├┴┐┌└┘" + // 8
+		"β∞φ±½¼≈«»\uFEF7\uFEF8\uFFFD\uFFFD\uFEFB\uFEFC\uFFFD" + // 9
+		"\u00A0\u00AD\uFE82£¤\uFE84\uFFFD\uFFFD\uFE8E\uFE8F\uFE95\uFE99،\uFE9D\uFEA1\uFEA5" + // A
+		"٠١٢٣٤٥٦٧٨٩\uFED1؛\uFEB1\uFEB5\uFEB9؟" + // B
+		"¢\uFE80\uFE81\uFE83\uFE85\uFECA\uFE8B\uFE8D\uFE91\uFE93\uFE97\uFE9B\uFE9F\uFEA3\uFEA7\uFEA9" + // C
+		"\uFEAB\uFEAD\uFEAF\uFEB3\uFEB7\uFEBB\uFEBF\uFEC1\uFEC5\uFECB\uFECF¦¬÷×\uFEC9" + // D
+		"\u0640\uFED3\uFED7\uFEDB\uFEDF\uFEE3\uFEE7\uFEEB\uFEED\uFEEF\uFEF3\uFEBD\uFECC\uFECE\uFECD\uFEE1" + // E
+		"\uFE7D\u0651\uFEE5\uFEE9\uFEEC\uFEF0\uFEF2\uFED0\uFED5\uFEF5\uFEF6\uFEDD\uFED9\uFEF1■\uFFFD" // F
+	upper869 = "\uFFFD\uFFFD\uFFFD\uFFFD\uFFFD\uFFFDΆ\uFFFD·¬¦‘’Έ―Ή" + // 8
+		"ΊΪΌ\uFFFD\uFFFDΎΫ©Ώ²³ά£έήί" + // 9
+		"ϊΐόύΑΒΓΔΕΖΗ½ΘΙ«»" + // A
+		"░▒▓│┤ΚΛΜΝ╣║╗╝ΞΟ┐" + // B
+		"└┴┬├─┼ΠΡ╚╔╩╦╠═╬Σ" + // C
+		"ΤΥΦΧΨΩαβγ┘┌█▄δε▀" + // D
+		"ζηθικλμνξοπρσςτ΄" + // E
+		"\u00AD±υφχ§ψ΅°¨ωϋΰώ■\u00A0" // F
+	upperKamenicky = "ČüéďäĎŤčěĚĹÍľĺÄÁ" + // 8
+		"ÉžŽôöÓůÚýÖÜŠĽÝŘť" + // 9
+		"áíóúňŇŮÔšřŕŔ¼§«»" + // A
+		"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" + // B
+		"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" + // C
+		"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" + // D
+		"αßΓπΣσµτΦΘΩδ∞φε∩" + // E
+		"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00A0" // F
+	upperMazovia = "ÇüéâäàąçêëèïîćÄĄ" + // 8
+		"ĘęłôöĆûùŚÖÜ¢Ł¥śƒ" + // 9
+		"ŹŻóÓńŃźż¿⌐¬½¼¡«»" + // A
+		"░▒▓│┤╡╢╖╕╣║╗╝╜╛┐" + // B
+		"└┴┬├─┼╞╟╚╔╩╦╠═╬╧" + // C
+		"╨╤╥╙╘╒╓╫╪┘┌█▄▌▐▀" + // D
+		"αßΓπΣσµτΦΘΩδ∞φε∩" + // E
+		"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00A0" // F
+	upperMIK = "АБВГДЕЖЗИЙКЛМНОП" + // 8
+		"РСТУФХЦЧШЩЪЫЬЭЮЯ" + // 9
+		"абвгдежзийклмноп" + // A
+		"рстуфхцчшщъыьэюя" + // B
+		"└┴┬├─┼╣║╚╔╩╦╠═╬┐" + // C
+		"░▒▓│┤№§╗╝┘┌█▄▌▐▀" + // D
+		"αßΓπΣσµτΦΘΩδ∞φε∩" + // E
+		"≡±≥≤⌠⌡÷≈°∙·√ⁿ²■\u00A0" // F
+)
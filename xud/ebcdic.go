@@ -0,0 +1,433 @@
+package xud
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+// Named, numeric and alias values for the national and Euro EBCDIC code pages.
+const (
+	Name273   = "cp273"    // name of IBM Code Page 273
+	Name277   = "cp277"    // name of IBM Code Page 277
+	Name278   = "cp278"    // name of IBM Code Page 278
+	Name280   = "cp280"    // name of IBM Code Page 280
+	Name284   = "cp284"    // name of IBM Code Page 284
+	Name285   = "cp285"    // name of IBM Code Page 285
+	Name297   = "cp297"    // name of IBM Code Page 297
+	Name500   = "cp500"    // name of IBM Code Page 500
+	Name871   = "cp871"    // name of IBM Code Page 871
+	Name1141  = "cp1141"   // name of IBM Code Page 1141
+	Name1142  = "cp1142"   // name of IBM Code Page 1142
+	Name1143  = "cp1143"   // name of IBM Code Page 1143
+	Name1144  = "cp1144"   // name of IBM Code Page 1144
+	Name1145  = "cp1145"   // name of IBM Code Page 1145
+	Name1146  = "cp1146"   // name of IBM Code Page 1146
+	Name1147  = "cp1147"   // name of IBM Code Page 1147
+	Name1148  = "cp1148"   // name of IBM Code Page 1148
+	Name1149  = "cp1149"   // name of IBM Code Page 1149
+	Numr273   = "273"      // numeric value for IBM Code Page 273
+	Numr277   = "277"      // numeric value for IBM Code Page 277
+	Numr278   = "278"      // numeric value for IBM Code Page 278
+	Numr280   = "280"      // numeric value for IBM Code Page 280
+	Numr284   = "284"      // numeric value for IBM Code Page 284
+	Numr285   = "285"      // numeric value for IBM Code Page 285
+	Numr297   = "297"      // numeric value for IBM Code Page 297
+	Numr500   = "500"      // numeric value for IBM Code Page 500
+	Numr871   = "871"      // numeric value for IBM Code Page 871
+	Numr1141  = "1141"     // numeric value for IBM Code Page 1141
+	Numr1142  = "1142"     // numeric value for IBM Code Page 1142
+	Numr1143  = "1143"     // numeric value for IBM Code Page 1143
+	Numr1144  = "1144"     // numeric value for IBM Code Page 1144
+	Numr1145  = "1145"     // numeric value for IBM Code Page 1145
+	Numr1146  = "1146"     // numeric value for IBM Code Page 1146
+	Numr1147  = "1147"     // numeric value for IBM Code Page 1147
+	Numr1148  = "1148"     // numeric value for IBM Code Page 1148
+	Numr1149  = "1149"     // numeric value for IBM Code Page 1149
+	Alias273  = "ibm273"   // alias for IBM Code Page 273
+	Alias277  = "ibm277"   // alias for IBM Code Page 277
+	Alias278  = "ibm278"   // alias for IBM Code Page 278
+	Alias280  = "ibm280"   // alias for IBM Code Page 280
+	Alias284  = "ibm284"   // alias for IBM Code Page 284
+	Alias285  = "ibm285"   // alias for IBM Code Page 285
+	Alias297  = "ibm297"   // alias for IBM Code Page 297
+	Alias500  = "ibm500"   // alias for IBM Code Page 500
+	Alias871  = "ibm871"   // alias for IBM Code Page 871
+	Alias1141 = "ibm01141" // alias for IBM Code Page 1141
+	Alias1142 = "ibm01142" // alias for IBM Code Page 1142
+	Alias1143 = "ibm01143" // alias for IBM Code Page 1143
+	Alias1144 = "ibm01144" // alias for IBM Code Page 1144
+	Alias1145 = "ibm01145" // alias for IBM Code Page 1145
+	Alias1146 = "ibm01146" // alias for IBM Code Page 1146
+	Alias1147 = "ibm01147" // alias for IBM Code Page 1147
+	Alias1148 = "ibm01148" // alias for IBM Code Page 1148
+	Alias1149 = "ibm01149" // alias for IBM Code Page 1149
+)
+
+var (
+	// XUserDefined273 IBM Code Page 273, EBCDIC Germany and Austria.
+	XUserDefined273 encoding.Encoding = &x273
+	// XUserDefined277 IBM Code Page 277, EBCDIC Denmark and Norway.
+	XUserDefined277 encoding.Encoding = &x277
+	// XUserDefined278 IBM Code Page 278, EBCDIC Finland and Sweden.
+	XUserDefined278 encoding.Encoding = &x278
+	// XUserDefined280 IBM Code Page 280, EBCDIC Italy.
+	XUserDefined280 encoding.Encoding = &x280
+	// XUserDefined284 IBM Code Page 284, EBCDIC Spain and Latin America.
+	XUserDefined284 encoding.Encoding = &x284
+	// XUserDefined285 IBM Code Page 285, EBCDIC United Kingdom.
+	XUserDefined285 encoding.Encoding = &x285
+	// XUserDefined297 IBM Code Page 297, EBCDIC France.
+	XUserDefined297 encoding.Encoding = &x297
+	// XUserDefined500 IBM Code Page 500, EBCDIC International Latin 1.
+	XUserDefined500 encoding.Encoding = &x500
+	// XUserDefined871 IBM Code Page 871, EBCDIC Iceland.
+	XUserDefined871 encoding.Encoding = &x871
+	// XUserDefined1141 IBM Code Page 1141, EBCDIC Germany and Austria plus €.
+	XUserDefined1141 encoding.Encoding = &x1141
+	// XUserDefined1142 IBM Code Page 1142, EBCDIC Denmark and Norway plus €.
+	XUserDefined1142 encoding.Encoding = &x1142
+	// XUserDefined1143 IBM Code Page 1143, EBCDIC Finland and Sweden plus €.
+	XUserDefined1143 encoding.Encoding = &x1143
+	// XUserDefined1144 IBM Code Page 1144, EBCDIC Italy plus €.
+	XUserDefined1144 encoding.Encoding = &x1144
+	// XUserDefined1145 IBM Code Page 1145, EBCDIC Spain and Latin America plus €.
+	XUserDefined1145 encoding.Encoding = &x1145
+	// XUserDefined1146 IBM Code Page 1146, EBCDIC United Kingdom plus €.
+	XUserDefined1146 encoding.Encoding = &x1146
+	// XUserDefined1147 IBM Code Page 1147, EBCDIC France plus €.
+	XUserDefined1147 encoding.Encoding = &x1147
+	// XUserDefined1148 IBM Code Page 1148, EBCDIC International Latin 1 plus €.
+	XUserDefined1148 encoding.Encoding = &x1148
+	// XUserDefined1149 IBM Code Page 1149, EBCDIC Iceland plus €.
+	XUserDefined1149 encoding.Encoding = &x1149
+
+	x273  = ebcdic("IBM Code Page 273", Name273, national273)
+	x277  = ebcdic("IBM Code Page 277", Name277, national277)
+	x278  = ebcdic("IBM Code Page 278", Name278, national278)
+	x280  = ebcdic("IBM Code Page 280", Name280, national280)
+	x284  = ebcdic("IBM Code Page 284", Name284, national284)
+	x285  = ebcdic("IBM Code Page 285", Name285, national285)
+	x297  = ebcdic("IBM Code Page 297", Name297, national297)
+	x500  = ebcdic("IBM Code Page 500", Name500, national500)
+	x871  = ebcdic("IBM Code Page 871", Name871, national871)
+	x1141 = ebcdic("IBM Code Page 1141", Name1141, euro(national273, euroCurrency))
+	x1142 = ebcdic("IBM Code Page 1142", Name1142, euro(national277, euroNordic))
+	x1143 = ebcdic("IBM Code Page 1143", Name1143, euro(national278, euroNordic))
+	x1144 = ebcdic("IBM Code Page 1144", Name1144, euro(national280, euroCurrency))
+	x1145 = ebcdic("IBM Code Page 1145", Name1145, euro(national284, euroCurrency))
+	x1146 = ebcdic("IBM Code Page 1146", Name1146, euro(national285, euroCurrency))
+	x1147 = ebcdic("IBM Code Page 1147", Name1147, euro(national297, euroCurrency))
+	x1148 = ebcdic("IBM Code Page 1148", Name1148, euro(national500, euroCurrency))
+	x1149 = ebcdic("IBM Code Page 1149", Name1149, euro(national871, euroCurrency))
+)
+
+// EBCDIC returns the national and Euro EBCDIC code pages that are missing from the charmap package.
+func EBCDIC() []encoding.Encoding {
+	return []encoding.Encoding{
+		XUserDefined273,
+		XUserDefined277,
+		XUserDefined278,
+		XUserDefined280,
+		XUserDefined284,
+		XUserDefined285,
+		XUserDefined297,
+		XUserDefined500,
+		XUserDefined871,
+		XUserDefined1141,
+		XUserDefined1142,
+		XUserDefined1143,
+		XUserDefined1144,
+		XUserDefined1145,
+		XUserDefined1146,
+		XUserDefined1147,
+		XUserDefined1148,
+		XUserDefined1149,
+	}
+}
+
+// CodeEBCDIC reports whether the encoding is one of the national or Euro EBCDIC code pages.
+func CodeEBCDIC(e encoding.Encoding) bool {
+	switch e {
+	case XUserDefined273, XUserDefined277, XUserDefined278,
+		XUserDefined280, XUserDefined284, XUserDefined285,
+		XUserDefined297, XUserDefined500, XUserDefined871,
+		XUserDefined1141, XUserDefined1142, XUserDefined1143,
+		XUserDefined1144, XUserDefined1145, XUserDefined1146,
+		XUserDefined1147, XUserDefined1148, XUserDefined1149:
+		return true
+	}
+	return false
+}
+
+// codePageEBCDIC returns the national or Euro EBCDIC code page of the name, numeric value or alias.
+func codePageEBCDIC(name string) encoding.Encoding {
+	switch strings.ToLower(name) {
+	case Name273, Numr273, Alias273:
+		return XUserDefined273
+	case Name277, Numr277, Alias277:
+		return XUserDefined277
+	case Name278, Numr278, Alias278:
+		return XUserDefined278
+	case Name280, Numr280, Alias280:
+		return XUserDefined280
+	case Name284, Numr284, Alias284:
+		return XUserDefined284
+	case Name285, Numr285, Alias285:
+		return XUserDefined285
+	case Name297, Numr297, Alias297:
+		return XUserDefined297
+	case Name500, Numr500, Alias500:
+		return XUserDefined500
+	case Name871, Numr871, Alias871:
+		return XUserDefined871
+	case Name1141, Numr1141, Alias1141:
+		return XUserDefined1141
+	case Name1142, Numr1142, Alias1142:
+		return XUserDefined1142
+	case Name1143, Numr1143, Alias1143:
+		return XUserDefined1143
+	case Name1144, Numr1144, Alias1144:
+		return XUserDefined1144
+	case Name1145, Numr1145, Alias1145:
+		return XUserDefined1145
+	case Name1146, Numr1146, Alias1146:
+		return XUserDefined1146
+	case Name1147, Numr1147, Alias1147:
+		return XUserDefined1147
+	case Name1148, Numr1148, Alias1148:
+		return XUserDefined1148
+	case Name1149, Numr1149, Alias1149:
+		return XUserDefined1149
+	}
+	for _, e := range EBCDIC() {
+		if strings.EqualFold(name, fmt.Sprint(e)) {
+			return e
+		}
+	}
+	return nil
+}
+
+// nameEBCDIC returns the name of the national or Euro EBCDIC code page.
+func nameEBCDIC(e encoding.Encoding) string {
+	switch e {
+	case XUserDefined273:
+		return Name273
+	case XUserDefined277:
+		return Name277
+	case XUserDefined278:
+		return Name278
+	case XUserDefined280:
+		return Name280
+	case XUserDefined284:
+		return Name284
+	case XUserDefined285:
+		return Name285
+	case XUserDefined297:
+		return Name297
+	case XUserDefined500:
+		return Name500
+	case XUserDefined871:
+		return Name871
+	case XUserDefined1141:
+		return Name1141
+	case XUserDefined1142:
+		return Name1142
+	case XUserDefined1143:
+		return Name1143
+	case XUserDefined1144:
+		return Name1144
+	case XUserDefined1145:
+		return Name1145
+	case XUserDefined1146:
+		return Name1146
+	case XUserDefined1147:
+		return Name1147
+	case XUserDefined1148:
+		return Name1148
+	case XUserDefined1149:
+		return Name1149
+	}
+	return ""
+}
+
+// numericEBCDIC returns the numeric value of the national or Euro EBCDIC code page.
+func numericEBCDIC(e encoding.Encoding) string {
+	switch e {
+	case XUserDefined273:
+		return Numr273
+	case XUserDefined277:
+		return Numr277
+	case XUserDefined278:
+		return Numr278
+	case XUserDefined280:
+		return Numr280
+	case XUserDefined284:
+		return Numr284
+	case XUserDefined285:
+		return Numr285
+	case XUserDefined297:
+		return Numr297
+	case XUserDefined500:
+		return Numr500
+	case XUserDefined871:
+		return Numr871
+	case XUserDefined1141:
+		return Numr1141
+	case XUserDefined1142:
+		return Numr1142
+	case XUserDefined1143:
+		return Numr1143
+	case XUserDefined1144:
+		return Numr1144
+	case XUserDefined1145:
+		return Numr1145
+	case XUserDefined1146:
+		return Numr1146
+	case XUserDefined1147:
+		return Numr1147
+	case XUserDefined1148:
+		return Numr1148
+	case XUserDefined1149:
+		return Numr1149
+	}
+	return ""
+}
+
+// aliasEBCDIC returns the alias of the national or Euro EBCDIC code page.
+func aliasEBCDIC(e encoding.Encoding) string {
+	switch e {
+	case XUserDefined273:
+		return Alias273
+	case XUserDefined277:
+		return Alias277
+	case XUserDefined278:
+		return Alias278
+	case XUserDefined280:
+		return Alias280
+	case XUserDefined284:
+		return Alias284
+	case XUserDefined285:
+		return Alias285
+	case XUserDefined297:
+		return Alias297
+	case XUserDefined500:
+		return Alias500
+	case XUserDefined871:
+		return Alias871
+	case XUserDefined1141:
+		return Alias1141
+	case XUserDefined1142:
+		return Alias1142
+	case XUserDefined1143:
+		return Alias1143
+	case XUserDefined1144:
+		return Alias1144
+	case XUserDefined1145:
+		return Alias1145
+	case XUserDefined1146:
+		return Alias1146
+	case XUserDefined1147:
+		return Alias1147
+	case XUserDefined1148:
+		return Alias1148
+	case XUserDefined1149:
+		return Alias1149
+	}
+	return ""
+}
+
+// ebcdic returns the named encoding that uses the IBM Code Page 037 table,
+// with the national characters replacing the codes of the US and Canada characters.
+func ebcdic(name, value string, national map[byte]rune) Encoding {
+	var table [256]rune
+	for i := range table {
+		table[i] = charmap.CodePage037.DecodeByte(byte(i))
+	}
+	for b, r := range national {
+		table[b] = r
+	}
+	return named(name, value, table)
+}
+
+// euro returns a copy of the national characters with the euro sign at the code.
+func euro(national map[byte]rune, code byte) map[byte]rune {
+	m := maps.Clone(national)
+	m[code] = '€'
+	return m
+}
+
+// The codes of the euro sign in the Euro EBCDIC code pages,
+// it always replaces the currency sign that the Nordic pages keep at a different code.
+const (
+	euroCurrency byte = 0x9F
+	euroNordic   byte = 0x5A
+)
+
+// The national characters of the EBCDIC code pages,
+// listed by the code that differs from IBM Code Page 037.
+var (
+	// Germany and Austria
+	national273 = map[byte]rune{
+		0x43: '{', 0x4A: 'Ä', 0x4F: '!', 0x59: '~', 0x5A: 'Ü', 0x5F: '^',
+		0x63: '[', 0x6A: 'ö', 0x7C: '§', 0xA1: 'ß', 0xB0: '¢', 0xB5: '@',
+		0xBA: '¬', 0xBB: '|', 0xC0: 'ä', 0xCC: '¦', 0xD0: 'ü', 0xDC: '}',
+		0xE0: 'Ö', 0xEC: '\\', 0xFC: ']',
+	}
+	// Denmark and Norway
+	national277 = map[byte]rune{
+		0x47: '}', 0x4A: '#', 0x4F: '!', 0x5A: '¤', 0x5B: 'Å', 0x5F: '^',
+		0x67: '$', 0x6A: 'ø', 0x70: '¦', 0x7B: 'Æ', 0x7C: 'Ø', 0x80: '@',
+		0x9C: '{', 0x9E: '[', 0x9F: ']', 0xA1: 'ü', 0xB0: '¢', 0xBA: '¬',
+		0xBB: '|', 0xC0: 'æ', 0xD0: 'å', 0xDC: '~',
+	}
+	// Finland and Sweden
+	national278 = map[byte]rune{
+		0x43: '{', 0x47: '}', 0x4A: '§', 0x4F: '!', 0x51: '`', 0x5A: '¤',
+		0x5B: 'Å', 0x5F: '^', 0x63: '#', 0x67: '$', 0x6A: 'ö', 0x71: '\\',
+		0x79: 'é', 0x7B: 'Ä', 0x7C: 'Ö', 0x9F: ']', 0xA1: 'ü', 0xB0: '¢',
+		0xB5: '[', 0xBA: '¬', 0xBB: '|', 0xC0: 'ä', 0xCC: '¦', 0xD0: 'å',
+		0xDC: '~', 0xE0: 'É', 0xEC: '@',
+	}
+	// Italy
+	national280 = map[byte]rune{
+		0x44: '{', 0x48: '\\', 0x4A: '°', 0x4F: '!', 0x51: ']', 0x54: '}',
+		0x58: '~', 0x5A: 'é', 0x5F: '^', 0x6A: 'ò', 0x79: 'ù', 0x7B: '£',
+		0x7C: '§', 0x90: '[', 0xA1: 'ì', 0xB0: '¢', 0xB1: '#', 0xB5: '@',
+		0xBA: '¬', 0xBB: '|', 0xC0: 'à', 0xCD: '¦', 0xD0: 'è', 0xDD: '`',
+		0xE0: 'ç',
+	}
+	// Spain and Latin America
+	national284 = map[byte]rune{
+		0x49: '¦', 0x4A: '[', 0x5A: ']', 0x69: '#', 0x6A: 'ñ', 0x7B: 'Ñ',
+		0xA1: '¨', 0xB0: '¢', 0xBA: '^', 0xBB: '!', 0xBD: '~',
+	}
+	// United Kingdom
+	national285 = map[byte]rune{
+		0x4A: '$', 0x5B: '£', 0xA1: '¯', 0xB0: '¢', 0xB1: '[', 0xBA: '^',
+		0xBC: '~',
+	}
+	// France
+	national297 = map[byte]rune{
+		0x44: '@', 0x48: '\\', 0x4A: '°', 0x4F: '!', 0x51: '{', 0x54: '}',
+		0x5A: '§', 0x5F: '^', 0x6A: 'ù', 0x79: 'µ', 0x7B: '£', 0x7C: 'à',
+		0x90: '[', 0xA0: '`', 0xA1: '¨', 0xB0: '¢', 0xB1: '#', 0xB5: ']',
+		0xBA: '¬', 0xBB: '|', 0xBD: '~', 0xC0: 'é', 0xD0: 'è', 0xDD: '¦',
+		0xE0: 'ç',
+	}
+	// International Latin 1
+	national500 = map[byte]rune{
+		0x4A: '[', 0x4F: '!', 0x5A: ']', 0x5F: '^', 0xB0: '¢', 0xBA: '¬',
+		0xBB: '|',
+	}
+	// Iceland
+	national871 = map[byte]rune{
+		0x4A: 'Þ', 0x4F: '!', 0x5A: 'Æ', 0x5F: 'Ö', 0x79: 'ð', 0x7C: 'Ð',
+		0x8C: '`', 0x8E: '{', 0x9C: '}', 0x9E: ']', 0xA1: 'ö', 0xAC: '@',
+		0xAE: '[', 0xB0: '¢', 0xBA: '¬', 0xBB: '|', 0xBE: '\\', 0xC0: 'þ',
+		0xCC: '~', 0xD0: 'æ', 0xE0: '´', 0xEC: '^',
+	}
+)
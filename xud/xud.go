@@ -1,12 +1,18 @@
 // Package xud provides X User Defined, character encodings.
-// It does not encode or decode text, only provides information about the encodings.
 //
 // This includes the early American Standards Association (ASA) ASCII character encodings.
 // There are three ASA encodings, X3.4-1963, X3.4-1965, X3.4-1967 and one missing ISO 8859-11 encoding.
 // These encodings are not compatible with each other.
+// The ASA encodings only use 7-bits, so any 8-bit codes decode to the Unicode replacement character.
 //
 // But the X3.4-1967 character codes are compatible with the ANSI X3.4-1977 and ANSI X3.4-1986 encodings.
 // Which are also compatible with many of the IBM Code Page and ISO 8859-X encodings, as-well as Unicode.
+//
+// It also includes the DOS national code pages that are missing from the charmap package,
+// IBM Code Pages 737, 775, 857, 861, 864 and 869, Kamenický, Mazovia and MIK.
+// And the national and Euro EBCDIC code pages used by IBM mainframes,
+// IBM Code Pages 273, 277, 278, 280, 284, 285, 297, 500 and 871, plus 1141 to 1149.
+// All these encodings do encode and decode text.
 package xud
 
 import (
@@ -14,7 +20,9 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"unicode/utf8"
 
+	"github.com/bengarrett/retrotxtgo/mapping"
 	"golang.org/x/text/encoding"
 	"golang.org/x/text/encoding/charmap"
 )
@@ -53,24 +61,74 @@ var (
 	// XUserDefined1967 ANSI X3.4 1967/77/86.
 	XUserDefined1967 encoding.Encoding = &x34_1967
 
-	xThaiISO11 = Encoding{
-		Encoding: charmap.Windows874,
-		Name:     "ISO-8859-11",
+	xThaiISO11 = named("ISO-8859-11", Name11, thai())
+	x34_1963   = named("ASA X3.4 1963", Name63, asa1963())
+	x34_1965   = named("ASA X3.4 1965", Name65, ascii(x3465))
+	x34_1967   = named("ANSI X3.4 1967/77/86", Name67, ascii(nil))
+)
+
+// The characters of the ASA encodings that differ from ANSI X3.4 1967.
+var (
+	x3463 = map[byte]rune{
+		0x5E: '↑', 0x5F: '←',
+		0x7C: '\x06', // ACK
+		0x7E: '\x1b', // ESC
+	}
+	x3465 = map[byte]rune{
+		0x40: '`', 0x5C: '~', 0x60: '@', 0x7C: '¬', 0x7E: '|',
+	}
+)
+
+// named returns the named encoding that decodes and encodes using the table.
+func named(name, value string, table [256]rune) Encoding {
+	return Encoding{
+		Encoding: mapping.New(name, value, table),
+		Name:     name,
 	}
+}
 
-	x34_1963 = Encoding{
-		Encoding: charmap.Windows1252,
-		Name:     "ASA X3.4 1963",
+// ascii returns a table of the 7-bit ASCII characters with the differences,
+// the 8-bit codes are unused.
+func ascii(diff map[byte]rune) [256]rune {
+	var table [256]rune
+	for i := range table {
+		table[i] = utf8.RuneError
 	}
-	x34_1965 = Encoding{
-		Encoding: charmap.Windows1252,
-		Name:     "ASA X3.4 1965",
+	for i := range 128 {
+		table[i] = rune(i)
 	}
-	x34_1967 = Encoding{
-		Encoding: charmap.Windows1252,
-		Name:     "ANSI X3.4 1967/77/86",
+	for b, r := range diff {
+		table[b] = r
 	}
-)
+	return table
+}
+
+// asa1963 returns the table for ASA X3.4 1963,
+// which has no lowercase letters.
+func asa1963() [256]rune {
+	table := ascii(x3463)
+	const lower, ack, unused = 0x60, 0x7C, 0x7D
+	for i := lower; i < ack; i++ {
+		table[i] = utf8.RuneError
+	}
+	table[unused] = utf8.RuneError
+	return table
+}
+
+// thai returns the table for ISO-8859-11, which uses the characters of Windows 874
+// except for the C1 controls in rows 8 and 9.
+// https://en.wikipedia.org/wiki/ISO/IEC_8859-11#Code_page_874_(IBM)_/_9066
+func thai() [256]rune {
+	var table [256]rune
+	for i := range table {
+		table[i] = charmap.Windows874.DecodeByte(byte(i))
+	}
+	const c1, c1end = 0x80, 0x9F
+	for i := c1; i <= c1end; i++ {
+		table[i] = rune(i)
+	}
+	return table
+}
 
 // String returns the formal name of the ASA encoding.
 func (e Encoding) String() string {
@@ -88,9 +146,39 @@ func CodePage(name string) encoding.Encoding {
 		return XUserDefined1965
 	case Name67, Numr67, Alias67:
 		return XUserDefined1967
-	default:
-		return nil
 	}
+	return codePageDOS(name)
+}
+
+// codePageDOS returns the DOS national code page of the name, numeric value or alias,
+// otherwise it returns the national or Euro EBCDIC code page.
+func codePageDOS(name string) encoding.Encoding {
+	switch strings.ToLower(name) {
+	case Name737, Numr737, Alias737:
+		return XUserDefined737
+	case Name775, Numr775, Alias775:
+		return XUserDefined775
+	case Name857, Numr857, Alias857:
+		return XUserDefined857
+	case Name861, Numr861, Alias861:
+		return XUserDefined861
+	case Name864, Numr864, Alias864:
+		return XUserDefined864
+	case Name869, Numr869, Alias869:
+		return XUserDefined869
+	case NameKamenicky, NumrKamenicky, AliasKamenicky, "kamenický":
+		return XUserDefinedKamenicky
+	case NameMazovia, NumrMazovia, AliasMazovia:
+		return XUserDefinedMazovia
+	case NameMIK, AliasMIK:
+		return XUserDefinedMIK
+	}
+	for _, e := range DOS() {
+		if strings.EqualFold(name, fmt.Sprint(e)) {
+			return e
+		}
+	}
+	return codePageEBCDIC(name)
 }
 
 // Code7bit reports whether the encoding is a 7-bit ASCII encoding.
@@ -115,8 +203,26 @@ func Name(e encoding.Encoding) string {
 		return Name65
 	case XUserDefined1967:
 		return Name67
+	case XUserDefined737:
+		return Name737
+	case XUserDefined775:
+		return Name775
+	case XUserDefined857:
+		return Name857
+	case XUserDefined861:
+		return Name861
+	case XUserDefined864:
+		return Name864
+	case XUserDefined869:
+		return Name869
+	case XUserDefinedKamenicky:
+		return NameKamenicky
+	case XUserDefinedMazovia:
+		return NameMazovia
+	case XUserDefinedMIK:
+		return NameMIK
 	}
-	return ""
+	return nameEBCDIC(e)
 }
 
 // Numeric returns a numeric value for the legacy ASA ASCII character encodings.
@@ -130,8 +236,24 @@ func Numeric(e encoding.Encoding) string {
 		return Numr65
 	case XUserDefined1967:
 		return Numr67
+	case XUserDefined737:
+		return Numr737
+	case XUserDefined775:
+		return Numr775
+	case XUserDefined857:
+		return Numr857
+	case XUserDefined861:
+		return Numr861
+	case XUserDefined864:
+		return Numr864
+	case XUserDefined869:
+		return Numr869
+	case XUserDefinedKamenicky:
+		return NumrKamenicky
+	case XUserDefinedMazovia:
+		return NumrMazovia
 	}
-	return ""
+	return numericEBCDIC(e)
 }
 
 // Alias returns an alias value for the legacy ASA ASCII character encodings.
@@ -141,8 +263,26 @@ func Alias(e encoding.Encoding) string {
 		return Alias11
 	case XUserDefined1967:
 		return Alias67
+	case XUserDefined737:
+		return Alias737
+	case XUserDefined775:
+		return Alias775
+	case XUserDefined857:
+		return Alias857
+	case XUserDefined861:
+		return Alias861
+	case XUserDefined864:
+		return Alias864
+	case XUserDefined869:
+		return Alias869
+	case XUserDefinedKamenicky:
+		return AliasKamenicky
+	case XUserDefinedMazovia:
+		return AliasMazovia
+	case XUserDefinedMIK:
+		return AliasMIK
 	}
-	return ""
+	return aliasEBCDIC(e)
 }
 
 // Footnote returns a footnote value for the legacy ASA ASCII character encodings.
@@ -158,6 +298,23 @@ func Footnote(w io.Writer, e encoding.Encoding) {
 	case XUserDefined1965:
 		fmt.Fprintln(w)
 		fmt.Fprintln(w, "* ASA X3.4 1965 cell 1-A is SUB, but it is not printable in Unicode.")
+	case XUserDefined857:
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* IBM Code Page 857 cells D-5, E-7 and F-2 are unused.")
+	case XUserDefined864:
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* IBM Code Page 864 cell 2-5 is the Arabic percent sign."+
+			"\n  Cells 9-B, 9-C, 9-F, A-6, A-7 and F-F are unused.")
+	case XUserDefined869:
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* IBM Code Page 869 cells 8-0 to 8-5, 8-7, 9-3 and 9-4 are unused.")
+	case XUserDefinedKamenicky:
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* Kamenický is also known as code page 895,"+
+			"\n  which IBM also uses for a Japanese code page.")
+	case XUserDefinedMazovia:
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "* Mazovia has a number of variants, this table is code page 667.")
 	}
 }
 
@@ -92,3 +92,141 @@ func TestCharX3467(t *testing.T) {
 	r = xud.Char(xud.XUserDefined1967, 130)
 	be.Equal(t, int32(32), r)
 }
+
+func ExampleDOS() {
+	for _, e := range xud.DOS() {
+		fmt.Printf("%s %s\n", xud.Name(e), e)
+	}
+	// Output: cp737 IBM Code Page 737
+	// cp775 IBM Code Page 775
+	// cp857 IBM Code Page 857
+	// cp861 IBM Code Page 861
+	// cp864 IBM Code Page 864
+	// cp869 IBM Code Page 869
+	// kamenicky Kamenický
+	// mazovia Mazovia
+	// mik MIK
+}
+
+func TestDOS(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		b    string
+		want string
+	}{
+		{"737", "\x80\x98\xe0 abc", "Ααω abc"},
+		{"775", "\x80\xb5\xf0", "ĆĄ­"},
+		{"857", "\x8d\x98\x9e", "ıİŞ"},
+		{"861", "\x8b\x8c\x8d\x95", "ÐðÞþ"},
+		{"864", "100%\xb1", "100٪١"},
+		{"869", "\xa4\xd6\x86", "ΑαΆ"},
+		{"kamenicky", "\x80\x9b\xa9\xdb", "ČŠř█"},
+		{"mazovia", "\x86\x92\x9e\xa1", "ąłśŻ"},
+		{"mik", "\x80\xbf\xd5\xe0", "Ая№α"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := xud.CodePage(tt.name)
+			be.True(t, xud.CodeDOS(e))
+			s, err := e.NewDecoder().String(tt.b)
+			be.Err(t, err, nil)
+			be.Equal(t, s, tt.want)
+			b, err := e.NewEncoder().String(tt.want)
+			be.Err(t, err, nil)
+			be.Equal(t, b, tt.b)
+		})
+	}
+}
+
+func TestCodePage(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, xud.CodePage("CP737"), xud.XUserDefined737)
+	be.Equal(t, xud.CodePage("ibm861"), xud.XUserDefined861)
+	be.Equal(t, xud.CodePage("Kamenický"), xud.XUserDefinedKamenicky)
+	be.Equal(t, xud.CodePage("IBM Code Page 869"), xud.XUserDefined869)
+	be.Equal(t, xud.CodePage("keybcs2"), xud.XUserDefinedKamenicky)
+	be.Equal(t, xud.CodePage("667"), xud.XUserDefinedMazovia)
+	be.Equal(t, xud.CodePage("cp437"), nil)
+	be.True(t, !xud.CodeDOS(xud.XUserDefined1963))
+}
+
+func TestEBCDIC(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		b    string
+		want string
+	}{
+		{"273", "\x4a\x5a\x6a\xa1\x7c\x40\x81", "ÄÜöß§ a"},
+		{"277", "\x5b\x7b\x7c\x6a\x40\x81", "ÅÆØø a"},
+		{"278", "\x5b\x7b\x7c\x79\xe0\x71", "ÅÄÖéÉ\\"},
+		{"280", "\x6a\x79\xc0\xd0", "òùàè"},
+		{"284", "\x6a\x7b\x4a\x5a", "ñÑ[]"},
+		{"285", "\x5b\x4a\x9f", "£$¤"},
+		{"297", "\x7c\xc0\xe0\x4a", "àéç°"},
+		{"500", "\x4a\x5a\x4f", "[]!"},
+		{"871", "\x4a\xc0\x79\x7c", "ÞþðÐ"},
+		{"1141", "\x4a\x9f", "Ä€"},
+		{"1142", "\x5b\x5a\x9f", "Å€]"},
+		{"1143", "\x5b\x5a", "Å€"},
+		{"1144", "\x6a\x9f", "ò€"},
+		{"1145", "\x6a\x9f", "ñ€"},
+		{"1146", "\x5b\x9f", "£€"},
+		{"1147", "\x7c\x9f", "à€"},
+		{"1148", "\x4a\x9f", "[€"},
+		{"ibm01149", "\x4a\x9f", "Þ€"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := xud.CodePage(tt.name)
+			be.True(t, xud.CodeEBCDIC(e))
+			be.True(t, !xud.CodeDOS(e))
+			s, err := e.NewDecoder().String(tt.b)
+			be.Err(t, err, nil)
+			be.Equal(t, s, tt.want)
+			b, err := e.NewEncoder().String(tt.want)
+			be.Err(t, err, nil)
+			be.Equal(t, b, tt.b)
+		})
+	}
+	be.Equal(t, len(xud.EBCDIC()), 18)
+	be.Equal(t, xud.Name(xud.XUserDefined1141), "cp1141")
+	be.Equal(t, xud.Numeric(xud.XUserDefined273), "273")
+	be.Equal(t, xud.Alias(xud.XUserDefined1149), "ibm01149")
+	be.Equal(t, xud.CodePage("IBM Code Page 500"), xud.XUserDefined500)
+}
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		b    string
+		want string
+	}{
+		{"ascii-63", "HELLO\x5e\x5f\r\n", "HELLO↑←\r\n"},
+		{"ascii-65", "@\\|~", "`~¬|"},
+		{"ascii-67", "@\\|~ hello", "@\\|~ hello"},
+		{"iso-8859-11", "\xa1\xe0\x85", "กเ\u0085"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			e := xud.CodePage(tt.name)
+			s, err := e.NewDecoder().String(tt.b)
+			be.Err(t, err, nil)
+			be.Equal(t, s, tt.want)
+			b, err := e.NewEncoder().String(tt.want)
+			be.Err(t, err, nil)
+			be.Equal(t, b, tt.b)
+		})
+	}
+	// the ASA encodings are 7-bit and 1963 has no lowercase letters
+	s, err := xud.XUserDefined1963.NewDecoder().String("a\x80")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "��")
+	_, err = xud.XUserDefined1963.NewEncoder().String("a")
+	be.True(t, err != nil)
+}